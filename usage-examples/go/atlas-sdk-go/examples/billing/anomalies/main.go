@@ -0,0 +1,84 @@
+// :snippet-start: billing-anomalies
+// :state-remove-start: copy
+// See entire project at https://github.com/mongodb/atlas-architecture-go-sdk
+// :state-remove-end: [copy]
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"atlas-sdk-go/internal/auth"
+	"atlas-sdk-go/internal/billing"
+	"atlas-sdk-go/internal/billing/anomaly"
+	"atlas-sdk-go/internal/config"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	envFile := ".env.production"
+	if err := godotenv.Load(envFile); err != nil {
+		log.Printf("Warning: could not load %s file: %v", envFile, err)
+	}
+
+	secrets, cfg, err := config.LoadAllFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to load configuration %v", err)
+	}
+
+	classifier, err := billing.LoadSKUClassifier(cfg.SKURulesPath)
+	if err != nil {
+		log.Fatalf("Failed to load SKU rules: %v", err)
+	}
+
+	ctx := context.Background()
+	client, err := auth.NewClient(ctx, cfg, secrets)
+	if err != nil {
+		log.Fatalf("Failed to initialize authentication client: %v", err)
+	}
+
+	orgID := cfg.OrgID
+	fmt.Printf("Checking the last 30 days of spend for organization: %s\n", orgID)
+
+	// Collect enough history to build a baseline, including the previous month's invoice
+	details, err := billing.CollectInvoiceLineItems(ctx, client.InvoicesApi, client.OrganizationsApi, orgID, 4, classifier,
+		billing.WithDateRange(time.Now().AddDate(0, 0, -30), time.Now()))
+	if err != nil {
+		log.Fatalf("Failed to retrieve line items for %s: %v", orgID, err)
+	}
+
+	// Flag days that cost at least $25 more than usual and score 3.5 or more
+	// against the median of the previous week
+	opts := anomaly.DefaultOptions()
+	opts.MinIncrease = 25
+	anomalies, err := anomaly.Detect(details, opts)
+	if err != nil {
+		log.Fatalf("Failed to detect anomalies: %v", err)
+	}
+
+	if len(anomalies) == 0 {
+		fmt.Println("No spend anomalies detected")
+		return
+	}
+	fmt.Printf("Found %d spend anomalies:\n", len(anomalies))
+	for _, a := range anomalies {
+		fmt.Printf("  [%s] %s %s/%s: %s\n",
+			a.Date.Format("2006-01-02"), a.Kind, a.Project, a.Cluster, a.Explanation)
+	}
+}
+
+// :snippet-end: [billing-anomalies]
+// :state-remove-start: copy
+// NOTE: INTERNAL
+// ** OUTPUT EXAMPLE **
+//
+// Checking the last 30 days of spend for organization: 5f7a9ec7d78fc03b42959328
+// Processing invoice ID: 66b3a1f2e4b0c81d2f5a9c10
+// Processing invoice ID: 6689c4d1e4b0c81d2f5a8b07
+// Found 2 spend anomalies:
+//   [2024-08-06] spike prod/load-test: Clusters cost $412.80 vs baseline $0.00 (+$412.80, score 41280.0); driven by ATLAS_AWS_INSTANCE_M200 +$412.80
+//   [2024-08-06] new_sku prod/load-test: new SKU ATLAS_AWS_INSTANCE_M200 started billing $412.80/day in Clusters
+// :state-remove-end: [copy]
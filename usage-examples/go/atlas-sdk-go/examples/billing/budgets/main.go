@@ -0,0 +1,99 @@
+// :snippet-start: budget-forecast
+// :state-remove-start: copy
+// See entire project at https://github.com/mongodb/atlas-architecture-go-sdk
+// :state-remove-end: [copy]
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"atlas-sdk-go/internal/auth"
+	"atlas-sdk-go/internal/billing"
+	"atlas-sdk-go/internal/billing/budget"
+	"atlas-sdk-go/internal/config"
+	"atlas-sdk-go/internal/data/export"
+	"atlas-sdk-go/internal/fileutils"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	envFile := ".env.production"
+	if err := godotenv.Load(envFile); err != nil {
+		log.Printf("Warning: could not load %s file: %v", envFile, err)
+	}
+
+	secrets, cfg, err := config.LoadAllFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to load configuration %v", err)
+	}
+
+	classifier, err := billing.LoadSKUClassifier(cfg.SKURulesPath)
+	if err != nil {
+		log.Fatalf("Failed to load SKU rules: %v", err)
+	}
+	if len(cfg.Budgets) == 0 {
+		log.Fatal("No budgets defined in configuration; add a \"budgets\" section to your config file")
+	}
+
+	ctx := context.Background()
+	client, err := auth.NewClient(ctx, cfg, secrets)
+	if err != nil {
+		log.Fatalf("Failed to initialize authentication client: %v", err)
+	}
+
+	orgID := cfg.OrgID
+	fmt.Printf("Forecasting month-end spend for organization: %s\n", orgID)
+
+	// The pending invoice holds the month-to-date line items
+	details, err := billing.CollectLineItemBillingData(ctx, client.InvoicesApi, client.OrganizationsApi, orgID, nil, classifier)
+	if err != nil {
+		log.Fatalf("Failed to retrieve pending invoices for %s: %v", orgID, err)
+	}
+
+	forecasts, err := budget.ForecastAll(details, cfg.Budgets, time.Now())
+	if err != nil {
+		log.Fatalf("Failed to forecast budgets: %v", err)
+	}
+
+	fmt.Printf("\n=== Budget Forecast (%d budgets) ===\n", len(forecasts))
+	for _, f := range forecasts {
+		fmt.Println(f.Summary())
+	}
+
+	// Export forecasts as JSON for automation (e.g. alerting on "overrun" or "exceeded" status)
+	outDir := "invoices"
+	jsonPath, err := fileutils.GenerateOutputPath(outDir, fmt.Sprintf("budgets_%s", orgID), "json")
+	if err != nil {
+		log.Fatalf("Failed to generate JSON output path: %v", err)
+	}
+	if err := export.ToJSON(forecasts, jsonPath); err != nil {
+		log.Fatalf("Failed to write JSON file: %v", err)
+	}
+	fmt.Printf("\nExported budget forecast to %s\n", jsonPath)
+	// :remove-start:
+	// Clean up (internal-only function)
+	if err = fileutils.SafeDelete(outDir); err != nil {
+		log.Printf("Cleanup error: %v", err)
+	}
+	fmt.Println("Deleted generated files from", outDir)
+	// :remove-end:
+}
+
+// :snippet-end: [budget-forecast]
+// :state-remove-start: copy
+// NOTE: INTERNAL
+// ** OUTPUT EXAMPLE **
+//
+// Forecasting month-end spend for organization: 5f7a9ec7d78fc03b42959328
+// Processing invoice ID: 66b3a1f2e4b0c81d2f5a9c10
+//
+// === Budget Forecast (2 budgets) ===
+// Org total (2024-08): $1850.40 of $5000.00 spent (37.0%), projected $5592.10 (111.8%) - projected overrun $592.10, limit crossed around 2024-08-28
+// Backups (2024-08): $61.20 of $300.00 spent (20.4%), projected $185.00 (61.7%) - on track
+//
+// Exported budget forecast to invoices/budgets_5f7a9ec7d78fc03b42959328.json
+// :state-remove-end: [copy]
@@ -0,0 +1,142 @@
+// :snippet-start: chargeback
+// :state-remove-start: copy
+// See entire project at https://github.com/mongodb/atlas-architecture-go-sdk
+// :state-remove-end: [copy]
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"atlas-sdk-go/internal/auth"
+	"atlas-sdk-go/internal/billing"
+	"atlas-sdk-go/internal/billing/chargeback"
+	"atlas-sdk-go/internal/config"
+	"atlas-sdk-go/internal/fileutils"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	envFile := ".env.production"
+	if err := godotenv.Load(envFile); err != nil {
+		log.Printf("Warning: could not load %s file: %v", envFile, err)
+	}
+
+	secrets, cfg, err := config.LoadAllFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to load configuration %v", err)
+	}
+
+	classifier, err := billing.LoadSKUClassifier(cfg.SKURulesPath)
+	if err != nil {
+		log.Fatalf("Failed to load SKU rules: %v", err)
+	}
+
+	mappingPath := os.Getenv("CHARGEBACK_MAPPING_PATH")
+	if mappingPath == "" {
+		mappingPath = "configs/chargeback.json"
+	}
+	mapping, err := chargeback.LoadMapping(mappingPath)
+	if err != nil {
+		log.Fatalf("Failed to load chargeback mapping: %v", err)
+	}
+
+	ctx := context.Background()
+	client, err := auth.NewClient(ctx, cfg, secrets)
+	if err != nil {
+		log.Fatalf("Failed to initialize authentication client: %v", err)
+	}
+
+	orgID := cfg.OrgID
+	fmt.Printf("Allocating costs to teams for organization: %s\n", orgID)
+
+	details, err := billing.CollectLineItemBillingData(ctx, client.InvoicesApi, client.OrganizationsApi, orgID, nil, classifier)
+	if err != nil {
+		log.Fatalf("Failed to retrieve pending invoices for %s: %v", orgID, err)
+	}
+
+	report, err := chargeback.Allocate(details, mapping)
+	if err != nil {
+		log.Fatalf("Failed to allocate costs: %v", err)
+	}
+
+	fmt.Printf("\n=== Chargeback Summary ===\n")
+	for _, row := range report.Table() {
+		fmt.Printf("%-14s %-10s %12s %12s %12s %8s\n", row[0], row[1], row[2], row[3], row[4], row[5])
+	}
+
+	for _, s := range report.Statements {
+		fmt.Printf("\n--- %s (%s) ---\n", s.Team, s.CostCentre)
+		for _, row := range s.Table()[1:] {
+			fmt.Printf("  %-40s %12s\n", row[0], row[1])
+		}
+	}
+
+	// Unallocated cost is reported explicitly so that gaps in the mapping can be fixed
+	if len(report.Unallocated.Items) > 0 {
+		fmt.Printf("\n=== Unallocated ($%.2f) ===\n", report.Unallocated.Total)
+		for _, item := range report.Unallocated.Items {
+			fmt.Printf("  project=%q cluster=%q category=%q $%.2f (%s)\n",
+				item.Project, item.Cluster, item.Category, item.Cost, item.Reason)
+		}
+	}
+
+	outDir := "invoices"
+	prefix := fmt.Sprintf("chargeback_%s", orgID)
+	jsonPath, err := fileutils.GenerateOutputPath(outDir, prefix, "json")
+	if err != nil {
+		log.Fatalf("Failed to generate JSON output path: %v", err)
+	}
+	if err := report.WriteJSON(jsonPath); err != nil {
+		log.Fatalf("Failed to write JSON file: %v", err)
+	}
+	csvPath, err := fileutils.GenerateOutputPath(outDir, prefix, "csv")
+	if err != nil {
+		log.Fatalf("Failed to generate CSV output path: %v", err)
+	}
+	if err := report.WriteCSV(csvPath); err != nil {
+		log.Fatalf("Failed to write CSV file: %v", err)
+	}
+	fmt.Printf("\nExported chargeback statements to %s and %s\n", jsonPath, csvPath)
+	// :remove-start:
+	// Clean up (internal-only function)
+	if err = fileutils.SafeDelete(outDir); err != nil {
+		log.Printf("Cleanup error: %v", err)
+	}
+	fmt.Println("Deleted generated files from", outDir)
+	// :remove-end:
+}
+
+// :snippet-end: [chargeback]
+// :state-remove-start: copy
+// NOTE: INTERNAL
+// ** OUTPUT EXAMPLE **
+//
+// Allocating costs to teams for organization: 5f7a9ec7d78fc03b42959328
+// Processing invoice ID: 66b3a1f2e4b0c81d2f5a9c10
+//
+// === Chargeback Summary ===
+// Team           Cost Centre       Direct       Shared        Total    Share
+// payments       CC-1001          1204.50        96.36      1300.86    62.4%
+// search         CC-1002           512.25        44.11       556.36    26.7%
+// platform       CC-1000           180.00        18.40       198.40     9.5%
+// UNALLOCATED                        29.70         0.00        29.70     1.4%
+// TOTAL                                                     2085.32   100.0%
+//
+// --- payments (CC-1001) ---
+//   Direct: Backup                                  84.50
+//   Direct: Clusters                              1120.00
+//   Shared: Data Transfer                           12.40
+//   Shared: Premium Features                        15.00
+//   Shared: Support                                 68.96
+//   Total                                         1300.86
+// ...
+//
+// === Unallocated ($29.70) ===
+//   project="sandbox" cluster="Cluster0" category="Clusters" $29.70 (no matching team)
+//
+// Exported chargeback statements to invoices/chargeback_5f7a9ec7d78fc03b42959328.json and invoices/chargeback_5f7a9ec7d78fc03b42959328.csv
+// :state-remove-end: [copy]
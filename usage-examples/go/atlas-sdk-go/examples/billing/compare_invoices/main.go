@@ -0,0 +1,146 @@
+// :snippet-start: compare-invoices
+// :state-remove-start: copy
+// See entire project at https://github.com/mongodb/atlas-architecture-go-sdk
+// :state-remove-end: [copy]
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+
+	"atlas-sdk-go/internal/auth"
+	"atlas-sdk-go/internal/billing"
+	"atlas-sdk-go/internal/billing/compare"
+	"atlas-sdk-go/internal/config"
+	"atlas-sdk-go/internal/fileutils"
+
+	"github.com/joho/godotenv"
+	"go.mongodb.org/atlas-sdk/v20250219001/admin"
+)
+
+func main() {
+	baselineID := flag.String("baseline", "", "Invoice ID to compare against (default: most recent closed invoice)")
+	currentID := flag.String("current", "pending", `Invoice ID to compare, or "pending" for the pending (month-to-date) invoice`)
+	top := flag.Int("top", 20, "Number of items to print")
+	flag.Parse()
+
+	envFile := ".env.production"
+	if err := godotenv.Load(envFile); err != nil {
+		log.Printf("Warning: could not load %s file: %v", envFile, err)
+	}
+
+	secrets, cfg, err := config.LoadAllFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to load configuration %v", err)
+	}
+
+	classifier, err := billing.LoadSKUClassifier(cfg.SKURulesPath)
+	if err != nil {
+		log.Fatalf("Failed to load SKU rules: %v", err)
+	}
+
+	ctx := context.Background()
+	client, err := auth.NewClient(ctx, cfg, secrets)
+	if err != nil {
+		log.Fatalf("Failed to initialize authentication client: %v", err)
+	}
+
+	orgID := cfg.OrgID
+	if *baselineID == "" {
+		// Find the most recent closed invoice
+		r, err := billing.ListInvoicesForOrg(ctx, client.InvoicesApi, &admin.ListInvoicesApiParams{OrgId: orgID},
+			billing.WithStatusNames([]string{"CLOSED", "PAID", "INVOICED"}),
+			billing.WithSortBy("START_DATE"),
+			billing.WithOrderBy("desc"),
+			billing.WithItemsPerPage(1))
+		if err != nil {
+			log.Fatalf("Failed to list invoices for %s: %v", orgID, err)
+		}
+		if len(r.GetResults()) == 0 {
+			log.Fatalf("No closed invoices found for organization %s; pass -baseline", orgID)
+		}
+		*baselineID = r.GetResults()[0].GetId()
+	}
+
+	fmt.Printf("Comparing invoice %s with %s for organization: %s\n", *baselineID, *currentID, orgID)
+	baseline, err := billing.CollectInvoiceDetails(ctx, client.InvoicesApi, client.OrganizationsApi, orgID, *baselineID, classifier)
+	if err != nil {
+		log.Fatalf("Failed to retrieve invoice %s: %v", *baselineID, err)
+	}
+
+	var current []billing.Detail
+	partial := *currentID == "pending"
+	if partial {
+		current, err = billing.CollectLineItemBillingData(ctx, client.InvoicesApi, client.OrganizationsApi, orgID, nil, classifier)
+	} else {
+		current, err = billing.CollectInvoiceDetails(ctx, client.InvoicesApi, client.OrganizationsApi, orgID, *currentID, classifier)
+	}
+	if err != nil {
+		log.Fatalf("Failed to retrieve invoice %s: %v", *currentID, err)
+	}
+
+	report := compare.Compare(baseline, current, *baselineID, *currentID)
+
+	currentLabel := ""
+	if partial {
+		// The pending invoice only covers the month to date, so it is compared with a full month
+		fmt.Println("\nWARNING: the pending invoice is month-to-date; its totals are partial and most items will show as decreases.")
+		fmt.Println("Pass -current with a closed invoice ID to compare two full months.")
+		currentLabel = " (partial, month to date)"
+	}
+	fmt.Printf("\nTotal: $%.2f -> $%.2f%s (%+.2f), volume %+.2f, price %+.2f, unattributed %+.2f\n",
+		report.BaselineTotal, report.CurrentTotal, currentLabel, report.Change,
+		report.VolumeEffect, report.PriceEffect, report.Unattributed)
+	fmt.Printf("%d new and %d removed line items\n\n", report.NewItems, report.RemovedItems)
+
+	printed := 0
+	for _, it := range report.Items {
+		if it.Status == compare.Unchanged || printed == *top {
+			continue
+		}
+		printed++
+		fmt.Printf("%-9s %-16s %-16s %-36s %10.2f -> %10.2f (%+.2f)\n",
+			it.Status, it.Project, it.Cluster, it.SKU, it.BaselineCost, it.CurrentCost, it.Change)
+	}
+
+	outDir := "invoices"
+	csvPath, err := fileutils.GenerateOutputPath(outDir, fmt.Sprintf("compare_%s", orgID), "csv")
+	if err != nil {
+		log.Fatalf("Failed to generate CSV output path: %v", err)
+	}
+	if err := report.WriteCSV(csvPath); err != nil {
+		log.Fatalf("Failed to write CSV file: %v", err)
+	}
+	fmt.Printf("\nExported comparison to %s\n", csvPath)
+	// :remove-start:
+	// Clean up (internal-only function)
+	if err = fileutils.SafeDelete(outDir); err != nil {
+		log.Printf("Cleanup error: %v", err)
+	}
+	fmt.Println("Deleted generated files from", outDir)
+	// :remove-end:
+}
+
+// :snippet-end: [compare-invoices]
+// :state-remove-start: copy
+// NOTE: INTERNAL
+// ** OUTPUT EXAMPLE **
+//
+// Comparing invoice 6689d2a1e4b0c81d2f5a1b22 with pending for organization: 5f7a9ec7d78fc03b42959328
+// Processing invoice ID: 6689d2a1e4b0c81d2f5a1b22
+// Processing invoice ID: 66b3a1f2e4b0c81d2f5a9c10
+//
+// WARNING: the pending invoice is month-to-date; its totals are partial and most items will show as decreases.
+// Pass -current with a closed invoice ID to compare two full months.
+//
+// Total: $2410.55 -> $2688.20 (partial, month to date) (+277.65), volume +190.40, price +80.25, unattributed +7.00
+// 1 new and 0 removed line items
+//
+// new       analytics        reporting        NDS_AWS_INSTANCE_M30                       0.00 ->     112.32 (+112.32)
+// changed   payments-prod    Cluster0         NDS_AWS_INSTANCE_M50                    1123.20 ->    1203.45 (+80.25)
+// changed   payments-prod    Cluster0         NDS_AWS_STORAGE_STANDARD                  68.00 ->      75.00 (+7.00)
+//
+// Exported comparison to invoices/compare_5f7a9ec7d78fc03b42959328.csv
+// :state-remove-end: [copy]
@@ -0,0 +1,113 @@
+// :snippet-start: cost-rollup
+// :state-remove-start: copy
+// See entire project at https://github.com/mongodb/atlas-architecture-go-sdk
+// :state-remove-end: [copy]
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"atlas-sdk-go/internal/auth"
+	"atlas-sdk-go/internal/billing"
+	"atlas-sdk-go/internal/billing/rollup"
+	"atlas-sdk-go/internal/config"
+	"atlas-sdk-go/internal/fileutils"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	envFile := ".env.production"
+	if err := godotenv.Load(envFile); err != nil {
+		log.Printf("Warning: could not load %s file: %v", envFile, err)
+	}
+
+	secrets, cfg, err := config.LoadAllFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to load configuration %v", err)
+	}
+
+	classifier, err := billing.LoadSKUClassifier(cfg.SKURulesPath)
+	if err != nil {
+		log.Fatalf("Failed to load SKU rules: %v", err)
+	}
+
+	ctx := context.Background()
+	client, err := auth.NewClient(ctx, cfg, secrets)
+	if err != nil {
+		log.Fatalf("Failed to initialize authentication client: %v", err)
+	}
+
+	orgID := cfg.OrgID
+	fmt.Printf("Fetching pending invoices for organization: %s\n", orgID)
+
+	details, err := billing.CollectLineItemBillingData(ctx, client.InvoicesApi, client.OrganizationsApi, orgID, nil, classifier)
+	if err != nil {
+		log.Fatalf("Failed to retrieve pending invoices for %s: %v", orgID, err)
+	}
+	if len(details) == 0 {
+		fmt.Printf("No pending invoices found for organization: %s\n", orgID)
+		return
+	}
+
+	// Pivot cost by project and category, with one column per week
+	report, err := rollup.Build(details, rollup.Options{
+		GroupBy: []rollup.Dimension{rollup.Project, rollup.Category},
+		Period:  rollup.Week,
+	})
+	if err != nil {
+		log.Fatalf("Failed to build cost rollup: %v", err)
+	}
+	fmt.Printf("Rolled up %d line items into %d rows across %d periods (total: $%.2f)\n\n",
+		len(details), len(report.Lines), len(report.Periods), report.Total)
+	fmt.Println(report.Markdown())
+
+	// Export the pivot for finance reporting
+	outDir := "invoices"
+	prefix := fmt.Sprintf("rollup_%s", orgID)
+	writers := map[string]func(string) error{
+		"csv":  report.WriteCSV,
+		"json": report.WriteJSON,
+		"md":   report.WriteMarkdown,
+	}
+	for _, ext := range []string{"csv", "json", "md"} {
+		path, err := fileutils.GenerateOutputPath(outDir, prefix, ext)
+		if err != nil {
+			log.Fatalf("Failed to generate %s output path: %v", ext, err)
+		}
+		if err := writers[ext](path); err != nil {
+			log.Fatalf("Failed to write %s file: %v", ext, err)
+		}
+		fmt.Printf("Exported cost rollup to %s\n", path)
+	}
+	// :remove-start:
+	// Clean up (internal-only function)
+	if err = fileutils.SafeDelete(outDir); err != nil {
+		log.Printf("Cleanup error: %v", err)
+	}
+	fmt.Println("Deleted generated files from", outDir)
+	// :remove-end:
+}
+
+// :snippet-end: [cost-rollup]
+// :state-remove-start: copy
+// NOTE: INTERNAL
+// ** OUTPUT EXAMPLE **
+//
+// Fetching pending invoices for organization: 5f7a9ec7d78fc03b42959328
+// Processing invoice ID: 66b3a1f2e4b0c81d2f5a9c10
+// Rolled up 42 line items into 3 rows across 2 periods (total: $412.80)
+//
+// | project | category | 2024-W31 | 2024-W32 | Total | Share | Change | Change % |
+// | --- | --- | ---: | ---: | ---: | ---: | ---: | ---: |
+// | prod | Clusters | 150.00 | 180.00 | 330.00 | 79.9% | 30.00 | +20.0% |
+// | prod | Backup | 30.40 | 32.40 | 62.80 | 15.2% | 2.00 | +6.6% |
+// | dev | Clusters | 10.00 | 10.00 | 20.00 | 4.8% | 0.00 | +0.0% |
+// | TOTAL |  | 190.40 | 222.40 | 412.80 | 100.0% | 32.00 | +16.8% |
+//
+// Exported cost rollup to invoices/rollup_5f7a9ec7d78fc03b42959328.csv
+// Exported cost rollup to invoices/rollup_5f7a9ec7d78fc03b42959328.json
+// Exported cost rollup to invoices/rollup_5f7a9ec7d78fc03b42959328.md
+// :state-remove-end: [copy]
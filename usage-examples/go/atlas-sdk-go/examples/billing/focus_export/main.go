@@ -0,0 +1,90 @@
+// :snippet-start: focus-export
+// :state-remove-start: copy
+// See entire project at https://github.com/mongodb/atlas-architecture-go-sdk
+// :state-remove-end: [copy]
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"atlas-sdk-go/internal/auth"
+	"atlas-sdk-go/internal/billing"
+	"atlas-sdk-go/internal/billing/focus"
+	"atlas-sdk-go/internal/config"
+	"atlas-sdk-go/internal/fileutils"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	envFile := ".env.production"
+	if err := godotenv.Load(envFile); err != nil {
+		log.Printf("Warning: could not load %s file: %v", envFile, err)
+	}
+
+	secrets, cfg, err := config.LoadAllFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to load configuration %v", err)
+	}
+
+	classifier, err := billing.LoadSKUClassifier(cfg.SKURulesPath)
+	if err != nil {
+		log.Fatalf("Failed to load SKU rules: %v", err)
+	}
+
+	ctx := context.Background()
+	client, err := auth.NewClient(ctx, cfg, secrets)
+	if err != nil {
+		log.Fatalf("Failed to initialize authentication client: %v", err)
+	}
+
+	orgID := cfg.OrgID
+	fmt.Printf("Exporting FOCUS %s cost data for organization: %s\n", focus.Version, orgID)
+
+	// Export the previous month's closed invoices
+	now := time.Now().UTC()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	details, err := billing.CollectInvoiceLineItems(ctx, client.InvoicesApi, client.OrganizationsApi, orgID,
+		billing.DefaultInvoiceWorkers, classifier,
+		billing.WithStatusNames([]string{"CLOSED", "PAID", "INVOICED"}),
+		billing.WithDateRange(monthStart.AddDate(0, -1, 0), monthStart))
+	if err != nil {
+		log.Fatalf("Failed to retrieve invoice line items for %s: %v", orgID, err)
+	}
+	if len(details) == 0 {
+		fmt.Printf("No closed invoices found for organization: %s\n", orgID)
+		return
+	}
+
+	rows := focus.Convert(details)
+
+	outDir := "invoices"
+	csvPath, err := fileutils.GenerateOutputPath(outDir, fmt.Sprintf("focus_%s", orgID), "csv")
+	if err != nil {
+		log.Fatalf("Failed to generate CSV output path: %v", err)
+	}
+	if err := focus.WriteCSV(rows, csvPath); err != nil {
+		log.Fatalf("Failed to write FOCUS CSV file: %v", err)
+	}
+	fmt.Printf("Exported %d FOCUS rows to %s\n", len(rows), csvPath)
+	// :remove-start:
+	// Clean up (internal-only function)
+	if err = fileutils.SafeDelete(outDir); err != nil {
+		log.Printf("Cleanup error: %v", err)
+	}
+	fmt.Println("Deleted generated files from", outDir)
+	// :remove-end:
+}
+
+// :snippet-end: [focus-export]
+// :state-remove-start: copy
+// NOTE: INTERNAL
+// ** OUTPUT EXAMPLE **
+//
+// Exporting FOCUS 1.0 cost data for organization: 5f7a9ec7d78fc03b42959328
+// Processing invoice ID: 6689d2a1e4b0c81d2f5a1b22
+// Exported 412 FOCUS rows to invoices/focus_5f7a9ec7d78fc03b42959328.csv
+// :state-remove-end: [copy]
@@ -41,8 +41,8 @@ func main() {
 	}
 	fmt.Printf("Fetching historical invoices for organization: %s\n", p.OrgId)
 
-	// Fetch invoices from the previous six months with the provided options
-	invoices, err := billing.ListInvoicesForOrg(ctx, client.InvoicesApi, p,
+	// Fetch every page of invoices from the previous six months with the provided options
+	invoices, err := billing.ListAllInvoicesForOrg(ctx, client.InvoicesApi, p,
 		billing.WithViewLinkedInvoices(true),
 		billing.WithIncludeCount(true),
 		billing.WithDateRange(time.Now().AddDate(0, -6, 0), time.Now()))
@@ -50,8 +50,8 @@ func main() {
 		log.Fatalf("Failed to retrieve invoices: %v", err)
 	}
 
-	if invoices.GetTotalCount() > 0 {
-		fmt.Printf("Total count of invoices: %d\n", invoices.GetTotalCount())
+	if len(invoices) > 0 {
+		fmt.Printf("Total count of invoices: %d\n", len(invoices))
 	} else {
 		fmt.Println("No invoices found for the specified date range")
 		return
@@ -78,19 +78,19 @@ func main() {
 	// :remove-end:
 }
 
-func exportInvoicesToJSON(invoices *admin.PaginatedApiInvoiceMetadata, outDir, prefix string) error {
+func exportInvoicesToJSON(invoices []admin.BillingInvoiceMetadata, outDir, prefix string) error {
 	jsonPath, err := fileutils.GenerateOutputPath(outDir, prefix, "json")
 	if err != nil {
 		return fmt.Errorf("failed to generate JSON output path: %v", err)
 	}
-	if err := export.ToJSON(invoices.GetResults(), jsonPath); err != nil {
+	if err := export.ToJSON(invoices, jsonPath); err != nil {
 		return fmt.Errorf("failed to write JSON file: %v", err)
 	}
 	fmt.Printf("Exported invoice data to %s\n", jsonPath)
 	return nil
 }
 
-func exportInvoicesToCSV(invoices *admin.PaginatedApiInvoiceMetadata, outDir, prefix string) error {
+func exportInvoicesToCSV(invoices []admin.BillingInvoiceMetadata, outDir, prefix string) error {
 	csvPath, err := fileutils.GenerateOutputPath(outDir, prefix, "csv")
 	if err != nil {
 		return fmt.Errorf("failed to generate CSV output path: %v", err)
@@ -98,7 +98,7 @@ func exportInvoicesToCSV(invoices *admin.PaginatedApiInvoiceMetadata, outDir, pr
 
 	// Set the headers and mapped rows for the CSV export
 	headers := []string{"InvoiceID", "Status", "Created", "AmountBilled"}
-	err = export.ToCSVWithMapper(invoices.GetResults(), csvPath, headers, func(invoice admin.BillingInvoiceMetadata) []string {
+	err = export.ToCSVWithMapper(invoices, csvPath, headers, func(invoice admin.BillingInvoiceMetadata) []string {
 		return []string{
 			invoice.GetId(),
 			invoice.GetStatusName(),
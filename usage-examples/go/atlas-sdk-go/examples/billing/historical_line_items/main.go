@@ -0,0 +1,94 @@
+// :snippet-start: historical-line-items
+// :state-remove-start: copy
+// See entire project at https://github.com/mongodb/atlas-architecture-go-sdk
+// :state-remove-end: [copy]
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"atlas-sdk-go/internal/auth"
+	"atlas-sdk-go/internal/billing"
+	"atlas-sdk-go/internal/config"
+	"atlas-sdk-go/internal/data/export"
+	"atlas-sdk-go/internal/fileutils"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	envFile := ".env.production"
+	if err := godotenv.Load(envFile); err != nil {
+		log.Printf("Warning: could not load %s file: %v", envFile, err)
+	}
+
+	secrets, cfg, err := config.LoadAllFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to load configuration %v", err)
+	}
+
+	classifier, err := billing.LoadSKUClassifier(cfg.SKURulesPath)
+	if err != nil {
+		log.Fatalf("Failed to load SKU rules: %v", err)
+	}
+
+	ctx := context.Background()
+	client, err := auth.NewClient(ctx, cfg, secrets)
+	if err != nil {
+		log.Fatalf("Failed to initialize authentication client: %v", err)
+	}
+
+	orgID := cfg.OrgID
+	fmt.Printf("Fetching closed invoice line items for organization: %s\n", orgID)
+
+	// Collect line items for every closed or paid invoice from the previous three months,
+	// fetching up to four invoices at a time
+	details, err := billing.CollectInvoiceLineItems(ctx, client.InvoicesApi, client.OrganizationsApi, orgID, 4, classifier,
+		billing.WithStatusNames([]string{"CLOSED", "PAID"}),
+		billing.WithDateRange(time.Now().AddDate(0, -3, 0), time.Now()))
+	if err != nil {
+		log.Fatalf("Failed to retrieve invoice line items for %s: %v", orgID, err)
+	}
+
+	if len(details) == 0 {
+		fmt.Printf("No closed invoices found for organization: %s\n", orgID)
+		return
+	}
+	fmt.Printf("Found %d line items in closed invoices\n", len(details))
+
+	// Export line items for month-end reconciliation
+	outDir := "invoices"
+	prefix := fmt.Sprintf("closed_%s", orgID)
+
+	jsonPath, err := fileutils.GenerateOutputPath(outDir, prefix, "json")
+	if err != nil {
+		log.Fatalf("Failed to generate JSON output path: %v", err)
+	}
+	if err := export.ToJSON(details, jsonPath); err != nil {
+		log.Fatalf("Failed to write JSON file: %v", err)
+	}
+	fmt.Printf("Exported billing data to %s\n", jsonPath)
+	// :remove-start:
+	// Clean up (internal-only function)
+	if err = fileutils.SafeDelete(outDir); err != nil {
+		log.Printf("Cleanup error: %v", err)
+	}
+	fmt.Println("Deleted generated files from", outDir)
+	// :remove-end:
+}
+
+// :snippet-end: [historical-line-items]
+// :state-remove-start: copy
+// NOTE: INTERNAL
+// ** OUTPUT EXAMPLE **
+//
+// Fetching closed invoice line items for organization: 5f7a9ec7d78fc03b42959328
+// Processing invoice ID: 66b3a1f2e4b0c81d2f5a9c10
+// Processing invoice ID: 6689c4d1e4b0c81d2f5a8b07
+// Processing invoice ID: 665f2e10e4b0c81d2f5a7a02
+// Found 214 line items in closed invoices
+// Exported billing data to invoices/closed_5f7a9ec7d78fc03b42959328.json
+// :state-remove-end: [copy]
@@ -0,0 +1,99 @@
+// :snippet-start: incremental-billing-sync
+// :state-remove-start: copy
+// See entire project at https://github.com/mongodb/atlas-architecture-go-sdk
+// :state-remove-end: [copy]
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"time"
+
+	"atlas-sdk-go/internal/auth"
+	"atlas-sdk-go/internal/billing"
+	"atlas-sdk-go/internal/config"
+	"atlas-sdk-go/internal/data/export"
+	"atlas-sdk-go/internal/fileutils"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	envFile := ".env.production"
+	if err := godotenv.Load(envFile); err != nil {
+		log.Printf("Warning: could not load %s file: %v", envFile, err)
+	}
+
+	secrets, cfg, err := config.LoadAllFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to load configuration %v", err)
+	}
+
+	classifier, err := billing.LoadSKUClassifier(cfg.SKURulesPath)
+	if err != nil {
+		log.Fatalf("Failed to load SKU rules: %v", err)
+	}
+
+	ctx := context.Background()
+	client, err := auth.NewClient(ctx, cfg, secrets)
+	if err != nil {
+		log.Fatalf("Failed to initialize authentication client: %v", err)
+	}
+
+	orgID := cfg.OrgID
+	outDir := "invoices"
+
+	// Checkpoints are stored alongside the exported line items so that each run
+	// only picks up line items added or revised since the previous run
+	checkpointPath := filepath.Join(fileutils.ResolveWithDownloadsBase(outDir), "checkpoints.json")
+	store := billing.NewFileCheckpointStore(checkpointPath)
+
+	fmt.Printf("Starting incremental billing sync for organization: %s\n", orgID)
+
+	// The checkpoint only advances after commit returns nil, so a failed export
+	// is retried on the next run. Each run writes its own file so that a second
+	// run on the same day does not overwrite items the checkpoint has moved past
+	runAt := time.Now().UTC()
+	commit := func(items []billing.SyncedDetail) error {
+		prefix := fmt.Sprintf("sync_%s_%s", orgID, runAt.Format("150405"))
+		jsonPath, err := fileutils.GenerateOutputPath(outDir, prefix, "json")
+		if err != nil {
+			return fmt.Errorf("failed to generate JSON output path: %v", err)
+		}
+		if err := export.ToJSON(items, jsonPath); err != nil {
+			return fmt.Errorf("failed to write JSON file: %v", err)
+		}
+		fmt.Printf("Exported %d line items to %s\n", len(items), jsonPath)
+		return nil
+	}
+
+	result, err := billing.SyncLineItems(ctx, client.InvoicesApi, client.OrganizationsApi, orgID, store, commit, billing.SyncOptions{Classifier: classifier})
+	if err != nil {
+		log.Fatalf("Failed to sync billing data for %s: %v", orgID, err)
+	}
+
+	fmt.Printf("Synced line items since %s: %d new, %d revised\n",
+		result.Since.Format("2006-01-02"), result.NewCount, result.RevisedCount)
+	fmt.Printf("Checkpoint for %s is now %s\n", orgID, result.Checkpoint.LastProcessedDate.Format("2006-01-02"))
+	// :remove-start:
+	// Clean up (internal-only function)
+	if err = fileutils.SafeDelete(outDir); err != nil {
+		log.Printf("Cleanup error: %v", err)
+	}
+	fmt.Println("Deleted generated files from", outDir)
+	// :remove-end:
+}
+
+// :snippet-end: [incremental-billing-sync]
+// :state-remove-start: copy
+// NOTE: INTERNAL
+// ** OUTPUT EXAMPLE **
+//
+// Starting incremental billing sync for organization: 5f7a9ec7d78fc03b42959328
+// Processing invoice ID: 66b3a1f2e4b0c81d2f5a9c10
+// Exported 18 line items to invoices/sync_5f7a9ec7d78fc03b42959328_061502_20240814.json
+// Synced line items since 2024-08-11: 16 new, 2 revised
+// Checkpoint for 5f7a9ec7d78fc03b42959328 is now 2024-08-14
+// :state-remove-end: [copy]
@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strconv"
 
 	"atlas-sdk-go/internal/auth"
 	"atlas-sdk-go/internal/billing"
@@ -30,6 +31,11 @@ func main() {
 		log.Fatalf("Failed to load configuration %v", err)
 	}
 
+	classifier, err := billing.LoadSKUClassifier(cfg.SKURulesPath)
+	if err != nil {
+		log.Fatalf("Failed to load SKU rules: %v", err)
+	}
+
 	ctx := context.Background()
 	client, err := auth.NewClient(ctx, cfg, secrets)
 	if err != nil {
@@ -42,7 +48,7 @@ func main() {
 
 	fmt.Printf("Fetching pending invoices for organization: %s\n", p.OrgId)
 
-	details, err := billing.CollectLineItemBillingData(ctx, client.InvoicesApi, client.OrganizationsApi, p.OrgId, nil)
+	details, err := billing.CollectLineItemBillingData(ctx, client.InvoicesApi, client.OrganizationsApi, p.OrgId, nil, classifier)
 	if err != nil {
 		log.Fatalf("Failed to retrieve pending invoices for %s: %v", p.OrgId, err)
 	}
@@ -95,8 +101,14 @@ func exportInvoicesToCSV(details []billing.Detail, outDir, prefix string) error
 
 	// Set the headers and mapped rows for the CSV export
 	headers := []string{"Organization", "OrgID", "Project", "ProjectID", "Cluster",
-		"SKU", "Cost", "Date", "Provider", "Instance", "Category"}
+		"SKU", "Cost", "Date", "Provider", "Instance", "Category",
+		"Region", "Quantity", "Unit", "UnitPrice", "Discount", "EndDate", "Tier", "Note"}
 	err = export.ToCSVWithMapper(details, csvPath, headers, func(item billing.Detail) []string {
+		// Line items without an end date leave the column empty
+		endDate := ""
+		if !item.EndDate.IsZero() {
+			endDate = item.EndDate.Format("2006-01-02")
+		}
 		return []string{
 			item.Org.Name,
 			item.Org.ID,
@@ -109,6 +121,14 @@ func exportInvoicesToCSV(details []billing.Detail, outDir, prefix string) error
 			item.Provider,
 			item.Instance,
 			item.Category,
+			item.Region,
+			strconv.FormatFloat(item.Quantity, 'f', -1, 64),
+			item.Unit,
+			strconv.FormatFloat(item.UnitPrice, 'f', -1, 64),
+			fmt.Sprintf("%.2f", item.Discount),
+			endDate,
+			item.Tier,
+			item.Note,
 		}
 	})
 	if err != nil {
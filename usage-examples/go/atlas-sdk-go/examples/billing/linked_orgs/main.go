@@ -8,10 +8,14 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"time"
 
 	"atlas-sdk-go/internal/auth"
 	"atlas-sdk-go/internal/billing"
+	"atlas-sdk-go/internal/billing/crossorg"
+	"atlas-sdk-go/internal/billing/rollup"
 	"atlas-sdk-go/internal/config"
+	"atlas-sdk-go/internal/fileutils"
 
 	"github.com/joho/godotenv"
 	"go.mongodb.org/atlas-sdk/v20250219001/admin"
@@ -28,6 +32,11 @@ func main() {
 		log.Fatalf("Failed to load configuration %v", err)
 	}
 
+	classifier, err := billing.LoadSKUClassifier(cfg.SKURulesPath)
+	if err != nil {
+		log.Fatalf("Failed to load SKU rules: %v", err)
+	}
+
 	ctx := context.Background()
 	client, err := auth.NewClient(ctx, cfg, secrets)
 	if err != nil {
@@ -45,10 +54,49 @@ func main() {
 		log.Fatalf("Failed to retrieve cross-organization billing data for %s: %v", p.OrgId, err)
 	}
 
-	displayLinkedOrganizations(invoices, p.OrgId)
+	// Resolve organization names once and reuse them for the line items below
+	names := billing.NewOrgNameCache(client.OrganizationsApi)
+	displayLinkedOrganizations(ctx, invoices, p.OrgId, names)
+
+	// Roll up the last three months of spend by the organization that incurred it
+	details, err := billing.CollectLinkedOrgLineItems(ctx, client.InvoicesApi, names, p.OrgId, billing.DefaultInvoiceWorkers, classifier,
+		billing.WithDateRange(time.Now().AddDate(0, -3, 0), time.Now()))
+	if err != nil {
+		log.Fatalf("Failed to collect linked organization line items for %s: %v", p.OrgId, err)
+	}
+	report, err := crossorg.Build(details, p.OrgId, rollup.Month)
+	if err != nil {
+		log.Fatalf("Failed to build cross-organization report: %v", err)
+	}
+
+	fmt.Printf("\n=== Spend by Organization (total billed to %s: $%.2f) ===\n", report.PayingOrgName, report.Total)
+	for _, o := range report.Orgs {
+		role := "linked"
+		if o.Paying {
+			role = "paying"
+		}
+		fmt.Printf("  %-30s %-7s $%10.2f  %5.1f%%\n", o.OrgName, role, o.Total, o.Share*100)
+	}
+
+	outDir := "invoices"
+	csvPath, err := fileutils.GenerateOutputPath(outDir, fmt.Sprintf("linked_orgs_%s", p.OrgId), "csv")
+	if err != nil {
+		log.Fatalf("Failed to generate CSV output path: %v", err)
+	}
+	if err := report.WriteCSV(csvPath); err != nil {
+		log.Fatalf("Failed to write CSV file: %v", err)
+	}
+	fmt.Printf("\nExported spend by organization and month to %s\n", csvPath)
+	// :remove-start:
+	// Clean up (internal-only function)
+	if err = fileutils.SafeDelete(outDir); err != nil {
+		log.Printf("Cleanup error: %v", err)
+	}
+	fmt.Println("Deleted generated files from", outDir)
+	// :remove-end:
 }
 
-func displayLinkedOrganizations(invoices map[string][]admin.BillingInvoiceMetadata, primaryOrgID string) {
+func displayLinkedOrganizations(ctx context.Context, invoices map[string][]admin.BillingInvoiceMetadata, primaryOrgID string, names *billing.OrgNameCache) {
 	var linkedOrgs []string
 	for orgID := range invoices {
 		if orgID != primaryOrgID {
@@ -63,7 +111,7 @@ func displayLinkedOrganizations(invoices map[string][]admin.BillingInvoiceMetada
 
 	fmt.Printf("Found %d linked organizations:\n", len(linkedOrgs))
 	for i, orgID := range linkedOrgs {
-		fmt.Printf("  %d. Organization ID: %s\n", i+1, orgID)
+		fmt.Printf("  %d. %s (Organization ID: %s)\n", i+1, names.Name(ctx, orgID), orgID)
 	}
 }
 
@@ -75,8 +123,19 @@ func displayLinkedOrganizations(invoices map[string][]admin.BillingInvoiceMetada
 // Fetching linked organizations for billing organization: 5f7a9ec7d78fc03b42959328
 //
 // Found 4 linked organizations:
-//  1. Organization ID: 61f4d5e2bf82763afcd12e45
-//  2. Organization ID: 62a1b937c845d9f216890c72
-//  3. Organization ID: 60c8f71e4d8a219b37a5d90f
-//  4. Organization ID: 63e7d2c8a19b4f7654321abc
+//  1. Retail Analytics (Organization ID: 61f4d5e2bf82763afcd12e45)
+//  2. Payments Platform (Organization ID: 62a1b937c845d9f216890c72)
+//  3. Search (Organization ID: 60c8f71e4d8a219b37a5d90f)
+//  4. Data Science (Organization ID: 63e7d2c8a19b4f7654321abc)
+// Processing invoice ID: 66b3a1f2e4b0c81d2f5a9c10
+// ...
+//
+// === Spend by Organization (total billed to Central Billing: $18250.40) ===
+//   Payments Platform              linked  $   8120.00   44.5%
+//   Retail Analytics               linked  $   4410.25   24.2%
+//   Search                         linked  $   3012.15   16.5%
+//   Data Science                   linked  $   1908.00   10.5%
+//   Central Billing                paying  $    800.00    4.4%
+//
+// Exported spend by organization and month to invoices/linked_orgs_5f7a9ec7d78fc03b42959328.csv
 // :state-remove-end: [copy]
@@ -0,0 +1,110 @@
+// :snippet-start: invoice-reconciliation
+// :state-remove-start: copy
+// See entire project at https://github.com/mongodb/atlas-architecture-go-sdk
+// :state-remove-end: [copy]
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"atlas-sdk-go/internal/auth"
+	"atlas-sdk-go/internal/billing"
+	"atlas-sdk-go/internal/billing/reconcile"
+	"atlas-sdk-go/internal/config"
+	"atlas-sdk-go/internal/fileutils"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	envFile := ".env.production"
+	if err := godotenv.Load(envFile); err != nil {
+		log.Printf("Warning: could not load %s file: %v", envFile, err)
+	}
+
+	secrets, cfg, err := config.LoadAllFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to load configuration %v", err)
+	}
+
+	classifier, err := billing.LoadSKUClassifier(cfg.SKURulesPath)
+	if err != nil {
+		log.Fatalf("Failed to load SKU rules: %v", err)
+	}
+
+	ctx := context.Background()
+	client, err := auth.NewClient(ctx, cfg, secrets)
+	if err != nil {
+		log.Fatalf("Failed to initialize authentication client: %v", err)
+	}
+
+	orgID := cfg.OrgID
+	fmt.Printf("Reconciling invoices for organization: %s\n", orgID)
+
+	// Reconcile every closed or paid invoice from the previous three months
+	invoices, err := billing.GetInvoicesForOrg(ctx, client.InvoicesApi, orgID, billing.DefaultInvoiceWorkers,
+		billing.WithStatusNames([]string{"CLOSED", "PAID", "INVOICED"}),
+		billing.WithDateRange(time.Now().AddDate(0, -3, 0), time.Now()))
+	if err != nil {
+		log.Fatalf("Failed to retrieve invoices for %s: %v", orgID, err)
+	}
+	if len(invoices) == 0 {
+		fmt.Printf("No closed invoices found for organization: %s\n", orgID)
+		return
+	}
+
+	report := reconcile.Reconcile(invoices, reconcile.Options{Classifier: classifier})
+
+	fmt.Printf("\n=== Invoice Reconciliation (%d invoices) ===\n", len(report.Invoices))
+	for _, inv := range report.Invoices {
+		fmt.Printf("%s %s: gross $%.2f, credits $%.2f, minimum $%.2f, tax $%.2f = $%.2f vs billed $%.2f",
+			inv.InvoiceID, inv.StartDate.Format("2006-01"), inv.GrossCharges, inv.Credits, inv.MinimumCharges,
+			inv.Taxes, inv.Reconciled, inv.AmountBilled)
+		if inv.Matched {
+			fmt.Println(" - OK")
+		} else {
+			fmt.Printf(" - MISMATCH (%+.2f)\n", inv.Difference)
+		}
+		for _, w := range inv.Warnings {
+			fmt.Printf("    warning: %s\n", w)
+		}
+	}
+	fmt.Printf("\nTotal: gross $%.2f, credits $%.2f, net billed $%.2f, %d mismatched\n",
+		report.GrossCharges, report.Credits, report.AmountBilled, report.Mismatches)
+
+	outDir := "invoices"
+	csvPath, err := fileutils.GenerateOutputPath(outDir, fmt.Sprintf("reconciliation_%s", orgID), "csv")
+	if err != nil {
+		log.Fatalf("Failed to generate CSV output path: %v", err)
+	}
+	if err := report.WriteCSV(csvPath); err != nil {
+		log.Fatalf("Failed to write CSV file: %v", err)
+	}
+	fmt.Printf("Exported reconciliation to %s\n", csvPath)
+	// :remove-start:
+	// Clean up (internal-only function)
+	if err = fileutils.SafeDelete(outDir); err != nil {
+		log.Printf("Cleanup error: %v", err)
+	}
+	fmt.Println("Deleted generated files from", outDir)
+	// :remove-end:
+}
+
+// :snippet-end: [invoice-reconciliation]
+// :state-remove-start: copy
+// NOTE: INTERNAL
+// ** OUTPUT EXAMPLE **
+//
+// Reconciling invoices for organization: 5f7a9ec7d78fc03b42959328
+//
+// === Invoice Reconciliation (3 invoices) ===
+// 66b3a1f2e4b0c81d2f5a9c10 2024-07: gross $2410.55, credits $-500.00, minimum $0.00, tax $154.43 = $2064.98 vs billed $2064.98 - OK
+// 6689d2a1e4b0c81d2f5a1b22 2024-06: gross $2288.10, credits $-500.00, minimum $0.00, tax $143.05 = $1931.15 vs billed $1931.15 - OK
+// 665fa0c3e4b0c81d2f59f301 2024-05: gross $310.00, credits $0.00, minimum $190.00, tax $40.00 = $540.00 vs billed $545.00 - MISMATCH (+5.00)
+//
+// Total: gross $5008.65, credits $-1000.00, net billed $4541.13, 1 mismatched
+// Exported reconciliation to invoices/reconciliation_5f7a9ec7d78fc03b42959328.csv
+// :state-remove-end: [copy]
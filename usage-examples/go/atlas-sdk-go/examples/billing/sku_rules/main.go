@@ -0,0 +1,94 @@
+// :snippet-start: sku-rules
+// :state-remove-start: copy
+// See entire project at https://github.com/mongodb/atlas-architecture-go-sdk
+// :state-remove-end: [copy]
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"atlas-sdk-go/internal/auth"
+	"atlas-sdk-go/internal/billing"
+	"atlas-sdk-go/internal/config"
+	"atlas-sdk-go/internal/data/export"
+	"atlas-sdk-go/internal/fileutils"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	envFile := ".env.production"
+	if err := godotenv.Load(envFile); err != nil {
+		log.Printf("Warning: could not load %s file: %v", envFile, err)
+	}
+
+	secrets, cfg, err := config.LoadAllFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to load configuration %v", err)
+	}
+
+	// Merge any rules from the config file over the embedded defaults
+	classifier, err := billing.LoadSKUClassifier(cfg.SKURulesPath)
+	if err != nil {
+		log.Fatalf("Failed to load SKU rules: %v", err)
+	}
+	fmt.Printf("Using SKU rules version %s\n", classifier.Version())
+
+	ctx := context.Background()
+	client, err := auth.NewClient(ctx, cfg, secrets)
+	if err != nil {
+		log.Fatalf("Failed to initialize authentication client: %v", err)
+	}
+
+	orgID := cfg.OrgID
+	details, err := billing.CollectLineItemBillingData(ctx, client.InvoicesApi, client.OrganizationsApi, orgID, nil, classifier)
+	if err != nil {
+		log.Fatalf("Failed to retrieve pending invoices for %s: %v", orgID, err)
+	}
+
+	unclassified := classifier.Unclassified(details)
+	if len(unclassified) == 0 {
+		fmt.Printf("All %d line items matched a category rule\n", len(details))
+		return
+	}
+
+	fmt.Printf("\n=== Unclassified SKUs (%d) ===\n", len(unclassified))
+	for _, u := range unclassified {
+		fmt.Printf("%-50s %4d items  $%.2f\n", u.SKU, u.Count, u.Cost)
+	}
+
+	// Export the report so the SKUs can be added to a rules file
+	outDir := "invoices"
+	jsonPath, err := fileutils.GenerateOutputPath(outDir, fmt.Sprintf("unclassified_skus_%s", orgID), "json")
+	if err != nil {
+		log.Fatalf("Failed to generate JSON output path: %v", err)
+	}
+	if err := export.ToJSON(unclassified, jsonPath); err != nil {
+		log.Fatalf("Failed to write JSON file: %v", err)
+	}
+	fmt.Printf("\nExported unclassified SKUs to %s\n", jsonPath)
+	// :remove-start:
+	// Clean up (internal-only function)
+	if err = fileutils.SafeDelete(outDir); err != nil {
+		log.Printf("Cleanup error: %v", err)
+	}
+	fmt.Println("Deleted generated files from", outDir)
+	// :remove-end:
+}
+
+// :snippet-end: [sku-rules]
+// :state-remove-start: copy
+// NOTE: INTERNAL
+// ** OUTPUT EXAMPLE **
+//
+// Using SKU rules version 2025-02-19
+// Processing invoice ID: 66b3a1f2e4b0c81d2f5a9c10
+//
+// === Unclassified SKUs (2) ===
+// NDS_AWS_VECTOR_SEARCH_NODES                          31 items  $42.17
+// NDS_ENCRYPTION_AT_REST_KMIP                           1 items  $3.00
+//
+// Exported unclassified SKUs to invoices/unclassified_skus_5f7a9ec7d78fc03b42959328.json
+// :state-remove-end: [copy]
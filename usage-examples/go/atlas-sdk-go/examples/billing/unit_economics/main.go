@@ -0,0 +1,90 @@
+// :snippet-start: unit-economics
+// :state-remove-start: copy
+// See entire project at https://github.com/mongodb/atlas-architecture-go-sdk
+// :state-remove-end: [copy]
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"atlas-sdk-go/internal/auth"
+	"atlas-sdk-go/internal/billing"
+	"atlas-sdk-go/internal/billing/uniteconomics"
+	"atlas-sdk-go/internal/config"
+	"atlas-sdk-go/internal/fileutils"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	envFile := ".env.production"
+	if err := godotenv.Load(envFile); err != nil {
+		log.Printf("Warning: could not load %s file: %v", envFile, err)
+	}
+
+	secrets, cfg, err := config.LoadAllFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to load configuration %v", err)
+	}
+
+	classifier, err := billing.LoadSKUClassifier(cfg.SKURulesPath)
+	if err != nil {
+		log.Fatalf("Failed to load SKU rules: %v", err)
+	}
+
+	ctx := context.Background()
+	client, err := auth.NewClient(ctx, cfg, secrets)
+	if err != nil {
+		log.Fatalf("Failed to initialize authentication client: %v", err)
+	}
+
+	orgID := cfg.OrgID
+	fmt.Printf("Computing unit economics for organization: %s\n", orgID)
+
+	details, err := billing.CollectLineItemBillingData(ctx, client.InvoicesApi, client.OrganizationsApi, orgID, nil, classifier)
+	if err != nil {
+		log.Fatalf("Failed to retrieve pending invoices for %s: %v", orgID, err)
+	}
+
+	report := uniteconomics.Build(details)
+
+	fmt.Printf("\n=== Unit Economics by Cluster ===\n")
+	for _, row := range report.Table() {
+		fmt.Printf("%-20s %-20s %12s %14s %18s %12s %14s\n", row[0], row[1], row[2], row[3], row[5], row[6], row[8])
+	}
+
+	outDir := "invoices"
+	csvPath, err := fileutils.GenerateOutputPath(outDir, fmt.Sprintf("unit_economics_%s", orgID), "csv")
+	if err != nil {
+		log.Fatalf("Failed to generate CSV output path: %v", err)
+	}
+	if err := report.WriteCSV(csvPath); err != nil {
+		log.Fatalf("Failed to write CSV file: %v", err)
+	}
+	fmt.Printf("\nExported unit economics to %s\n", csvPath)
+	// :remove-start:
+	// Clean up (internal-only function)
+	if err = fileutils.SafeDelete(outDir); err != nil {
+		log.Printf("Cleanup error: %v", err)
+	}
+	fmt.Println("Deleted generated files from", outDir)
+	// :remove-end:
+}
+
+// :snippet-end: [unit-economics]
+// :state-remove-start: copy
+// NOTE: INTERNAL
+// ** OUTPUT EXAMPLE **
+//
+// Computing unit economics for organization: 5f7a9ec7d78fc03b42959328
+// Processing invoice ID: 66b3a1f2e4b0c81d2f5a9c10
+//
+// === Unit Economics by Cluster ===
+// Project              Cluster                Total Cost Instance Hours Cost/Instance-Hour    GB-Months  Cost/GB-Month
+// payments-prod        Cluster0                  812.40         1368.0             0.5400       120.00         0.2500
+// analytics            reporting                 140.22          456.0             0.2300        40.00         0.2500
+//
+// Exported unit economics to invoices/unit_economics_5f7a9ec7d78fc03b42959328.csv
+// :state-remove-end: [copy]
@@ -0,0 +1,93 @@
+// :snippet-start: prometheus-exporter
+// :state-remove-start: copy
+// See entire project at https://github.com/mongodb/atlas-architecture-go-sdk
+// :state-remove-end: [copy]
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"atlas-sdk-go/internal/auth"
+	"atlas-sdk-go/internal/config"
+	"atlas-sdk-go/internal/exporter"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	envFile := ".env.production"
+	if err := godotenv.Load(envFile); err != nil {
+		log.Printf("Warning: could not load %s file: %v", envFile, err)
+	}
+
+	secrets, cfg, err := config.LoadAllFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to load configuration %v", err)
+	}
+
+	// Stop refreshing and serving on Ctrl+C or SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	client, err := auth.NewClient(ctx, cfg, secrets)
+	if err != nil {
+		log.Fatalf("Failed to initialize authentication client: %v", err)
+	}
+
+	projectID := cfg.ProjectID
+	if projectID == "" {
+		log.Fatal("Failed to find Project ID in configuration")
+	}
+
+	// Pull measurements from Atlas in the background; scrapes are served from the cache
+	opts := exporter.LoadOptions(cfg)
+	exp := exporter.New(client, projectID, opts)
+	go exp.Run(ctx)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", exp)
+	server := &http.Server{Addr: opts.ListenAddress, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Shutdown error: %v", err)
+		}
+	}()
+
+	log.Printf("Serving Atlas metrics for project %s on %s/metrics (refresh every %ds)",
+		projectID, opts.ListenAddress, opts.RefreshIntervalSeconds)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatalf("Failed to serve metrics: %v", err)
+	}
+}
+
+// :snippet-end: [prometheus-exporter]
+// :state-remove-start: copy
+// NOTE: INTERNAL
+// ** OUTPUT EXAMPLE **
+//
+// 2025/03/02 10:15:04 Serving Atlas metrics for project 5f1a2b3c4d5e6f7a8b9c0d1e on :9216/metrics (refresh every 60s)
+// 2025/03/02 10:15:09 Refresh skipped 1 targets (first: atlas-6yd18i-shard-00-02.nr3ko.mongodb.net:27017/database/admin: ...)
+//
+// $ curl -s localhost:9216/metrics
+// # HELP atlas_connections Atlas measurement CONNECTIONS (SCALAR)
+// # TYPE atlas_connections gauge
+// atlas_connections{project="5f1a2b3c4d5e6f7a8b9c0d1e",cluster="Cluster0",process="atlas-6yd18i-shard-00-00.nr3ko.mongodb.net:27017",role="REPLICA_SECONDARY"} 31
+// atlas_connections{project="5f1a2b3c4d5e6f7a8b9c0d1e",cluster="Cluster0",process="atlas-6yd18i-shard-00-01.nr3ko.mongodb.net:27017",role="REPLICA_PRIMARY"} 58
+// # HELP atlas_disk_partition_space_used_bytes Atlas measurement DISK_PARTITION_SPACE_USED (BYTES)
+// # TYPE atlas_disk_partition_space_used_bytes gauge
+// atlas_disk_partition_space_used_bytes{project="5f1a2b3c4d5e6f7a8b9c0d1e",cluster="Cluster0",process="atlas-6yd18i-shard-00-01.nr3ko.mongodb.net:27017",role="REPLICA_PRIMARY",partition="data"} 4.294967296e+09
+// ...
+// # HELP atlas_exporter_up Whether the last refresh from Atlas succeeded.
+// # TYPE atlas_exporter_up gauge
+// atlas_exporter_up 1
+// :state-remove-end: [copy]
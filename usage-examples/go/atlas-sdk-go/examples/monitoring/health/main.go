@@ -0,0 +1,123 @@
+// :snippet-start: replica-set-health
+// :state-remove-start: copy
+// See entire project at https://github.com/mongodb/atlas-architecture-go-sdk
+// :state-remove-end: [copy]
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"atlas-sdk-go/internal/auth"
+	"atlas-sdk-go/internal/clusterutils"
+	"atlas-sdk-go/internal/config"
+	"atlas-sdk-go/internal/fileutils"
+	"atlas-sdk-go/internal/health"
+
+	"github.com/joho/godotenv"
+	"go.mongodb.org/atlas-sdk/v20250219001/admin"
+)
+
+func main() {
+	clusterFlag := flag.String("cluster", "", "cluster to check (default: every cluster in the project)")
+	flag.Parse()
+
+	envFile := ".env.production"
+	if err := godotenv.Load(envFile); err != nil {
+		log.Printf("Warning: could not load %s file: %v", envFile, err)
+	}
+
+	secrets, cfg, err := config.LoadAllFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to load configuration %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	client, err := auth.NewClient(ctx, cfg, secrets)
+	if err != nil {
+		log.Fatalf("Failed to initialize authentication client: %v", err)
+	}
+
+	projectID := cfg.ProjectID
+	if projectID == "" {
+		log.Fatal("Failed to find Project ID in configuration")
+	}
+
+	clusters := []string{*clusterFlag}
+	if *clusterFlag == "" {
+		clusters, err = clusterutils.ListClusterNames(ctx, client.ClustersApi, &admin.ListClustersApiParams{GroupId: projectID})
+		if err != nil {
+			log.Fatalf("Failed to list clusters: %v", err)
+		}
+	}
+
+	th := health.LoadThresholds(cfg)
+	outDir := "health"
+	unhealthy := 0
+	for _, name := range clusters {
+		report, err := health.EvaluateCluster(ctx, client, projectID, name, th)
+		if err != nil {
+			fmt.Printf("\n=== %s: UNKNOWN ===\n  %v\n", name, err)
+			unhealthy++
+			continue
+		}
+		fmt.Printf("\n=== %s: %s ===\n", name, report.Status)
+		for _, rs := range report.ReplicaSets {
+			fmt.Printf("%s [%s]", rs.Name, rs.Status)
+			if len(rs.Issues) > 0 {
+				fmt.Printf(" %s", strings.Join(rs.Issues, "; "))
+			}
+			fmt.Println()
+			for _, m := range rs.Members {
+				fmt.Printf("  %-6s %s (%s)", m.Status, m.ID, m.Role)
+				if problems := m.Problems(); len(problems) > 0 {
+					fmt.Printf(": %s", strings.Join(problems, "; "))
+				}
+				fmt.Println()
+			}
+		}
+		if report.Status == health.Red {
+			unhealthy++
+		}
+
+		csvPath, err := fileutils.GenerateOutputPath(outDir, "health_"+name, "csv")
+		if err != nil {
+			log.Fatalf("Failed to generate report path: %v", err)
+		}
+		if err := report.WriteCSV(csvPath); err != nil {
+			log.Fatalf("Failed to write health report: %v", err)
+		}
+		fmt.Printf("Report written to %s\n", csvPath)
+	}
+	// :remove-start:
+	// Clean up (internal-only function)
+	if err = fileutils.SafeDelete(outDir); err != nil {
+		log.Printf("Cleanup error: %v", err)
+	}
+	fmt.Println("Deleted generated files from", outDir)
+	// :remove-end:
+
+	// Exit non-zero when any cluster is red or could not be checked, so the command can gate on-call runbooks
+	if unhealthy > 0 {
+		os.Exit(1)
+	}
+}
+
+// :snippet-end: [replica-set-health]
+// :state-remove-start: copy
+// NOTE: INTERNAL
+// ** OUTPUT EXAMPLE **
+//
+// === Cluster0: YELLOW ===
+// atlas-6yd18i-shard-0 [YELLOW]
+//   GREEN  atlas-6yd18i-shard-00-00.nr3ko.mongodb.net:27017 (REPLICA_SECONDARY)
+//   GREEN  atlas-6yd18i-shard-00-01.nr3ko.mongodb.net:27017 (REPLICA_PRIMARY)
+//   YELLOW atlas-6yd18i-shard-00-02.nr3ko.mongodb.net:27017 (REPLICA_SECONDARY): replication lag: 84s > 60s
+// Report written to health/health_Cluster0_20250302.csv
+// :state-remove-end: [copy]
@@ -15,7 +15,6 @@ import (
 	"atlas-sdk-go/internal/metrics"
 
 	"github.com/joho/godotenv"
-	"go.mongodb.org/atlas-sdk/v20250219001/admin"
 )
 
 func main() {
@@ -35,22 +34,27 @@ func main() {
 		log.Fatalf("Failed to initialize authentication client: %v", err)
 	}
 
-	// Fetch disk metrics with the provided parameters
-	p := &admin.GetDiskMeasurementsApiParams{
-		GroupId:       cfg.ProjectID,
-		ProcessId:     cfg.ProcessID,
-		PartitionName: "data",
-		M:             &[]string{"DISK_PARTITION_SPACE_FREE", "DISK_PARTITION_SPACE_USED"},
-		Granularity:   admin.PtrString("P1D"),
-		Period:        admin.PtrString("P1D"),
-	}
-	view, err := metrics.FetchDiskMetrics(ctx, client.MonitoringAndLogsApi, p)
+	// Discover every disk partition of the process rather than assuming "data"
+	targets, err := metrics.ListDiskPartitions(ctx, client.MonitoringAndLogsApi, cfg.ProjectID, cfg.ProcessID)
 	if err != nil {
-		log.Fatalf("Failed to fetch disk metrics: %v", err)
+		log.Fatalf("Failed to list disk partitions: %v", err)
+	}
+
+	// Fetch disk metrics for all partitions concurrently
+	c := metrics.Collect(ctx, client.MonitoringAndLogsApi, cfg.ProjectID, targets, metrics.CollectOptions{
+		DiskMeasurements: []string{"DISK_PARTITION_SPACE_FREE", "DISK_PARTITION_SPACE_USED"},
+		Granularity:      "P1D",
+		Period:           "P1D",
+	})
+	for _, e := range c.Errors {
+		log.Printf("Warning: failed to fetch disk metrics for %s: %v", e.Target, e.Err)
+	}
+	if len(c.Results) == 0 {
+		log.Fatalf("No disk metrics found for process %s", cfg.ProcessID)
 	}
 
 	// Output metrics
-	out, err := json.MarshalIndent(view, "", "  ")
+	out, err := json.MarshalIndent(c.Results, "", "  ")
 	if err != nil {
 		log.Fatalf("Failed to format metrics data: %v", err)
 	}
@@ -61,24 +65,27 @@ func main() {
 // :state-remove-start: copy
 // NOTE: INTERNAL
 // ** OUTPUT EXAMPLE **
-// {
-//   "measurements": [
-//     {
-//       "name": "DISK_PARTITION_SPACE_FREE",
-//       "granularity": "P1D",
-//       "period": "P1D",
-//       "values": [
-//         {
-//           "timestamp": "2023-10-01T00:00:00Z",
-//           "value": 1234567890
-//         },
-//         {
-//           "timestamp": "2023-10-02T00:00:00Z",
-//           "value": 1234567890
-//         }
-//       ]
+// [
+//   {
+//     "target": {
+//       "kind": "disk",
+//       "processId": "atlas-abc123-shard-00-00.ab1cd.mongodb.net:27017",
+//       "partition": "data"
 //     },
+//     "series": {
+//       "DISK_PARTITION_SPACE_FREE": {
+//         "name": "DISK_PARTITION_SPACE_FREE",
+//         "units": "BYTES",
+//         "points": [
+//           {
+//             "timestamp": "2023-10-01T00:00:00Z",
+//             "value": 1234567890,
+//             "valid": true
+//           }
+//         ]
+//       },
 //	 	...
-//   ]
-// }
+//     }
+//   }
+// ]
 // :state-remove-end: [copy]
@@ -69,7 +69,11 @@ func main() {
 		// This simplified example first selects all collections with counts, and then filters them.
 		// NOTE: In a real implementation, you would analyze collections based on size, age,
 		// access patterns, and other factors to determine candidates for archiving.
-		stats := archive.ListCollectionsWithCounts(ctx, client, projectID, clusterName)
+		stats, err := archive.ListCollectionsWithCounts(ctx, client, projectID, clusterName, cfg.Database, secrets)
+		if err != nil {
+			fmt.Printf("\nFailed to list collections for cluster %s: %v\n", clusterName, err)
+			continue
+		}
 		candidates := make([]archive.Candidate, 0)
 		const docThreshold = 100000
 		for _, s := range stats {
@@ -0,0 +1,134 @@
+// :snippet-start: cluster-desired-state
+// :state-remove-start: copy
+// See entire project at https://github.com/mongodb/atlas-architecture-go-sdk
+// :state-remove-end: [copy]
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"atlas-sdk-go/internal/auth"
+	"atlas-sdk-go/internal/clusterutils"
+	"atlas-sdk-go/internal/config"
+	"atlas-sdk-go/internal/scale"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	desiredPath := flag.String("file", "configs/desired_state.json", "desired state file")
+	apply := flag.Bool("apply", false, "apply the plan after printing it")
+	autoApprove := flag.Bool("yes", false, "apply without asking for confirmation per cluster")
+	flag.Parse()
+
+	envFile := ".env.production"
+	if err := godotenv.Load(envFile); err != nil {
+		log.Printf("Warning: could not load %s file: %v", envFile, err)
+	}
+
+	secrets, cfg, err := config.LoadAllFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to load configuration %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Hour)
+	defer cancel()
+	client, err := auth.NewClient(ctx, cfg, secrets)
+	if err != nil {
+		log.Fatalf("Failed to initialize authentication client: %v", err)
+	}
+
+	desired, err := scale.LoadDesiredState(*desiredPath)
+	if err != nil {
+		log.Fatalf("Failed to load desired state: %v", err)
+	}
+
+	// Compare each declared cluster with its live configuration
+	plan, err := scale.BuildPlan(ctx, client.ClustersApi, desired)
+	if err != nil {
+		log.Fatalf("Failed to build plan: %v", err)
+	}
+	fmt.Printf("=== Plan: %d of %d clusters need changes ===\n", len(plan.Clusters), len(desired.Clusters))
+	for _, cp := range plan.Clusters {
+		fmt.Printf("\n%s/%s\n", cp.ProjectID, cp.Cluster)
+		for _, c := range cp.Changes {
+			fmt.Printf("  ~ %s: %q -> %q\n", c.Field, c.Before, c.After)
+		}
+	}
+	if !plan.HasChanges() {
+		fmt.Println("No changes: clusters match the desired state")
+		return
+	}
+	if !*apply {
+		fmt.Println("\nRun with -apply to make these changes.")
+		return
+	}
+
+	// Apply one cluster at a time, waiting for each to return to IDLE before the next
+	stdin := bufio.NewReader(os.Stdin)
+	opts := scale.ApplyOptions{Wait: clusterutils.DefaultWaitOptions()}
+	opts.Wait.Progress = func(p clusterutils.WaitProgress) {
+		if p.Changed {
+			fmt.Printf("  %s: %s (after %s)\n", p.Cluster, p.State, p.Elapsed.Round(time.Second))
+		}
+	}
+	if !*autoApprove {
+		opts.Confirm = func(cp scale.ClusterPlan) bool {
+			if cp.Replanned {
+				// The cluster changed while waiting; show the changes that would now be made
+				fmt.Printf("\n%s/%s changed since the plan was built; new plan:\n", cp.ProjectID, cp.Cluster)
+				for _, c := range cp.Changes {
+					fmt.Printf("  ~ %s: %q -> %q\n", c.Field, c.Before, c.After)
+				}
+			}
+			fmt.Printf("\nApply %d changes to %s/%s? [y/N] ", len(cp.Changes), cp.ProjectID, cp.Cluster)
+			answer, _ := stdin.ReadString('\n')
+			answer = strings.ToLower(strings.TrimSpace(answer))
+			return answer == "y" || answer == "yes"
+		}
+	}
+
+	results, err := scale.ApplyPlan(ctx, client.ClustersApi, plan, opts)
+	fmt.Println("\n=== Apply Summary ===")
+	for _, r := range results {
+		switch {
+		case r.Err != nil:
+			fmt.Printf("%s/%s: FAILED: %v\n", r.ProjectID, r.Cluster, r.Err)
+		case r.Skipped:
+			fmt.Printf("%s/%s: skipped\n", r.ProjectID, r.Cluster)
+		default:
+			fmt.Printf("%s/%s: applied\n", r.ProjectID, r.Cluster)
+		}
+	}
+	if err != nil {
+		log.Fatalf("Apply stopped: %v", err)
+	}
+}
+
+// :snippet-end: [cluster-desired-state]
+// :state-remove-start: copy
+// NOTE: INTERNAL
+// ** OUTPUT EXAMPLE **
+//
+// === Plan: 1 of 1 clusters need changes ===
+//
+// 5f60207f14dfb25d23101102/Cluster0
+//   ~ shard[0].AWS/US_EAST_1.electable.instanceSize: "M30" -> "M40"
+//   ~ shard[0].AWS/US_EAST_1.electable.diskSizeGB: "40" -> "80"
+//   ~ shard[0].AWS/US_EAST_1.autoScaling.diskEnabled: "false" -> "true"
+//
+// Apply 3 changes to 5f60207f14dfb25d23101102/Cluster0? [y/N] y
+//   Cluster0: IDLE (after 0s)
+//   Cluster0: UPDATING (after 0s)
+//   Cluster0: IDLE (after 9m12s)
+//
+// === Apply Summary ===
+// 5f60207f14dfb25d23101102/Cluster0: applied
+// :state-remove-end: [copy]
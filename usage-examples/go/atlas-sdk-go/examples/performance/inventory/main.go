@@ -0,0 +1,130 @@
+// :snippet-start: cluster-inventory-drift
+// :state-remove-start: copy
+// See entire project at https://github.com/mongodb/atlas-architecture-go-sdk
+// :state-remove-end: [copy]
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"atlas-sdk-go/internal/auth"
+	"atlas-sdk-go/internal/config"
+	"atlas-sdk-go/internal/fileutils"
+	"atlas-sdk-go/internal/inventory"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	baselinePath := flag.String("baseline", "", "snapshot file to compare the new snapshot against")
+	projects := flag.String("projects", "", "comma-separated project IDs (default: every project in the organization)")
+	flag.Parse()
+
+	envFile := ".env.production"
+	if err := godotenv.Load(envFile); err != nil {
+		log.Printf("Warning: could not load %s file: %v", envFile, err)
+	}
+
+	secrets, cfg, err := config.LoadAllFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to load configuration %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+	client, err := auth.NewClient(ctx, cfg, secrets)
+	if err != nil {
+		log.Fatalf("Failed to initialize authentication client: %v", err)
+	}
+
+	var projectIDs []string
+	if *projects != "" {
+		projectIDs = strings.Split(*projects, ",")
+	}
+
+	fmt.Printf("Taking cluster inventory snapshot for organization: %s\n", cfg.OrgID)
+	snapshot, err := inventory.Collect(ctx, client, cfg.OrgID, projectIDs)
+	if err != nil {
+		log.Fatalf("Failed to collect cluster inventory: %v", err)
+	}
+	fmt.Printf("Captured %d clusters at %s\n", len(snapshot.Clusters), snapshot.TakenAt.Format(time.RFC3339))
+
+	// Keep every snapshot; they are the evidence for later drift checks
+	outDir := "inventory"
+	prefix := fmt.Sprintf("inventory_%s_%s", cfg.OrgID, snapshot.TakenAt.Format("150405"))
+	snapshotPath, err := fileutils.GenerateOutputPath(outDir, prefix, "json")
+	if err != nil {
+		log.Fatalf("Failed to generate snapshot path: %v", err)
+	}
+	if err := snapshot.Save(snapshotPath); err != nil {
+		log.Fatalf("Failed to write snapshot: %v", err)
+	}
+	fmt.Printf("Snapshot written to %s\n", snapshotPath)
+
+	drift := false
+	if *baselinePath != "" {
+		baseline, err := inventory.Load(*baselinePath)
+		if err != nil {
+			log.Fatalf("Failed to load baseline snapshot: %v", err)
+		}
+		report := inventory.Diff(baseline, snapshot)
+		fmt.Printf("\n=== Drift since %s ===\n", baseline.TakenAt.Format(time.RFC3339))
+		if !report.HasDrift() {
+			fmt.Println("No drift: clusters match the baseline")
+		}
+		for _, c := range report.Changes {
+			if c.Kind == inventory.Modified {
+				fmt.Printf("  %s/%s: %s %q -> %q\n", c.ProjectID, c.Cluster, c.Field, c.Before, c.After)
+			} else {
+				fmt.Printf("  %s/%s: %s\n", c.ProjectID, c.Cluster, c.Kind)
+			}
+		}
+
+		if report.HasDrift() {
+			drift = true
+			csvPath, err := fileutils.GenerateOutputPath(outDir, "drift_"+cfg.OrgID, "csv")
+			if err != nil {
+				log.Fatalf("Failed to generate drift report path: %v", err)
+			}
+			if err := report.WriteCSV(csvPath); err != nil {
+				log.Fatalf("Failed to write drift report: %v", err)
+			}
+			fmt.Printf("\n%d changes written to %s\n", len(report.Changes), csvPath)
+		}
+	}
+	// :remove-start:
+	// Clean up (internal-only function)
+	if err = fileutils.SafeDelete(outDir); err != nil {
+		log.Printf("Cleanup error: %v", err)
+	}
+	fmt.Println("Deleted generated files from", outDir)
+	// :remove-end:
+
+	// Exit non-zero on drift so scheduled runs can flag changes made outside approved windows
+	if drift {
+		os.Exit(1)
+	}
+}
+
+// :snippet-end: [cluster-inventory-drift]
+// :state-remove-start: copy
+// NOTE: INTERNAL
+// ** OUTPUT EXAMPLE **
+//
+// Taking cluster inventory snapshot for organization: 5f7a9ec7d78fc03b42959328
+// Captured 6 clusters at 2025-03-02T08:00:04Z
+// Snapshot written to inventory/inventory_5f7a9ec7d78fc03b42959328_080004_20250302.json
+//
+// === Drift since 2025-03-01T08:00:03Z ===
+//   5f60207f14dfb25d23101102/Cluster0: shard[0].AWS/US_EAST_1.electable.instanceSize "M30" -> "M40"
+//   5f60207f14dfb25d23101102/Cluster0: pitEnabled "true" -> "false"
+//   66a1c2e3f4b5a6978d0e1f23/scratch: cluster added
+//
+// 3 changes written to inventory/drift_5f7a9ec7d78fc03b42959328_20250302.csv
+// :state-remove-end: [copy]
@@ -12,11 +12,14 @@ import (
 	"time"
 
 	"atlas-sdk-go/internal/auth"
+	"atlas-sdk-go/internal/billing"
+	"atlas-sdk-go/internal/billing/pricing"
 	"atlas-sdk-go/internal/clusterutils"
 	"atlas-sdk-go/internal/config"
 	"atlas-sdk-go/internal/scale"
 
 	"github.com/joho/godotenv"
+	"go.mongodb.org/atlas-sdk/v20250219001/admin"
 )
 
 func main() {
@@ -57,6 +60,15 @@ func main() {
 	fmt.Printf("Configuration - Target tier: %s, Pre-scale: %v, CPU threshold: %.1f%%, Period: %d min, Dry run: %v\n",
 		scaling.TargetTier, scaling.PreScale, scaling.CPUThreshold, scaling.PeriodMinutes, scaling.DryRun)
 
+	// Load node prices to estimate the cost impact of each tier change
+	catalog, err := loadPriceCatalog(ctx, client, cfg.OrgID, cfg.SKURulesPath, scaling)
+	if err != nil {
+		log.Fatalf("Failed to load price catalog: %v", err)
+	}
+	if scaling.MaxMonthlyCostIncrease > 0 {
+		fmt.Printf("Cost ceiling: $%.2f/month increase per cluster\n", scaling.MaxMonthlyCostIncrease)
+	}
+
 	clusterList, _, err := client.ClustersApi.ListClusters(ctx, projectID).Execute()
 	if err != nil {
 		log.Fatalf("Failed to list clusters: %v", err)
@@ -70,13 +82,15 @@ func main() {
 	successfulScales := 0
 	failedScales := 0
 	skippedClusters := 0
+	refusedScales := 0
+	var scaledClusters []string
 
 	for _, cluster := range clusters {
 		clusterName := cluster.GetName()
 		fmt.Printf("\n=== Analyzing cluster: %s ===\n", clusterName)
 
 		// Skip clusters that are not in IDLE state
-		if cluster.HasStateName() && cluster.GetStateName() != "IDLE" {
+		if cluster.HasStateName() && cluster.GetStateName() != clusterutils.StateIdle {
 			fmt.Printf("- Skipping cluster %s: not in IDLE state (current: %s)\n", clusterName, cluster.GetStateName())
 			skippedClusters++
 			continue
@@ -146,6 +160,28 @@ func main() {
 		scalingCandidates++
 		fmt.Printf("- Scaling decision: proceed -> %s\n", reason)
 
+		// Refuse tier changes that exceed the cost ceiling, or that cannot be priced when a ceiling is set
+		if catalog != nil {
+			estimate, err := pricing.EstimateScaling(&cluster, scaling.TargetTier, catalog)
+			if err != nil {
+				fmt.Printf("- Unable to estimate cost impact: %v\n", err)
+				if scaling.MaxMonthlyCostIncrease > 0 {
+					fmt.Printf("- REFUSED: cost ceiling is set but the change cannot be priced\n")
+					refusedScales++
+					continue
+				}
+			} else {
+				fmt.Printf("- Estimated cost impact: %+.2f/hour (%+.2f/month) across %d node groups\n",
+					estimate.HourlyDelta, estimate.MonthlyDelta, len(estimate.Groups))
+				if estimate.ExceedsCeiling(scaling.MaxMonthlyCostIncrease) {
+					fmt.Printf("- REFUSED: monthly increase $%.2f exceeds ceiling $%.2f\n",
+						estimate.MonthlyDelta, scaling.MaxMonthlyCostIncrease)
+					refusedScales++
+					continue
+				}
+			}
+		}
+
 		if scaling.DryRun {
 			fmt.Printf("- DRY_RUN=true: would scale cluster %s from %s to %s\n",
 				clusterName, currentTier, scaling.TargetTier)
@@ -161,6 +197,7 @@ func main() {
 		fmt.Printf("- Successfully initiated scaling for cluster %s from %s to %s\n",
 			clusterName, currentTier, scaling.TargetTier)
 		successfulScales++
+		scaledClusters = append(scaledClusters, clusterName)
 	}
 
 	fmt.Printf("\n=== Scaling Operation Summary ===\n")
@@ -169,18 +206,66 @@ func main() {
 	fmt.Printf("Successful scaling operations: %d\n", successfulScales)
 	fmt.Printf("Failed scaling operations: %d\n", failedScales)
 	fmt.Printf("Skipped clusters: %d\n", skippedClusters)
+	fmt.Printf("Refused by cost ceiling: %d\n", refusedScales)
 
 	if failedScales > 0 {
 		fmt.Printf("WARNING: %d of %d scaling operations failed\n", failedScales, scalingCandidates)
 	}
 
-	if successfulScales > 0 && !scaling.DryRun {
+	if len(scaledClusters) > 0 {
 		fmt.Println("\nAtlas will perform rolling resizes with zero-downtime semantics.")
-		fmt.Println("Monitor status in the Atlas UI or poll cluster states until STATE_NAME becomes IDLE.")
+		fmt.Printf("Waiting for %d clusters to return to %s...\n", len(scaledClusters), clusterutils.StateIdle)
+		waitOpts := clusterutils.DefaultWaitOptions()
+		waitOpts.AwaitTransition = clusterutils.DefaultAwaitTransition
+		waitOpts.Progress = func(p clusterutils.WaitProgress) {
+			if p.Changed {
+				fmt.Printf("- %s: %s (after %s)\n", p.Cluster, p.State, p.Elapsed.Round(time.Second))
+			}
+		}
+		for _, r := range clusterutils.WaitForClusters(ctx, client.ClustersApi, projectID, scaledClusters, waitOpts) {
+			if r.Err != nil {
+				fmt.Printf("- WARNING: %s did not finish resizing: %v\n", r.Cluster, r.Err)
+				continue
+			}
+			fmt.Printf("- %s resized in %s\n", r.Cluster, r.Elapsed.Round(time.Second))
+		}
 	}
 	fmt.Println("Scaling analysis and operations completed.")
 }
 
+// loadPriceCatalog loads the configured price catalog and, if enabled, adds the prices billed on the
+// last two months of invoices, classifying their SKUs with the rules at skuRulesPath merged over the
+// embedded defaults. It returns nil if no pricing source is configured.
+func loadPriceCatalog(ctx context.Context, client *admin.APIClient, orgID, skuRulesPath string, sc scale.ScalingConfig) (*pricing.Catalog, error) {
+	if sc.PriceCatalogPath == "" && !sc.LearnPrices {
+		return nil, nil
+	}
+	catalog := &pricing.Catalog{}
+	if sc.PriceCatalogPath != "" {
+		c, err := pricing.LoadCatalog(sc.PriceCatalogPath)
+		if err != nil {
+			return nil, err
+		}
+		catalog = c
+	}
+	if sc.LearnPrices && orgID != "" {
+		classifier, err := billing.LoadSKUClassifier(skuRulesPath)
+		if err != nil {
+			return nil, err
+		}
+		details, err := billing.CollectInvoiceLineItems(ctx, client.InvoicesApi, client.OrganizationsApi, orgID,
+			billing.DefaultInvoiceWorkers, classifier, billing.WithDateRange(time.Now().AddDate(0, -2, 0), time.Now()))
+		if err != nil {
+			// Fall back to the catalog file; learned prices are an optional refinement
+			log.Printf("Warning: unable to learn prices from invoices: %v", err)
+		} else {
+			fmt.Printf("Learned %d node prices from recent invoices\n", catalog.Learn(details))
+		}
+	}
+	fmt.Printf("Price catalog: %d prices loaded\n", len(catalog.Prices))
+	return catalog, nil
+}
+
 // :snippet-end: [scale-cluster-programmatically-prod]
 // :state-remove-start: copy
 // NOTE: INTERNAL
@@ -188,25 +273,32 @@ func main() {
 //
 //Starting scaling analysis for project: 5f60207f14dfb25d23101102
 //Configuration - Target tier: M50, Pre-scale: true, CPU threshold: 75.0%, Period: 60 min, Dry run: true
+//Learned 4 node prices from recent invoices
+//Price catalog: 17 prices loaded
+//Cost ceiling: $2500.00/month increase per cluster
 //
 //Found 2 clusters to analyze for scaling
 //
 //=== Analyzing cluster: Cluster0 ===
-//- Current tier: M10, Target tier: M50
+//- Current tier: M40, Target tier: M50
 //- Found 3 processes (primary=atlas-6yd18i-shard-00-01.nr3ko.mongodb.net:27017)
 //- Scaling decision: proceed -> pre-scale event flag set (predictable traffic spike)
-//- DRY_RUN=true: would scale cluster Cluster0 from M10 to M50
+//- Estimated cost impact: +2.88/hour (+2102.40/month) across 1 node groups
+//- DRY_RUN=true: would scale cluster Cluster0 from M40 to M50
 //
-//=== Analyzing cluster: AtlasCluster ===
-//- Current tier: M0, Target tier: M50
-//- Scaling decision: proceed -> pre-scale event flag set (shared tier)
-//- DRY_RUN=true: would scale cluster AtlasCluster from M0 to M50
+//=== Analyzing cluster: AnalyticsCluster ===
+//- Current tier: M10, Target tier: M50
+//- Found 3 processes (primary=atlas-k2m81p-shard-00-00.nr3ko.mongodb.net:27017)
+//- Scaling decision: proceed -> pre-scale event flag set (predictable traffic spike)
+//- Estimated cost impact: +5.76/hour (+4204.80/month) across 1 node groups
+//- REFUSED: monthly increase $4204.80 exceeds ceiling $2500.00
 //
 //=== Scaling Operation Summary ===
 //Total clusters analyzed: 2
 //Scaling candidates identified: 2
-//Successful scaling operations: 2
+//Successful scaling operations: 1
 //Failed scaling operations: 0
 //Skipped clusters: 0
+//Refused by cost ceiling: 1
 //Scaling analysis and operations completed.
 // :state-remove-end: [copy]
@@ -11,6 +11,7 @@ require (
 	github.com/stretchr/testify v1.10.0 // :remove:
 	go.mongodb.org/atlas-sdk/v20250219001 v20250219001.1.0
 	go.mongodb.org/mongo-driver v1.17.4
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -19,7 +20,6 @@ require (
 	github.com/pmezard/go-difflib v1.0.0 // indirect; indirect // :remove:
 	github.com/stretchr/objx v0.5.2 // indirect; indirect // :remove:
 	golang.org/x/oauth2 v0.30.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
 
 require (
@@ -0,0 +1,381 @@
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"atlas-sdk-go/internal/clusterutils"
+	"atlas-sdk-go/internal/errors"
+	"atlas-sdk-go/internal/metrics"
+
+	"go.mongodb.org/atlas-sdk/v20250219001/admin"
+)
+
+// ResolvedRetention is how long a resolved alert is kept in the state before it is forgotten.
+const ResolvedRetention = 24 * time.Hour
+
+// State is the lifecycle stage of an alert.
+type State string
+
+// Alert states
+const (
+	StatePending  State = "pending"  // condition holds but not yet for the rule's "for" duration
+	StateFiring   State = "firing"   // condition has held for the "for" duration
+	StateResolved State = "resolved" // condition stopped holding after the alert fired
+)
+
+// Alert is the state of one rule for one target. There is at most one alert per rule and target.
+type Alert struct {
+	Key          string         `json:"key"` // "<rule>|<target>"
+	Rule         string         `json:"rule"`
+	Severity     string         `json:"severity,omitempty"`
+	Summary      string         `json:"summary,omitempty"`
+	Condition    string         `json:"condition"`
+	Target       metrics.Target `json:"target"`
+	Role         string         `json:"role,omitempty"`
+	State        State          `json:"state"`
+	Value        float64        `json:"value"`
+	ActiveSince  time.Time      `json:"activeSince"`
+	FiredAt      time.Time      `json:"firedAt,omitzero"`
+	ResolvedAt   time.Time      `json:"resolvedAt,omitzero"`
+	LastNotified time.Time      `json:"lastNotified,omitzero"`
+}
+
+// StateStore loads and saves alert state between runs. Load returns an empty map if nothing has been saved.
+type StateStore interface {
+	Load() (map[string]Alert, error)
+	Save(alerts map[string]Alert) error
+}
+
+// FileStateStore keeps alert state in a JSON file. Writes go to a temporary file that is
+// renamed into place, so a crash mid-write leaves the previous state intact.
+type FileStateStore struct {
+	path string
+}
+
+// NewFileStateStore returns a store backed by the JSON file at path.
+// The file and its parent directory are created on the first Save.
+func NewFileStateStore(path string) *FileStateStore {
+	return &FileStateStore{path: path}
+}
+
+// Load returns the saved alerts keyed by alert key. A missing file is treated as empty.
+func (s *FileStateStore) Load() (map[string]Alert, error) {
+	all := make(map[string]Alert)
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return all, nil
+		}
+		return nil, errors.WithContext(err, "reading alert state file")
+	}
+	if len(data) == 0 {
+		return all, nil
+	}
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, errors.WithContext(err, "parsing alert state file")
+	}
+	return all, nil
+}
+
+// Save replaces the saved alerts.
+func (s *FileStateStore) Save(alerts map[string]Alert) error {
+	data, err := json.MarshalIndent(alerts, "", "  ")
+	if err != nil {
+		return errors.WithContext(err, "encoding alert state")
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return errors.WithContext(err, "creating alert state directory")
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return errors.WithContext(err, "writing alert state file")
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return errors.WithContext(err, "replacing alert state file")
+	}
+	return nil
+}
+
+// Engine evaluates rules against a project's measurements, tracks alert state between runs,
+// and sends alerts that fire, repeat, or resolve to its notifiers.
+type Engine struct {
+	Rules     []Rule
+	Store     StateStore
+	Notifiers []Notifier
+	// Workers and RequestTimeout control metric collection (see metrics.CollectOptions).
+	Workers        int
+	RequestTimeout time.Duration
+}
+
+// RunResult is the outcome of one engine run.
+type RunResult struct {
+	Alerts   []Alert               // every pending, firing, and resolved alert after the run, by key
+	Notified []Alert               // alerts sent to the notifiers on this run
+	Errors   []metrics.TargetError // targets whose measurements could not be fetched
+}
+
+// Run fetches the measurements the rules need, updates alert state, notifies, and saves the state.
+// Alerts are only marked notified if every notifier succeeds, so a failed notification is retried
+// on the next run. Targets that cannot be fetched keep their previous alert state; targets that are
+// no longer discovered are retired (see Retire).
+func (e *Engine) Run(ctx context.Context, client *admin.APIClient, projectID string, now time.Time) (*RunResult, error) {
+	if projectID == "" {
+		return nil, &errors.ValidationError{Message: "project ID is required"}
+	}
+	alerts, err := e.Store.Load()
+	if err != nil {
+		return nil, err
+	}
+	processes, err := clusterutils.ListAllProcesses(ctx, client.MonitoringAndLogsApi, &admin.ListAtlasProcessesApiParams{GroupId: projectID})
+	if err != nil {
+		return nil, err
+	}
+	roles := make(map[string]string, len(processes))
+	for _, p := range processes {
+		roles[p.GetId()] = p.GetTypeName()
+	}
+
+	result := &RunResult{}
+	discovered := make(map[string]bool)
+	var discoveryErrs []metrics.TargetError
+	for _, g := range groupRules(e.Rules) {
+		var scoped []admin.ApiHostViewAtlas
+		for _, p := range processes {
+			if g.appliesTo(p.GetTypeName()) {
+				scoped = append(scoped, p)
+			}
+		}
+		opts := metrics.CollectOptions{
+			Granularity:    "PT1M",
+			Period:         isoMinutes(g.window),
+			Workers:        e.Workers,
+			RequestTimeout: e.RequestTimeout,
+		}
+		switch g.kind {
+		case metrics.TargetDisk:
+			opts.DiskMeasurements = g.measurements
+		case metrics.TargetDatabase:
+			opts.DatabaseMeasurements = g.measurements
+		default:
+			opts.ProcessMeasurements = g.measurements
+		}
+		targets, targetErrs := metrics.DiscoverProcessTargets(ctx, client.MonitoringAndLogsApi, projectID, scoped, opts)
+		c := metrics.Collect(ctx, client.MonitoringAndLogsApi, projectID, targets, opts)
+		result.Errors = append(result.Errors, targetErrs...)
+		result.Errors = append(result.Errors, c.Errors...)
+		for _, t := range targets {
+			discovered[t.String()] = true
+		}
+		discoveryErrs = append(discoveryErrs, targetErrs...)
+		Evaluate(alerts, g.rules, c.Results, roles, now)
+	}
+	Retire(alerts, e.Rules, discovered, discoveryErrs, roles, now)
+	Prune(alerts, e.Rules, now)
+
+	result.Notified = Due(alerts, e.Rules, now)
+	if len(result.Notified) > 0 {
+		if err := e.notify(ctx, result.Notified); err != nil {
+			// Save the state so transitions are kept; the alerts stay due and are sent next run
+			if saveErr := e.Store.Save(alerts); saveErr != nil {
+				return nil, saveErr
+			}
+			return nil, err
+		}
+		for _, n := range result.Notified {
+			a := alerts[n.Key]
+			a.LastNotified = now
+			alerts[n.Key] = a
+		}
+	}
+	if err := e.Store.Save(alerts); err != nil {
+		return nil, err
+	}
+	for _, k := range slices.Sorted(maps.Keys(alerts)) {
+		result.Alerts = append(result.Alerts, alerts[k])
+	}
+	return result, nil
+}
+
+// notify sends alerts to every notifier and returns the first error.
+func (e *Engine) notify(ctx context.Context, alerts []Alert) error {
+	var firstErr error
+	for _, n := range e.Notifiers {
+		if err := n.Notify(ctx, alerts); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Evaluate updates alerts, keyed by alert key, with the rules' conditions over the fetched results.
+// roles maps process IDs to Atlas type names. A rule whose measurement is missing from a result,
+// or has no valid points, leaves that target's alert unchanged.
+//
+// A condition that holds starts a pending alert, which fires once the condition has held for the
+// rule's "for" duration (immediately if none). A condition that stops holding drops a pending
+// alert and resolves a firing one.
+func Evaluate(alerts map[string]Alert, rules []Rule, results []metrics.Result, roles map[string]string, now time.Time) {
+	for _, r := range rules {
+		c := r.cond
+		for _, res := range results {
+			role := roles[res.Target.ProcessID]
+			if res.Target.Kind != c.Kind || !c.AppliesTo(role) {
+				continue
+			}
+			s, ok := res.Series[c.Measurement]
+			if !ok {
+				continue
+			}
+			v, ok := c.Value(s)
+			if !ok {
+				continue
+			}
+
+			key := r.Name + "|" + res.Target.String()
+			a, exists := alerts[key]
+			if !c.Matches(v) {
+				switch {
+				case !exists:
+				case a.State == StatePending:
+					delete(alerts, key)
+				case a.State == StateFiring:
+					a.State, a.Value, a.ResolvedAt = StateResolved, v, now
+					alerts[key] = a
+				}
+				continue
+			}
+
+			if !exists || a.State == StateResolved {
+				a = Alert{Key: key, Rule: r.Name, Target: res.Target, State: StatePending, ActiveSince: now}
+			}
+			a.Severity, a.Summary, a.Condition, a.Role, a.Value = r.Severity, r.Summary, c.String(), role, v
+			if a.State == StatePending && now.Sub(a.ActiveSince) >= c.For {
+				a.State, a.FiredAt = StateFiring, now
+			}
+			alerts[key] = a
+		}
+	}
+}
+
+// Retire resolves firing alerts, and drops pending ones, whose target is no longer in scope of their
+// rule: the process, disk partition, or database was not discovered on this run (for example, a
+// process removed by scaling or a dropped database), or the process's type no longer matches the
+// rule's roles. discovered holds the String of every target found on this run, and roles maps
+// process IDs to their current Atlas type names. Alerts on a process whose partitions or databases
+// could not be listed, as reported in failed, are left unchanged.
+func Retire(alerts map[string]Alert, rules []Rule, discovered map[string]bool, failed []metrics.TargetError, roles map[string]string, now time.Time) {
+	conds := make(map[string]Condition, len(rules))
+	for _, r := range rules {
+		conds[r.Name] = r.cond
+	}
+	unknown := make(map[metrics.Target]bool, len(failed))
+	for _, e := range failed {
+		unknown[metrics.Target{Kind: e.Target.Kind, ProcessID: e.Target.ProcessID}] = true
+	}
+	for k, a := range alerts {
+		c, ok := conds[a.Rule]
+		if !ok || a.State == StateResolved || unknown[metrics.Target{Kind: a.Target.Kind, ProcessID: a.Target.ProcessID}] {
+			continue
+		}
+		role, exists := roles[a.Target.ProcessID]
+		if exists && discovered[a.Target.String()] && c.AppliesTo(role) {
+			continue
+		}
+		if a.State == StatePending {
+			delete(alerts, k)
+			continue
+		}
+		a.State, a.ResolvedAt = StateResolved, now
+		alerts[k] = a
+	}
+}
+
+// Prune drops alerts for rules that no longer exist and alerts resolved more than ResolvedRetention ago.
+func Prune(alerts map[string]Alert, rules []Rule, now time.Time) {
+	names := make(map[string]bool, len(rules))
+	for _, r := range rules {
+		names[r.Name] = true
+	}
+	for k, a := range alerts {
+		expired := a.State == StateResolved && now.Sub(a.ResolvedAt) > ResolvedRetention
+		if !names[a.Rule] || expired {
+			delete(alerts, k)
+		}
+	}
+}
+
+// Due returns the alerts that should be sent now, by key: alerts that fired or resolved since they
+// were last sent, and firing alerts whose rule's repeat interval has passed.
+func Due(alerts map[string]Alert, rules []Rule, now time.Time) []Alert {
+	repeat := make(map[string]time.Duration, len(rules))
+	for _, r := range rules {
+		repeat[r.Name] = r.RepeatInterval
+	}
+	var out []Alert
+	for _, k := range slices.Sorted(maps.Keys(alerts)) {
+		a := alerts[k]
+		switch a.State {
+		case StateFiring:
+			if a.LastNotified.Before(a.FiredAt) || (repeat[a.Rule] > 0 && now.Sub(a.LastNotified) >= repeat[a.Rule]) {
+				out = append(out, a)
+			}
+		case StateResolved:
+			// Only alerts that were sent while firing are sent when they resolve
+			if !a.LastNotified.IsZero() && a.LastNotified.Before(a.ResolvedAt) {
+				out = append(out, a)
+			}
+		}
+	}
+	return out
+}
+
+// ruleGroup is a set of rules whose measurements can be fetched together.
+type ruleGroup struct {
+	kind         string
+	window       time.Duration
+	rules        []Rule
+	measurements []string
+}
+
+// appliesTo reports whether any rule in the group covers a process type.
+func (g ruleGroup) appliesTo(role string) bool {
+	for _, r := range g.rules {
+		if r.cond.AppliesTo(role) {
+			return true
+		}
+	}
+	return false
+}
+
+// groupRules groups rules by target kind and window, in rule order.
+func groupRules(rules []Rule) []ruleGroup {
+	var groups []ruleGroup
+	index := make(map[string]int)
+	for _, r := range rules {
+		key := fmt.Sprintf("%s/%s", r.cond.Kind, r.cond.Window)
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, ruleGroup{kind: r.cond.Kind, window: r.cond.Window})
+		}
+		g := &groups[i]
+		g.rules = append(g.rules, r)
+		if !slices.Contains(g.measurements, r.cond.Measurement) {
+			g.measurements = append(g.measurements, r.cond.Measurement)
+		}
+	}
+	return groups
+}
+
+// isoMinutes formats a duration as an ISO 8601 period in whole minutes, rounding up.
+func isoMinutes(d time.Duration) string {
+	return fmt.Sprintf("PT%dM", int((d+time.Minute-1)/time.Minute))
+}
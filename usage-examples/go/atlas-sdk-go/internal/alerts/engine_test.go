@@ -0,0 +1,265 @@
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/atlas-sdk/v20250219001/admin"
+
+	"atlas-sdk-go/internal/metrics"
+)
+
+var t0 = time.Date(2025, 3, 2, 10, 0, 0, 0, time.UTC)
+
+func testSeries(name string, vals ...float64) metrics.Series {
+	s := metrics.Series{Name: name}
+	for i, v := range vals {
+		s.Points = append(s.Points, metrics.Point{Timestamp: t0.Add(time.Duration(i) * time.Minute), Value: v, Valid: true})
+	}
+	return s
+}
+
+func testRules(t *testing.T, yaml string) []Rule {
+	t.Helper()
+	rules, err := ParseRules([]byte(yaml))
+	require.NoError(t, err)
+	return rules
+}
+
+func processResult(id string, s metrics.Series) metrics.Result {
+	return metrics.Result{
+		Target: metrics.Target{Kind: metrics.TargetProcess, ProcessID: id},
+		Series: map[string]metrics.Series{s.Name: s},
+	}
+}
+
+func TestEvaluate_Lifecycle(t *testing.T) {
+	t.Parallel()
+	rules := testRules(t, `
+rules:
+  - name: scan-ratio
+    condition: QUERY_TARGETING_SCANNED_OBJECTS_PER_RETURNED p95 > 1000 for 30m on primaries
+`)
+	roles := map[string]string{"p:27017": "REPLICA_PRIMARY", "s:27017": "REPLICA_SECONDARY"}
+	high := []metrics.Result{
+		processResult("p:27017", testSeries("QUERY_TARGETING_SCANNED_OBJECTS_PER_RETURNED", 900, 1500, 2000)),
+		processResult("s:27017", testSeries("QUERY_TARGETING_SCANNED_OBJECTS_PER_RETURNED", 5000, 5000)),
+	}
+	low := []metrics.Result{
+		processResult("p:27017", testSeries("QUERY_TARGETING_SCANNED_OBJECTS_PER_RETURNED", 10, 20)),
+	}
+	key := "scan-ratio|p:27017"
+	alerts := make(map[string]Alert)
+
+	// Condition holds: pending until it has held for 30m; the secondary is out of scope
+	Evaluate(alerts, rules, high, roles, t0)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, StatePending, alerts[key].State)
+	assert.Equal(t, "REPLICA_PRIMARY", alerts[key].Role)
+	assert.Empty(t, Due(alerts, rules, t0))
+
+	Evaluate(alerts, rules, high, roles, t0.Add(20*time.Minute))
+	assert.Equal(t, StatePending, alerts[key].State)
+	assert.Equal(t, t0, alerts[key].ActiveSince)
+
+	Evaluate(alerts, rules, high, roles, t0.Add(30*time.Minute))
+	a := alerts[key]
+	assert.Equal(t, StateFiring, a.State)
+	assert.Equal(t, t0.Add(30*time.Minute), a.FiredAt)
+	due := Due(alerts, rules, t0.Add(30*time.Minute))
+	require.Len(t, due, 1)
+	assert.Equal(t, key, due[0].Key)
+
+	// Once sent, a firing alert is not sent again
+	a.LastNotified = t0.Add(30 * time.Minute)
+	alerts[key] = a
+	Evaluate(alerts, rules, high, roles, t0.Add(40*time.Minute))
+	assert.Empty(t, Due(alerts, rules, t0.Add(40*time.Minute)))
+
+	// No data leaves the alert unchanged
+	Evaluate(alerts, rules, nil, roles, t0.Add(45*time.Minute))
+	assert.Equal(t, StateFiring, alerts[key].State)
+
+	Evaluate(alerts, rules, low, roles, t0.Add(50*time.Minute))
+	assert.Equal(t, StateResolved, alerts[key].State)
+	due = Due(alerts, rules, t0.Add(50*time.Minute))
+	require.Len(t, due, 1)
+	assert.Equal(t, StateResolved, due[0].State)
+
+	// Holding again starts a new pending alert
+	Evaluate(alerts, rules, high, roles, t0.Add(60*time.Minute))
+	assert.Equal(t, StatePending, alerts[key].State)
+	assert.Equal(t, t0.Add(60*time.Minute), alerts[key].ActiveSince)
+	assert.True(t, alerts[key].LastNotified.IsZero())
+}
+
+func TestEvaluate_PendingDroppedWithoutFiring(t *testing.T) {
+	t.Parallel()
+	rules := testRules(t, "rules:\n  - name: conns\n    condition: CONNECTIONS max > 100 for 10m\n")
+	roles := map[string]string{"p:27017": "REPLICA_PRIMARY"}
+	alerts := make(map[string]Alert)
+
+	Evaluate(alerts, rules, []metrics.Result{processResult("p:27017", testSeries("CONNECTIONS", 150))}, roles, t0)
+	require.Len(t, alerts, 1)
+	Evaluate(alerts, rules, []metrics.Result{processResult("p:27017", testSeries("CONNECTIONS", 50))}, roles, t0.Add(5*time.Minute))
+	assert.Empty(t, alerts)
+}
+
+func TestDue_RepeatInterval(t *testing.T) {
+	t.Parallel()
+	rules := testRules(t, "rules:\n  - name: conns\n    condition: CONNECTIONS max > 100\n    repeat_interval: 1h\n")
+	alerts := map[string]Alert{"conns|p:27017": {
+		Key: "conns|p:27017", Rule: "conns", State: StateFiring, FiredAt: t0, LastNotified: t0,
+	}}
+	assert.Empty(t, Due(alerts, rules, t0.Add(59*time.Minute)))
+	assert.Len(t, Due(alerts, rules, t0.Add(time.Hour)), 1)
+}
+
+func TestPrune(t *testing.T) {
+	t.Parallel()
+	rules := testRules(t, "rules:\n  - name: conns\n    condition: CONNECTIONS max > 100\n")
+	alerts := map[string]Alert{
+		"conns|a:1":   {Rule: "conns", State: StateFiring},
+		"conns|b:1":   {Rule: "conns", State: StateResolved, ResolvedAt: t0.Add(-2 * time.Hour)},
+		"conns|c:1":   {Rule: "conns", State: StateResolved, ResolvedAt: t0.Add(-25 * time.Hour)},
+		"removed|a:1": {Rule: "removed", State: StateFiring},
+	}
+	Prune(alerts, rules, t0)
+	assert.Len(t, alerts, 2)
+	assert.Contains(t, alerts, "conns|a:1")
+	assert.Contains(t, alerts, "conns|b:1")
+}
+
+func TestRetire(t *testing.T) {
+	t.Parallel()
+	rules := testRules(t, `
+rules:
+  - name: conns
+    condition: CONNECTIONS max > 100 on primaries
+  - name: db-size
+    condition: DATABASE_DATA_SIZE latest > 1e9
+`)
+	process := func(id string) metrics.Target { return metrics.Target{Kind: metrics.TargetProcess, ProcessID: id} }
+	database := func(id, db string) metrics.Target {
+		return metrics.Target{Kind: metrics.TargetDatabase, ProcessID: id, Database: db}
+	}
+	alerts := map[string]Alert{
+		"conns|a:1":            {Rule: "conns", Target: process("a:1"), State: StateFiring},               // still in scope
+		"conns|gone:1":         {Rule: "conns", Target: process("gone:1"), State: StateFiring},            // removed by scaling
+		"conns|pending:1":      {Rule: "conns", Target: process("pending:1"), State: StatePending},        // removed while pending
+		"conns|b:1":            {Rule: "conns", Target: process("b:1"), State: StateFiring},               // now a secondary
+		"db-size|a:1/db/app":   {Rule: "db-size", Target: database("a:1", "app"), State: StateFiring},     // dropped database
+		"db-size|c:1/db/app":   {Rule: "db-size", Target: database("c:1", "app"), State: StateFiring},     // databases not listed
+		"db-size|a:1/db/other": {Rule: "db-size", Target: database("a:1", "other"), State: StateResolved}, // already resolved
+	}
+	roles := map[string]string{"a:1": "SHARD_PRIMARY", "b:1": "SHARD_SECONDARY", "c:1": "SHARD_PRIMARY"}
+	discovered := map[string]bool{"a:1": true, "b:1": true, "c:1": true}
+	failed := []metrics.TargetError{{Target: metrics.Target{Kind: metrics.TargetDatabase, ProcessID: "c:1"}}}
+
+	Retire(alerts, rules, discovered, failed, roles, t0)
+
+	assert.Equal(t, StateFiring, alerts["conns|a:1"].State)
+	assert.Equal(t, StateResolved, alerts["conns|gone:1"].State)
+	assert.Equal(t, t0, alerts["conns|gone:1"].ResolvedAt)
+	assert.NotContains(t, alerts, "conns|pending:1")
+	assert.Equal(t, StateResolved, alerts["conns|b:1"].State)
+	assert.Equal(t, StateResolved, alerts["db-size|a:1/db/app"].State)
+	assert.Equal(t, StateFiring, alerts["db-size|c:1/db/app"].State, "unknown targets keep their state")
+	assert.True(t, alerts["db-size|a:1/db/other"].ResolvedAt.IsZero())
+}
+
+// recordingNotifier records the alerts it is sent and fails while fail is set.
+type recordingNotifier struct {
+	sent [][]Alert
+	fail bool
+}
+
+func (n *recordingNotifier) Notify(_ context.Context, alerts []Alert) error {
+	if n.fail {
+		return fmt.Errorf("notifier unavailable")
+	}
+	n.sent = append(n.sent, alerts)
+	return nil
+}
+
+func TestEngine_Run(t *testing.T) {
+	t.Parallel()
+	var connections atomic.Int32
+	connections.Store(500)
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		var body any
+		switch p := strings.TrimPrefix(r.URL.Path, "/api/atlas/v2/groups/proj1/processes"); p {
+		case "":
+			body = admin.PaginatedHostViewAtlas{TotalCount: admin.PtrInt(2), Results: &[]admin.ApiHostViewAtlas{
+				{Id: admin.PtrString("p:27017"), TypeName: admin.PtrString("REPLICA_PRIMARY")},
+				{Id: admin.PtrString("s:27017"), TypeName: admin.PtrString("REPLICA_SECONDARY")},
+			}}
+		case "/p:27017/measurements":
+			assert.Equal(t, "PT5M", r.URL.Query().Get("period"))
+			body = admin.ApiMeasurementsGeneralViewAtlas{Measurements: &[]admin.MetricsMeasurementAtlas{{
+				Name:       admin.PtrString("CONNECTIONS"),
+				DataPoints: &[]admin.MetricDataPointAtlas{{Timestamp: admin.PtrTime(t0), Value: admin.PtrFloat32(float32(connections.Load()))}},
+			}}}
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			http.Error(w, `{"error":404}`, http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		assert.NoError(t, json.NewEncoder(w).Encode(body))
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	t.Cleanup(server.Close)
+	client, err := admin.NewClient(admin.UseBaseURL(server.URL))
+	require.NoError(t, err)
+
+	store := NewFileStateStore(filepath.Join(t.TempDir(), "alerts", "state.json"))
+	notifier := &recordingNotifier{fail: true}
+	e := &Engine{
+		Rules:     testRules(t, "rules:\n  - name: conns\n    condition: CONNECTIONS max > 100 over 5m on primaries\n"),
+		Store:     store,
+		Notifiers: []Notifier{notifier},
+	}
+	ctx := context.Background()
+
+	// A failed notification keeps the transition and is retried on the next run
+	_, err = e.Run(ctx, client, "proj1", t0)
+	require.Error(t, err)
+	saved, err := store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, StateFiring, saved["conns|p:27017"].State)
+
+	notifier.fail = false
+	res, err := e.Run(ctx, client, "proj1", t0.Add(time.Minute))
+	require.NoError(t, err)
+	require.Len(t, res.Notified, 1)
+	assert.Equal(t, StateFiring, res.Notified[0].State)
+	assert.Equal(t, t0, res.Notified[0].FiredAt)
+
+	// Still firing: deduplicated
+	res, err = e.Run(ctx, client, "proj1", t0.Add(2*time.Minute))
+	require.NoError(t, err)
+	assert.Empty(t, res.Notified)
+	require.Len(t, res.Alerts, 1)
+
+	connections.Store(10)
+	res, err = e.Run(ctx, client, "proj1", t0.Add(3*time.Minute))
+	require.NoError(t, err)
+	require.Len(t, res.Notified, 1)
+	assert.Equal(t, StateResolved, res.Notified[0].State)
+	assert.Len(t, notifier.sent, 2)
+
+	saved, err = store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, t0.Add(3*time.Minute), saved["conns|p:27017"].LastNotified)
+}
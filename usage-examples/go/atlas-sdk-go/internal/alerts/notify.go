@@ -0,0 +1,85 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"atlas-sdk-go/internal/errors"
+)
+
+// Notifier sends alerts that fired, repeated, or resolved. Alerts may be sent more than once
+// if a notifier fails, so receivers should deduplicate by key and state.
+type Notifier interface {
+	Notify(ctx context.Context, alerts []Alert) error
+}
+
+// StdoutNotifier writes one line per alert to Out (os.Stdout if nil).
+type StdoutNotifier struct {
+	Out io.Writer
+}
+
+// Notify writes the alerts.
+func (n StdoutNotifier) Notify(_ context.Context, alerts []Alert) error {
+	out := n.Out
+	if out == nil {
+		out = os.Stdout
+	}
+	for _, a := range alerts {
+		line := fmt.Sprintf("[%s] %s %s on %s: %s (value %.4g)", a.State, a.Severity, a.Rule, a.Target, a.Condition, a.Value)
+		if a.Summary != "" {
+			line += " - " + a.Summary
+		}
+		if _, err := fmt.Fprintln(out, line); err != nil {
+			return errors.WithContext(err, "writing alerts")
+		}
+	}
+	return nil
+}
+
+// WebhookPayload is the JSON body posted by WebhookNotifier.
+type WebhookPayload struct {
+	SentAt time.Time `json:"sentAt"`
+	Alerts []Alert   `json:"alerts"`
+}
+
+// WebhookNotifier posts alerts as JSON to a URL. Any status other than 2xx is an error.
+type WebhookNotifier struct {
+	URL     string
+	Headers map[string]string // e.g. an Authorization header
+	Client  *http.Client      // http.DefaultClient if nil
+}
+
+// Notify posts the alerts in a single request.
+func (n WebhookNotifier) Notify(ctx context.Context, alerts []Alert) error {
+	body, err := json.Marshal(WebhookPayload{SentAt: time.Now().UTC(), Alerts: alerts})
+	if err != nil {
+		return errors.WithContext(err, "encoding webhook payload")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.URL, bytes.NewReader(body))
+	if err != nil {
+		return errors.WithContext(err, "creating webhook request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range n.Headers {
+		req.Header.Set(k, v)
+	}
+	client := n.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.WithContext(err, "posting alerts to webhook")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("posting alerts to webhook: unexpected status %s", resp.Status)
+	}
+	return nil
+}
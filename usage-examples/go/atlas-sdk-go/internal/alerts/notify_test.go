@@ -0,0 +1,66 @@
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"atlas-sdk-go/internal/metrics"
+)
+
+var testAlert = Alert{
+	Key:       "scan-ratio|p:27017",
+	Rule:      "scan-ratio",
+	Severity:  "warning",
+	Summary:   "Queries scan far more documents than they return",
+	Condition: "QUERY_TARGETING_SCANNED_OBJECTS_PER_RETURNED p95 > 1000 over 10m",
+	Target:    metrics.Target{Kind: metrics.TargetProcess, ProcessID: "p:27017"},
+	State:     StateFiring,
+	Value:     1840,
+}
+
+func TestStdoutNotifier(t *testing.T) {
+	t.Parallel()
+	var sb strings.Builder
+	require.NoError(t, StdoutNotifier{Out: &sb}.Notify(context.Background(), []Alert{testAlert}))
+	assert.Equal(t, "[firing] warning scan-ratio on p:27017: QUERY_TARGETING_SCANNED_OBJECTS_PER_RETURNED p95 > 1000 over 10m (value 1840)"+
+		" - Queries scan far more documents than they return\n", sb.String())
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	t.Parallel()
+	var got WebhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(server.Close)
+
+	n := WebhookNotifier{URL: server.URL, Headers: map[string]string{"Authorization": "Bearer token"}}
+	require.NoError(t, n.Notify(context.Background(), []Alert{testAlert}))
+	require.Len(t, got.Alerts, 1)
+	assert.Equal(t, testAlert.Key, got.Alerts[0].Key)
+	assert.Equal(t, StateFiring, got.Alerts[0].State)
+	assert.False(t, got.SentAt.IsZero())
+}
+
+func TestWebhookNotifier_ErrorStatus(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "down", http.StatusBadGateway)
+	}))
+	t.Cleanup(server.Close)
+
+	err := WebhookNotifier{URL: server.URL}.Notify(context.Background(), []Alert{testAlert})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "502")
+}
@@ -0,0 +1,266 @@
+package alerts
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"atlas-sdk-go/internal/clusterutils"
+	"atlas-sdk-go/internal/errors"
+	"atlas-sdk-go/internal/metrics"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultWindow is the lookback a rule's statistic is computed over when the condition has no "over" clause.
+const DefaultWindow = 10 * time.Minute
+
+// Rule is a named alert condition read from a rules file.
+type Rule struct {
+	Name      string `yaml:"name" json:"name"`
+	Condition string `yaml:"condition" json:"condition"` // e.g. "QUERY_TARGETING_SCANNED_OBJECTS_PER_RETURNED p95 > 1000 for 30m on primaries"
+	Severity  string `yaml:"severity,omitempty" json:"severity,omitempty"`
+	Summary   string `yaml:"summary,omitempty" json:"summary,omitempty"`
+	// RepeatInterval re-sends a firing alert this often (default: only when it fires and resolves).
+	RepeatInterval time.Duration `yaml:"repeat_interval,omitempty" json:"repeatInterval,omitempty"`
+
+	cond Condition
+}
+
+// RuleFile is the top-level structure of a rules file.
+type RuleFile struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+// Condition is a parsed rule condition:
+//
+//	<MEASUREMENT> <stat> <op> <threshold> [over <window>] [for <duration>] [on <roles>]
+//
+// The statistic (mean, min, max, latest, or a percentile such as p95) is computed over the
+// last window of the measurement, in normalised units (seconds, bytes, bytes per second). The
+// condition must hold on consecutive runs for the "for" duration before the alert fires.
+// Roles limit the rule to processes of those types: primaries, secondaries, mongos, config, or
+// an Atlas type name such as REPLICA_PRIMARY.
+type Condition struct {
+	Measurement string
+	Kind        string // metrics.TargetProcess, TargetDisk, or TargetDatabase, from the measurement name
+	Stat        string
+	Op          string
+	Threshold   float64
+	Window      time.Duration
+	For         time.Duration
+	Roles       []string
+}
+
+// LoadRules reads and validates a YAML rules file.
+func LoadRules(filePath string) ([]Rule, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, &errors.NotFoundError{Resource: "alert rules file", ID: filePath}
+		}
+		return nil, errors.WithContext(err, "reading alert rules file")
+	}
+	return ParseRules(data)
+}
+
+// ParseRules parses and validates YAML rules. Rule names must be unique.
+func ParseRules(data []byte) ([]Rule, error) {
+	var f RuleFile
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, errors.WithContext(err, "parsing alert rules")
+	}
+	seen := make(map[string]bool, len(f.Rules))
+	for i := range f.Rules {
+		r := &f.Rules[i]
+		if r.Name == "" {
+			return nil, &errors.ValidationError{Message: fmt.Sprintf("rule %d: name is required", i)}
+		}
+		if seen[r.Name] {
+			return nil, &errors.ValidationError{Message: fmt.Sprintf("rule %q is defined more than once", r.Name)}
+		}
+		seen[r.Name] = true
+		cond, err := ParseCondition(r.Condition)
+		if err != nil {
+			return nil, errors.WithContext(err, fmt.Sprintf("rule %q", r.Name))
+		}
+		r.cond = cond
+	}
+	return f.Rules, nil
+}
+
+// ParsedCondition returns the rule's parsed condition.
+func (r Rule) ParsedCondition() Condition {
+	return r.cond
+}
+
+// ParseCondition parses a condition such as "CONNECTIONS max > 500 over 15m for 30m on primaries".
+func ParseCondition(s string) (Condition, error) {
+	invalid := func(format string, args ...any) (Condition, error) {
+		return Condition{}, &errors.ValidationError{Message: fmt.Sprintf("condition %q: ", s) + fmt.Sprintf(format, args...)}
+	}
+	f := strings.Fields(s)
+	if len(f) < 4 {
+		return invalid("want <MEASUREMENT> <stat> <op> <threshold>")
+	}
+	c := Condition{Measurement: strings.ToUpper(f[0]), Stat: strings.ToLower(f[1]), Op: f[2], Window: DefaultWindow}
+	c.Kind = measurementKind(c.Measurement)
+	if _, err := statistic(c.Stat); err != nil {
+		return invalid("%v", err)
+	}
+	if _, ok := ops[c.Op]; !ok {
+		return invalid("unknown operator %q", c.Op)
+	}
+	t, err := strconv.ParseFloat(f[3], 64)
+	if err != nil || math.IsNaN(t) {
+		return invalid("threshold %q is not a number", f[3])
+	}
+	c.Threshold = t
+
+	for rest := f[4:]; len(rest) > 0; {
+		keyword := strings.ToLower(rest[0])
+		if len(rest) < 2 {
+			return invalid("%q needs a value", keyword)
+		}
+		switch keyword {
+		case "over", "for":
+			d, err := time.ParseDuration(rest[1])
+			if err != nil || d < 0 {
+				return invalid("%q is not a duration", rest[1])
+			}
+			if keyword == "over" {
+				if d < time.Minute {
+					return invalid("window must be at least 1m")
+				}
+				c.Window = d
+			} else {
+				c.For = d
+			}
+			rest = rest[2:]
+		case "on":
+			// Roles run to the end of the condition, separated by commas or "and"
+			for _, tok := range rest[1:] {
+				for _, role := range strings.Split(tok, ",") {
+					if role != "" && !strings.EqualFold(role, "and") {
+						c.Roles = append(c.Roles, strings.ToLower(role))
+					}
+				}
+			}
+			rest = nil
+		default:
+			return invalid("unexpected %q (want over, for, or on)", rest[0])
+		}
+	}
+	return c, nil
+}
+
+// String formats the condition in the rules file syntax, with defaults filled in.
+func (c Condition) String() string {
+	s := fmt.Sprintf("%s %s %s %s over %s", c.Measurement, c.Stat, c.Op, strconv.FormatFloat(c.Threshold, 'g', -1, 64), formatDuration(c.Window))
+	if c.For > 0 {
+		s += " for " + formatDuration(c.For)
+	}
+	if len(c.Roles) > 0 {
+		s += " on " + strings.Join(c.Roles, ",")
+	}
+	return s
+}
+
+// Matches reports whether the condition holds for a value.
+func (c Condition) Matches(v float64) bool {
+	return ops[c.Op](v, c.Threshold)
+}
+
+// Value computes the condition's statistic over a series. It reports false if the series has no valid points.
+func (c Condition) Value(s metrics.Series) (float64, bool) {
+	stat, err := statistic(c.Stat)
+	if err != nil {
+		return 0, false
+	}
+	return stat(s)
+}
+
+// AppliesTo reports whether the condition covers a process with the given Atlas type name.
+func (c Condition) AppliesTo(role string) bool {
+	if len(c.Roles) == 0 {
+		return true
+	}
+	for _, r := range c.Roles {
+		var ok bool
+		switch r {
+		case "primary", "primaries":
+			ok = clusterutils.IsPrimary(role)
+		case "secondary", "secondaries":
+			ok = clusterutils.IsSecondary(role)
+		case "mongos":
+			ok = clusterutils.IsMongos(role)
+		case "config":
+			ok = clusterutils.IsConfig(role)
+		default:
+			ok = strings.EqualFold(r, role)
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// formatDuration formats d without trailing zero units, e.g. "30m" rather than "30m0s".
+func formatDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = s[:len(s)-2]
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = s[:len(s)-2]
+	}
+	return s
+}
+
+var ops = map[string]func(v, t float64) bool{
+	">":  func(v, t float64) bool { return v > t },
+	">=": func(v, t float64) bool { return v >= t },
+	"<":  func(v, t float64) bool { return v < t },
+	"<=": func(v, t float64) bool { return v <= t },
+	"==": func(v, t float64) bool { return v == t },
+	"!=": func(v, t float64) bool { return v != t },
+}
+
+// statistic returns the function that computes a named statistic over a series.
+func statistic(name string) (func(metrics.Series) (float64, bool), error) {
+	switch name {
+	case "mean", "avg":
+		return metrics.Series.Mean, nil
+	case "min":
+		return metrics.Series.Min, nil
+	case "max":
+		return metrics.Series.Max, nil
+	case "latest", "last":
+		return func(s metrics.Series) (float64, bool) {
+			p, ok := s.Latest()
+			return p.Value, ok
+		}, nil
+	}
+	if p, ok := strings.CutPrefix(name, "p"); ok {
+		if n, err := strconv.ParseFloat(p, 64); err == nil && n >= 0 && n <= 100 {
+			return func(s metrics.Series) (float64, bool) { return s.Percentile(n) }, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown statistic %q (want mean, min, max, latest, or p0-p100)", name)
+}
+
+// measurementKind returns the target kind that reports a measurement.
+func measurementKind(measurement string) string {
+	switch {
+	case strings.HasPrefix(measurement, "DISK_PARTITION_"):
+		return metrics.TargetDisk
+	case strings.HasPrefix(measurement, "DATABASE_"):
+		return metrics.TargetDatabase
+	default:
+		return metrics.TargetProcess
+	}
+}
@@ -0,0 +1,159 @@
+package alerts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	internalerrors "atlas-sdk-go/internal/errors"
+	"atlas-sdk-go/internal/metrics"
+)
+
+func TestParseCondition(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name    string
+		in      string
+		want    Condition
+		wantErr string
+	}{
+		{
+			name: "percentile for duration on primaries",
+			in:   "QUERY_TARGETING_SCANNED_OBJECTS_PER_RETURNED p95 > 1000 for 30m on primaries",
+			want: Condition{Measurement: "QUERY_TARGETING_SCANNED_OBJECTS_PER_RETURNED", Kind: metrics.TargetProcess,
+				Stat: "p95", Op: ">", Threshold: 1000, Window: DefaultWindow, For: 30 * time.Minute, Roles: []string{"primaries"}},
+		},
+		{
+			name: "disk measurement with window and several roles",
+			in:   "DISK_PARTITION_LATENCY_WRITE mean >= 0.05 over 15m on primaries, secondaries",
+			want: Condition{Measurement: "DISK_PARTITION_LATENCY_WRITE", Kind: metrics.TargetDisk,
+				Stat: "mean", Op: ">=", Threshold: 0.05, Window: 15 * time.Minute, Roles: []string{"primaries", "secondaries"}},
+		},
+		{
+			name: "database measurement",
+			in:   "DATABASE_DATA_SIZE latest > 1e10",
+			want: Condition{Measurement: "DATABASE_DATA_SIZE", Kind: metrics.TargetDatabase,
+				Stat: "latest", Op: ">", Threshold: 1e10, Window: DefaultWindow},
+		},
+		{name: "too short", in: "CONNECTIONS max >", wantErr: "want <MEASUREMENT>"},
+		{name: "unknown stat", in: "CONNECTIONS median > 5", wantErr: "unknown statistic"},
+		{name: "percentile out of range", in: "CONNECTIONS p101 > 5", wantErr: "unknown statistic"},
+		{name: "unknown operator", in: "CONNECTIONS max => 5", wantErr: "unknown operator"},
+		{name: "bad threshold", in: "CONNECTIONS max > lots", wantErr: "not a number"},
+		{name: "bad duration", in: "CONNECTIONS max > 5 for soon", wantErr: "not a duration"},
+		{name: "short window", in: "CONNECTIONS max > 5 over 30s", wantErr: "at least 1m"},
+		{name: "unexpected clause", in: "CONNECTIONS max > 5 during 5m", wantErr: "unexpected"},
+		{name: "missing value", in: "CONNECTIONS max > 5 for", wantErr: "needs a value"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParseCondition(tc.in)
+			if tc.wantErr != "" {
+				require.Error(t, err)
+				var ve *internalerrors.ValidationError
+				assert.ErrorAs(t, err, &ve)
+				assert.Contains(t, err.Error(), tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestCondition_String(t *testing.T) {
+	t.Parallel()
+	c, err := ParseCondition("connections P99 >= 500 for 1h on primaries")
+	require.NoError(t, err)
+	assert.Equal(t, "CONNECTIONS p99 >= 500 over 10m for 1h on primaries", c.String())
+}
+
+func TestCondition_AppliesTo(t *testing.T) {
+	t.Parallel()
+	c, err := ParseCondition("CONNECTIONS max > 5 on primaries,mongos")
+	require.NoError(t, err)
+	assert.True(t, c.AppliesTo("REPLICA_PRIMARY"))
+	assert.True(t, c.AppliesTo("SHARD_PRIMARY"))
+	assert.True(t, c.AppliesTo("SHARD_MONGOS"))
+	assert.False(t, c.AppliesTo("REPLICA_SECONDARY"))
+	assert.False(t, c.AppliesTo("SHARD_CONFIG"), "config servers have no primary state")
+
+	c, err = ParseCondition("CONNECTIONS max > 5 on config and REPLICA_SECONDARY")
+	require.NoError(t, err)
+	assert.True(t, c.AppliesTo("SHARD_CONFIG"))
+	assert.True(t, c.AppliesTo("REPLICA_SECONDARY"))
+	assert.False(t, c.AppliesTo("SHARD_SECONDARY"))
+	assert.False(t, c.AppliesTo("REPLICA_PRIMARY"))
+
+	c, err = ParseCondition("CONNECTIONS max > 5 on secondaries")
+	require.NoError(t, err)
+	assert.True(t, c.AppliesTo("SHARD_SECONDARY"))
+
+	c, err = ParseCondition("CONNECTIONS max > 5")
+	require.NoError(t, err)
+	assert.True(t, c.AppliesTo("RECOVERING"))
+}
+
+func TestCondition_Value(t *testing.T) {
+	t.Parallel()
+	s := testSeries("CONNECTIONS", 10, 20, 30, 40, 50)
+	for stat, want := range map[string]float64{"mean": 30, "min": 10, "max": 50, "latest": 50, "p50": 30, "p75": 40} {
+		c, err := ParseCondition("CONNECTIONS " + stat + " > 0")
+		require.NoError(t, err)
+		got, ok := c.Value(s)
+		require.True(t, ok, stat)
+		assert.InDelta(t, want, got, 1e-9, stat)
+	}
+
+	c, err := ParseCondition("CONNECTIONS mean > 0")
+	require.NoError(t, err)
+	_, ok := c.Value(metrics.Series{Name: "CONNECTIONS"})
+	assert.False(t, ok)
+}
+
+func TestLoadRules(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+rules:
+  - name: scan-ratio
+    condition: QUERY_TARGETING_SCANNED_OBJECTS_PER_RETURNED p95 > 1000 for 30m on primaries
+    severity: warning
+    summary: Queries scan far more documents than they return
+    repeat_interval: 4h
+  - name: disk-latency
+    condition: DISK_PARTITION_LATENCY_WRITE mean > 0.05
+`), 0644))
+
+	rules, err := LoadRules(path)
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+	assert.Equal(t, "scan-ratio", rules[0].Name)
+	assert.Equal(t, "warning", rules[0].Severity)
+	assert.Equal(t, 4*time.Hour, rules[0].RepeatInterval)
+	assert.Equal(t, 30*time.Minute, rules[0].ParsedCondition().For)
+	assert.Equal(t, metrics.TargetDisk, rules[1].ParsedCondition().Kind)
+
+	_, err = LoadRules(filepath.Join(t.TempDir(), "missing.yaml"))
+	var nf *internalerrors.NotFoundError
+	assert.ErrorAs(t, err, &nf)
+}
+
+func TestParseRules_Invalid(t *testing.T) {
+	t.Parallel()
+	cases := map[string]string{
+		"missing name":   "rules:\n  - condition: CONNECTIONS max > 5\n",
+		"duplicate name": "rules:\n  - name: a\n    condition: CONNECTIONS max > 5\n  - name: a\n    condition: CONNECTIONS max > 6\n",
+		"bad condition":  "rules:\n  - name: a\n    condition: CONNECTIONS\n",
+		"bad yaml":       "rules: [",
+	}
+	for name, in := range cases {
+		_, err := ParseRules([]byte(in))
+		assert.Error(t, err, name)
+	}
+}
@@ -5,12 +5,13 @@ import (
 	"fmt"
 	"time"
 
-	"atlas-sdk-go/internal/clusterutils"
+	"atlas-sdk-go/internal/config"
+	"atlas-sdk-go/internal/connect"
+	"atlas-sdk-go/internal/errors"
 
 	"go.mongodb.org/atlas-sdk/v20250219001/admin"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 // Candidate represents a collection eligible for archiving
@@ -95,27 +96,29 @@ type CollectionStat struct {
 }
 
 // ListCollectionsWithCounts connects to the cluster with the MongoDB Go Driver and returns a flat list of collections
-// with their estimated document counts.
+// with their estimated document counts. The connection string type, credentials, and TLS settings come from db
+// and secrets (see connect.ForCluster). It returns an error if the connection cannot be resolved or opened;
+// databases and collections that cannot be read are skipped.
 // NOTE: This function intentionally applies no filtering or demo criteria to decide what qualifies as a candidate.
-func ListCollectionsWithCounts(ctx context.Context, sdk *admin.APIClient, projectID, clusterName string) []CollectionStat {
+func ListCollectionsWithCounts(ctx context.Context, sdk *admin.APIClient, projectID, clusterName string,
+	db config.DatabaseConfig, secrets config.Secrets) ([]CollectionStat, error) {
 	stats := make([]CollectionStat, 0)
 
-	// Get the SRV connection string for the cluster
-	srv, err := clusterutils.GetClusterSRVConnectionString(ctx, sdk, projectID, clusterName)
-	if err != nil || srv == "" {
-		return stats
+	// Resolve the connection string and driver options for the cluster
+	clientOpts, _, err := connect.ForCluster(ctx, sdk, projectID, clusterName, db, secrets)
+	if err != nil {
+		return nil, errors.WithContext(err, "resolving connection for cluster "+clusterName)
 	}
 
 	ctxConn, cancel := context.WithTimeout(ctx, 2*time.Second)
 	defer cancel()
-	clientOpts := options.Client().ApplyURI(srv).
-		SetServerSelectionTimeout(2 * time.Second).
+	clientOpts.SetServerSelectionTimeout(2 * time.Second).
 		SetConnectTimeout(2 * time.Second)
 
 	// Connect to the cluster using the official MongoDB Go Driver
 	client, err := mongo.Connect(ctxConn, clientOpts)
 	if err != nil {
-		return stats
+		return nil, errors.FormatError("connect to cluster", clusterName, err)
 	}
 	defer func() { _ = client.Disconnect(context.Background()) }()
 
@@ -123,7 +126,7 @@ func ListCollectionsWithCounts(ctx context.Context, sdk *admin.APIClient, projec
 
 	dbNames, err := client.ListDatabaseNames(ctx, bson.D{})
 	if err != nil {
-		return stats
+		return nil, errors.FormatError("list databases", clusterName, err)
 	}
 
 	for _, dbName := range dbNames {
@@ -145,5 +148,5 @@ func ListCollectionsWithCounts(ctx context.Context, sdk *admin.APIClient, projec
 			})
 		}
 	}
-	return stats
+	return stats, nil
 }
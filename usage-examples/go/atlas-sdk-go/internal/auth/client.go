@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"reflect"
 
 	"atlas-sdk-go/internal/config"
 	"atlas-sdk-go/internal/errors"
@@ -12,7 +13,7 @@ import (
 // NewClient initializes and returns an authenticated Atlas API client using OAuth2 with service account credentials (recommended)
 // See: https://www.mongodb.com/docs/atlas/architecture/current/auth/#service-accounts
 func NewClient(ctx context.Context, cfg config.Config, secrets config.Secrets) (*admin.APIClient, error) {
-	if cfg == (config.Config{}) {
+	if reflect.DeepEqual(cfg, config.Config{}) {
 		return nil, &errors.ValidationError{Message: "config cannot be empty"}
 	}
 	if secrets.ServiceAccountID() == "" || secrets.ServiceAccountSecret() == "" {
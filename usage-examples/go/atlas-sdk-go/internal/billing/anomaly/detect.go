@@ -0,0 +1,390 @@
+package anomaly
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"atlas-sdk-go/internal/billing"
+	"atlas-sdk-go/internal/errors"
+)
+
+// Method selects how the baseline for each day is computed.
+type Method string
+
+const (
+	// MedianMAD compares each day against the median of the baseline window, scaled by the
+	// median absolute deviation. It is robust to earlier spikes in the window.
+	MedianMAD Method = "median_mad"
+	// EWMA compares each day against an exponentially weighted moving average and variance,
+	// so recent days count more than older ones.
+	EWMA Method = "ewma"
+)
+
+// Kind describes why a day was flagged.
+type Kind string
+
+const (
+	Spike  Kind = "spike"   // Daily cost is well above its baseline
+	NewSKU Kind = "new_sku" // A SKU started billing that wasn't billed before in the same series
+)
+
+// Options configures anomaly detection sensitivity.
+// Zero values fall back to DefaultOptions, except MinIncrease and NewSKUMinCost where zero is
+// a meaningful setting; start from DefaultOptions() to get the recommended values for those.
+type Options struct {
+	Method         Method  // Baseline method (default: MedianMAD)
+	Window         int     // Number of prior days in the baseline (default: 7)
+	MinHistory     int     // Minimum prior days required before a day can be flagged (default: 3)
+	Threshold      float64 // Score at or above which a day is a spike (default: 3.5)
+	MinIncrease    float64 // Minimum increase over baseline, in dollars, to flag a spike (DefaultOptions: 10)
+	Alpha          float64 // EWMA smoothing factor between 0 and 1 (default: 0.3)
+	NewSKUMinCost  float64 // Minimum daily cost, in dollars, for a new SKU to be flagged (DefaultOptions: 0, flag all)
+	DisableNewSKUs bool    // If true, new SKUs are not flagged
+	MaxDrivers     int     // Maximum line items listed per flag (default: 5)
+}
+
+// DefaultOptions returns the recommended detection settings.
+func DefaultOptions() Options {
+	return Options{
+		Method:      MedianMAD,
+		Window:      7,
+		MinHistory:  3,
+		Threshold:   3.5,
+		MinIncrease: 10,
+		Alpha:       0.3,
+		MaxDrivers:  5,
+	}
+}
+
+// Driver is a SKU that contributed to a flagged day, with its cost that day and its typical cost.
+type Driver struct {
+	SKU      string  `json:"sku"`
+	Cost     float64 `json:"cost"`
+	Baseline float64 `json:"baseline"`
+	Increase float64 `json:"increase"`
+}
+
+// Anomaly is a flagged day for one project, cluster, and category series.
+type Anomaly struct {
+	Kind        Kind      `json:"kind"`
+	Date        time.Time `json:"date"`
+	Project     string    `json:"project"`
+	Cluster     string    `json:"cluster"`
+	Category    string    `json:"category"`
+	Cost        float64   `json:"cost"`
+	Baseline    float64   `json:"baseline"`
+	Increase    float64   `json:"increase"`
+	Score       float64   `json:"score"`
+	Drivers     []Driver  `json:"drivers"`
+	Explanation string    `json:"explanation"`
+}
+
+// seriesKey identifies a daily cost series.
+type seriesKey struct {
+	project  string
+	cluster  string
+	category string
+}
+
+// series holds daily totals for one key, indexed by day offset from the dataset start.
+type series struct {
+	key   seriesKey
+	total []float64
+	bySKU map[string][]float64
+}
+
+// Detect builds a daily cost series for each project, cluster, and category, compares each day
+// against a rolling baseline of the preceding days, and returns the days that cross the configured
+// thresholds along with the SKUs driving them. Days with no cost are treated as zero. A spike on
+// the same day as a new SKU in the same series is reported only as the new SKU.
+// Results are sorted by date, then by increase (largest first), series, kind, and SKU.
+func Detect(details []billing.Detail, opts Options) ([]Anomaly, error) {
+	opts, err := applyDefaults(opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(details) == 0 {
+		return nil, nil
+	}
+
+	start, days := dayRange(details)
+	all := buildSeries(details, start, days)
+
+	var anomalies []Anomaly
+	for _, s := range all {
+		var newSKUs []Anomaly
+		if !opts.DisableNewSKUs {
+			newSKUs = detectNewSKUs(s, start, opts)
+		}
+		for _, spike := range detectSpikes(s, start, opts) {
+			if !slices.ContainsFunc(newSKUs, func(a Anomaly) bool { return a.Date.Equal(spike.Date) }) {
+				anomalies = append(anomalies, spike)
+			}
+		}
+		anomalies = append(anomalies, newSKUs...)
+	}
+
+	slices.SortFunc(anomalies, func(a, b Anomaly) int {
+		if c := a.Date.Compare(b.Date); c != 0 {
+			return c
+		}
+		if a.Increase != b.Increase {
+			if a.Increase > b.Increase {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Or(
+			strings.Compare(a.Project+a.Cluster+a.Category, b.Project+b.Cluster+b.Category),
+			strings.Compare(string(a.Kind), string(b.Kind)),
+			strings.Compare(firstSKU(a), firstSKU(b)),
+		)
+	})
+	return anomalies, nil
+}
+
+// detectSpikes flags days in s whose cost is well above the baseline of the preceding days.
+func detectSpikes(s *series, start time.Time, opts Options) []Anomaly {
+	var out []Anomaly
+	for day := opts.MinHistory; day < len(s.total); day++ {
+		from := max(0, day-opts.Window)
+		history := s.total[from:day]
+
+		baseline, scale := baselineAndScale(history, s.total[:day], opts)
+		cost := s.total[day]
+		increase := cost - baseline
+		if increase < opts.MinIncrease {
+			continue
+		}
+		score := increase / scale
+		if score < opts.Threshold {
+			continue
+		}
+
+		drivers := topDrivers(s, from, day, opts.MaxDrivers)
+		out = append(out, Anomaly{
+			Kind:     Spike,
+			Date:     start.AddDate(0, 0, day),
+			Project:  s.key.project,
+			Cluster:  s.key.cluster,
+			Category: s.key.category,
+			Cost:     cost,
+			Baseline: baseline,
+			Increase: increase,
+			Score:    score,
+			Drivers:  drivers,
+			Explanation: fmt.Sprintf("%s cost $%.2f vs baseline $%.2f (+$%.2f, score %.1f); %s",
+				s.key.category, cost, baseline, increase, score, describeDrivers(drivers)),
+		})
+	}
+	return out
+}
+
+// detectNewSKUs flags the first day each SKU bills in s, unless fewer than MinHistory days of data
+// precede it, since the SKU may simply have been billing before the data starts.
+func detectNewSKUs(s *series, start time.Time, opts Options) []Anomaly {
+	var out []Anomaly
+	for sku, costs := range s.bySKU {
+		first := slices.IndexFunc(costs, func(c float64) bool { return c != 0 })
+		if first < opts.MinHistory || costs[first] < opts.NewSKUMinCost {
+			continue
+		}
+		driver := Driver{SKU: sku, Cost: costs[first], Increase: costs[first]}
+		out = append(out, Anomaly{
+			Kind:     NewSKU,
+			Date:     start.AddDate(0, 0, first),
+			Project:  s.key.project,
+			Cluster:  s.key.cluster,
+			Category: s.key.category,
+			Cost:     costs[first],
+			Increase: costs[first],
+			Drivers:  []Driver{driver},
+			Explanation: fmt.Sprintf("new SKU %s started billing $%.2f/day in %s",
+				sku, costs[first], s.key.category),
+		})
+	}
+	return out
+}
+
+// firstSKU returns the SKU of an anomaly's top driver, or "" if it has none.
+func firstSKU(a Anomaly) string {
+	if len(a.Drivers) == 0 {
+		return ""
+	}
+	return a.Drivers[0].SKU
+}
+
+// baselineAndScale returns the expected cost and the spread used to score a day.
+// history is the baseline window; full is every prior day, used by EWMA.
+func baselineAndScale(history, full []float64, opts Options) (float64, float64) {
+	var baseline, scale float64
+	switch opts.Method {
+	case EWMA:
+		mean, variance := full[0], 0.0
+		for _, x := range full[1:] {
+			diff := x - mean
+			mean += opts.Alpha * diff
+			variance = (1 - opts.Alpha) * (variance + opts.Alpha*diff*diff)
+		}
+		baseline, scale = mean, math.Sqrt(variance)
+	default:
+		baseline = median(history)
+		deviations := make([]float64, len(history))
+		for i, x := range history {
+			deviations[i] = math.Abs(x - baseline)
+		}
+		// 1.4826 scales the MAD to be comparable to a standard deviation for normal data
+		scale = 1.4826 * median(deviations)
+	}
+
+	// A perfectly flat history has no spread; fall back to a fraction of the baseline
+	// so that any large enough increase still scores as a spike
+	if scale == 0 {
+		scale = math.Max(0.1*math.Abs(baseline), 0.01)
+	}
+	return baseline, scale
+}
+
+// topDrivers returns the SKUs whose cost on day rose the most over their median in [from, day).
+func topDrivers(s *series, from, day, limit int) []Driver {
+	drivers := make([]Driver, 0, len(s.bySKU))
+	for sku, costs := range s.bySKU {
+		base := 0.0
+		if day > from {
+			base = median(costs[from:day])
+		}
+		inc := costs[day] - base
+		if inc <= 0 {
+			continue
+		}
+		drivers = append(drivers, Driver{SKU: sku, Cost: costs[day], Baseline: base, Increase: inc})
+	}
+	slices.SortFunc(drivers, func(a, b Driver) int {
+		if a.Increase != b.Increase {
+			if a.Increase > b.Increase {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.SKU, b.SKU)
+	})
+	if len(drivers) > limit {
+		drivers = drivers[:limit]
+	}
+	return drivers
+}
+
+// describeDrivers summarizes drivers for an explanation string.
+func describeDrivers(drivers []Driver) string {
+	if len(drivers) == 0 {
+		return "no single SKU increased"
+	}
+	parts := make([]string, len(drivers))
+	for i, d := range drivers {
+		parts[i] = fmt.Sprintf("%s +$%.2f", d.SKU, d.Increase)
+	}
+	return "driven by " + strings.Join(parts, ", ")
+}
+
+// buildSeries groups details into daily series keyed by project, cluster, and category.
+func buildSeries(details []billing.Detail, start time.Time, days int) []*series {
+	index := make(map[seriesKey]*series)
+	var ordered []*series
+	for _, d := range details {
+		key := seriesKey{
+			project:  cmp.Or(d.Project.Name, d.Project.ID),
+			cluster:  d.Cluster,
+			category: d.Category,
+		}
+		s, ok := index[key]
+		if !ok {
+			s = &series{key: key, total: make([]float64, days), bySKU: make(map[string][]float64)}
+			index[key] = s
+			ordered = append(ordered, s)
+		}
+		day := dayOffset(start, d.Date)
+		s.total[day] += d.Cost
+		if _, ok := s.bySKU[d.SKU]; !ok {
+			s.bySKU[d.SKU] = make([]float64, days)
+		}
+		s.bySKU[d.SKU][day] += d.Cost
+	}
+	return ordered
+}
+
+// dayRange returns the first day (UTC midnight) in details and the number of days spanned.
+func dayRange(details []billing.Detail) (time.Time, int) {
+	first, last := truncateDay(details[0].Date), truncateDay(details[0].Date)
+	for _, d := range details[1:] {
+		day := truncateDay(d.Date)
+		if day.Before(first) {
+			first = day
+		}
+		if day.After(last) {
+			last = day
+		}
+	}
+	return first, dayOffset(first, last) + 1
+}
+
+// dayOffset returns the number of whole days between start and t.
+func dayOffset(start, t time.Time) int {
+	return int(truncateDay(t).Sub(start).Hours() / 24)
+}
+
+// truncateDay returns midnight UTC on the day of t.
+func truncateDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// median returns the median of values, or zero for an empty slice.
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// applyDefaults fills unset options and validates the rest.
+func applyDefaults(opts Options) (Options, error) {
+	def := DefaultOptions()
+	if opts.Method == "" {
+		opts.Method = def.Method
+	}
+	if opts.Method != MedianMAD && opts.Method != EWMA {
+		return opts, &errors.ValidationError{Message: fmt.Sprintf("unsupported anomaly method %q", opts.Method)}
+	}
+	if opts.Window <= 0 {
+		opts.Window = def.Window
+	}
+	if opts.MinHistory <= 0 {
+		opts.MinHistory = def.MinHistory
+	}
+	if opts.Threshold <= 0 {
+		opts.Threshold = def.Threshold
+	}
+	if opts.MinIncrease < 0 {
+		return opts, &errors.ValidationError{Message: "minimum increase cannot be negative"}
+	}
+	if opts.Alpha == 0 {
+		opts.Alpha = def.Alpha
+	}
+	if opts.Alpha < 0 || opts.Alpha > 1 {
+		return opts, &errors.ValidationError{Message: "EWMA alpha must be between 0 and 1"}
+	}
+	if opts.MaxDrivers <= 0 {
+		opts.MaxDrivers = def.MaxDrivers
+	}
+	return opts, nil
+}
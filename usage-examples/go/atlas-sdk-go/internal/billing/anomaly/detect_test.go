@@ -0,0 +1,144 @@
+package anomaly
+
+import (
+	"testing"
+	"time"
+
+	"atlas-sdk-go/internal/billing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var day0 = time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+func item(day int, cluster, sku string, cost float64) billing.Detail {
+	return billing.Detail{
+		Project:  billing.ProjectInfo{ID: "p1", Name: "prod"},
+		Cluster:  cluster,
+		SKU:      sku,
+		Category: "Clusters",
+		Cost:     cost,
+		Date:     day0.AddDate(0, 0, day),
+	}
+}
+
+// steadyWithSpike returns ten days of ~$50/day for Cluster0 with a large extra SKU on day 8
+func steadyWithSpike() []billing.Detail {
+	var details []billing.Detail
+	for d := 0; d < 10; d++ {
+		details = append(details, item(d, "Cluster0", "ATLAS_AWS_INSTANCE_M30", 50+float64(d%3)))
+	}
+	details = append(details, item(8, "Cluster0", "ATLAS_AWS_INSTANCE_M200", 400))
+	return details
+}
+
+func TestDetect_MedianMADFlagsSpikeWithDrivers(t *testing.T) {
+	t.Parallel()
+	opts := DefaultOptions()
+	opts.DisableNewSKUs = true
+
+	anomalies, err := Detect(steadyWithSpike(), opts)
+
+	require.NoError(t, err)
+	var spikes []Anomaly
+	for _, a := range anomalies {
+		if a.Kind == Spike {
+			spikes = append(spikes, a)
+		}
+	}
+	require.Len(t, spikes, 1)
+	spike := spikes[0]
+	assert.Equal(t, day0.AddDate(0, 0, 8), spike.Date)
+	assert.Equal(t, "Cluster0", spike.Cluster)
+	assert.Greater(t, spike.Increase, 390.0)
+	require.NotEmpty(t, spike.Drivers)
+	assert.Equal(t, "ATLAS_AWS_INSTANCE_M200", spike.Drivers[0].SKU, "the new instance SKU should drive the spike")
+	assert.Contains(t, spike.Explanation, "ATLAS_AWS_INSTANCE_M200")
+}
+
+func TestDetect_FlagsNewSKU(t *testing.T) {
+	t.Parallel()
+
+	anomalies, err := Detect(steadyWithSpike(), DefaultOptions())
+
+	require.NoError(t, err)
+	require.Len(t, anomalies, 1, "the spike caused by the new SKU is not reported twice")
+	assert.Equal(t, NewSKU, anomalies[0].Kind)
+	assert.Equal(t, "ATLAS_AWS_INSTANCE_M200", anomalies[0].Drivers[0].SKU)
+	assert.Equal(t, day0.AddDate(0, 0, 8), anomalies[0].Date)
+}
+
+func TestDetect_NewSKURequiresMinHistory(t *testing.T) {
+	t.Parallel()
+	details := []billing.Detail{
+		item(0, "Cluster0", "ATLAS_AWS_INSTANCE_M30", 50),
+		item(1, "Cluster0", "ATLAS_AWS_INSTANCE_M30", 50),
+		item(1, "Cluster0", "ATLAS_AWS_BACKUP", 5),
+		item(3, "Cluster0", "ATLAS_AWS_INSTANCE_M30", 50),
+		item(3, "Cluster0", "ATLAS_AWS_STORAGE", 5),
+		item(3, "Cluster0", "ATLAS_AWS_DATA_TRANSFER", 5),
+	}
+
+	anomalies, err := Detect(details, DefaultOptions())
+
+	require.NoError(t, err)
+	require.Len(t, anomalies, 2, "a SKU first seen on day 1 may have billed before the data starts")
+	assert.Equal(t, "ATLAS_AWS_DATA_TRANSFER", anomalies[0].Drivers[0].SKU, "ties are sorted by SKU")
+	assert.Equal(t, "ATLAS_AWS_STORAGE", anomalies[1].Drivers[0].SKU)
+}
+
+func TestDetect_EWMA(t *testing.T) {
+	t.Parallel()
+	opts := DefaultOptions()
+	opts.Method = EWMA
+	opts.DisableNewSKUs = true
+
+	anomalies, err := Detect(steadyWithSpike(), opts)
+
+	require.NoError(t, err)
+	require.Len(t, anomalies, 1)
+	assert.Equal(t, Spike, anomalies[0].Kind)
+}
+
+func TestDetect_SteadySpendHasNoAnomalies(t *testing.T) {
+	t.Parallel()
+	var details []billing.Detail
+	for d := 0; d < 14; d++ {
+		details = append(details, item(d, "Cluster0", "ATLAS_AWS_INSTANCE_M30", 50))
+	}
+
+	anomalies, err := Detect(details, DefaultOptions())
+
+	require.NoError(t, err)
+	assert.Empty(t, anomalies)
+}
+
+func TestDetect_MinIncreaseSuppressesSmallSpikes(t *testing.T) {
+	t.Parallel()
+	var details []billing.Detail
+	for d := 0; d < 10; d++ {
+		details = append(details, item(d, "Cluster0", "ATLAS_AWS_INSTANCE_M10", 1))
+	}
+	details = append(details, item(9, "Cluster0", "ATLAS_AWS_INSTANCE_M10", 4))
+	opts := DefaultOptions()
+	opts.DisableNewSKUs = true
+
+	anomalies, err := Detect(details, opts)
+	require.NoError(t, err)
+	assert.Empty(t, anomalies, "a $4 increase is below the default $10 minimum")
+
+	opts.MinIncrease = 1
+	anomalies, err = Detect(details, opts)
+	require.NoError(t, err)
+	assert.Len(t, anomalies, 1)
+}
+
+func TestDetect_InvalidOptions(t *testing.T) {
+	t.Parallel()
+	_, err := Detect(nil, Options{Method: "prophet"})
+	require.Error(t, err)
+
+	_, err = Detect(nil, Options{Alpha: 2})
+	require.Error(t, err)
+}
@@ -0,0 +1,237 @@
+package budget
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"atlas-sdk-go/internal/billing"
+	"atlas-sdk-go/internal/config"
+	"atlas-sdk-go/internal/errors"
+)
+
+const defaultWarnPercent = 80.0
+
+// Status summarizes where a budget stands.
+type Status string
+
+const (
+	OnTrack  Status = "on_track" // Projected spend stays under the warning threshold
+	Warning  Status = "warning"  // Projected spend crosses the warning threshold but not the limit
+	Overrun  Status = "overrun"  // Projected spend exceeds the limit
+	Exceeded Status = "exceeded" // Spend to date already exceeds the limit
+)
+
+// Forecast is the month-end projection for a single budget.
+type Forecast struct {
+	Budget             string     `json:"budget"`
+	Scope              string     `json:"scope"`
+	ID                 string     `json:"id"`
+	Month              string     `json:"month"`              // YYYY-MM
+	MonthlyLimit       float64    `json:"monthlyLimit"`       // USD
+	ChargesToDate      float64    `json:"chargesToDate"`      // Usage charges so far, excluding credits
+	CreditsToDate      float64    `json:"creditsToDate"`      // Credits and adjustments applied so far
+	SpendToDate        float64    `json:"spendToDate"`        // Net spend so far
+	DailyRunRate       float64    `json:"dailyRunRate"`       // Day-weighted average daily charges
+	ElapsedDays        float64    `json:"elapsedDays"`        // Fractional days elapsed in the month
+	DaysInMonth        int        `json:"daysInMonth"`        // Calendar days in the month
+	ProjectedSpend     float64    `json:"projectedSpend"`     // Net spend projected for the full month
+	UtilisationPct     float64    `json:"utilisationPct"`     // SpendToDate as a percentage of the limit
+	ProjectedPct       float64    `json:"projectedPct"`       // ProjectedSpend as a percentage of the limit
+	ProjectedOverrun   float64    `json:"projectedOverrun"`   // Amount ProjectedSpend exceeds the limit, if any
+	BudgetCrossingDate *time.Time `json:"budgetCrossingDate"` // Day the limit was or is projected to be crossed
+	Status             Status     `json:"status"`
+}
+
+// ForecastAll projects month-end spend for each budget from the month-to-date line items in details
+// (typically the pending invoice). Only line items in the month containing now are counted.
+//
+// The daily run rate is a day-weighted average of completed days' charges, where each day's
+// weight is its day of the month, so recent days count more than the start of the month. The
+// current, partial day is included in spend to date and only the remaining fraction of it is
+// projected. Credits are counted as applied but are not extrapolated, since they are one-off adjustments.
+func ForecastAll(details []billing.Detail, budgets []config.Budget, now time.Time) ([]Forecast, error) {
+	out := make([]Forecast, 0, len(budgets))
+	for _, b := range budgets {
+		f, err := ForecastBudget(details, b, now)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, f)
+	}
+	return out, nil
+}
+
+// ForecastBudget projects month-end spend for a single budget. See ForecastAll.
+func ForecastBudget(details []billing.Detail, b config.Budget, now time.Time) (Forecast, error) {
+	if b.MonthlyLimit <= 0 {
+		return Forecast{}, &errors.ValidationError{Message: fmt.Sprintf("budget %q has no monthly limit", b.ID)}
+	}
+
+	now = now.UTC()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	daysInMonth := monthStart.AddDate(0, 1, -1).Day()
+	elapsed := now.Sub(monthStart).Hours() / 24
+	completeDays := int(elapsed)
+
+	// Daily charges (excluding credits) and credits for the month to date
+	daily := make([]float64, daysInMonth)
+	var charges, credits float64
+	for _, d := range details {
+		if !matches(d, b) {
+			continue
+		}
+		date := d.Date.UTC()
+		if date.Before(monthStart) || !date.Before(monthStart.AddDate(0, 1, 0)) || date.After(now) {
+			continue
+		}
+		if isCredit(d) {
+			credits += d.Cost
+			continue
+		}
+		charges += d.Cost
+		daily[date.Day()-1] += d.Cost
+	}
+
+	runRate := weightedRunRate(daily[:min(completeDays, daysInMonth)])
+	if completeDays == 0 {
+		// No completed days yet; extrapolate the partial first day
+		if elapsed > 0 {
+			runRate = charges / elapsed
+		}
+	}
+
+	remaining := float64(daysInMonth) - elapsed
+	spendToDate := charges + credits
+	projected := spendToDate + runRate*remaining
+
+	f := Forecast{
+		Budget:         budgetName(b),
+		Scope:          b.Scope,
+		ID:             b.ID,
+		Month:          monthStart.Format("2006-01"),
+		MonthlyLimit:   b.MonthlyLimit,
+		ChargesToDate:  charges,
+		CreditsToDate:  credits,
+		SpendToDate:    spendToDate,
+		DailyRunRate:   runRate,
+		ElapsedDays:    elapsed,
+		DaysInMonth:    daysInMonth,
+		ProjectedSpend: projected,
+		UtilisationPct: spendToDate / b.MonthlyLimit * 100,
+		ProjectedPct:   projected / b.MonthlyLimit * 100,
+	}
+	if projected > b.MonthlyLimit {
+		f.ProjectedOverrun = projected - b.MonthlyLimit
+	}
+	f.BudgetCrossingDate = crossingDate(daily, credits, runRate, b.MonthlyLimit, monthStart, elapsed)
+
+	warn := b.WarnPercent
+	if warn <= 0 {
+		warn = defaultWarnPercent
+	}
+	switch {
+	case spendToDate > b.MonthlyLimit:
+		f.Status = Exceeded
+	case projected > b.MonthlyLimit:
+		f.Status = Overrun
+	case f.ProjectedPct >= warn:
+		f.Status = Warning
+	default:
+		f.Status = OnTrack
+	}
+	return f, nil
+}
+
+// Summary returns a one-line, human-readable description of the forecast.
+func (f Forecast) Summary() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (%s): $%.2f of $%.2f spent (%.1f%%), projected $%.2f (%.1f%%)",
+		f.Budget, f.Month, f.SpendToDate, f.MonthlyLimit, f.UtilisationPct, f.ProjectedSpend, f.ProjectedPct)
+	switch f.Status {
+	case Exceeded:
+		fmt.Fprintf(&b, " - EXCEEDED on %s", crossingLabel(f.BudgetCrossingDate))
+	case Overrun:
+		fmt.Fprintf(&b, " - projected overrun $%.2f, limit crossed around %s",
+			f.ProjectedOverrun, crossingLabel(f.BudgetCrossingDate))
+	case Warning:
+		b.WriteString(" - WARNING: approaching limit")
+	default:
+		b.WriteString(" - on track")
+	}
+	return b.String()
+}
+
+// crossingLabel formats a budget crossing date, or "date unknown" when there is none.
+func crossingLabel(d *time.Time) string {
+	if d == nil {
+		return "date unknown"
+	}
+	return d.Format(time.DateOnly)
+}
+
+// weightedRunRate returns the average of daily values weighted by day number (1, 2, 3...).
+func weightedRunRate(daily []float64) float64 {
+	var sum, weights float64
+	for i, v := range daily {
+		w := float64(i + 1)
+		sum += v * w
+		weights += w
+	}
+	if weights == 0 {
+		return 0
+	}
+	return sum / weights
+}
+
+// crossingDate returns the day cumulative net spend first exceeds limit, using actual daily charges
+// for elapsed days and the run rate afterwards. Credits are applied from the start of the month.
+// Returns nil if the limit is not crossed within the month.
+func crossingDate(daily []float64, credits, runRate, limit float64, monthStart time.Time, elapsed float64) *time.Time {
+	cumulative := credits
+	for day := range daily {
+		dayStart := float64(day)
+		switch {
+		case dayStart+1 <= elapsed:
+			cumulative += daily[day]
+		case dayStart < elapsed:
+			// Partial current day: actual charges so far plus the projected remainder
+			cumulative += daily[day] + runRate*(dayStart+1-elapsed)
+		default:
+			cumulative += runRate
+		}
+		if cumulative > limit {
+			t := monthStart.AddDate(0, 0, day)
+			return &t
+		}
+	}
+	return nil
+}
+
+// matches reports whether a line item falls within the budget's scope.
+func matches(d billing.Detail, b config.Budget) bool {
+	switch b.Scope {
+	case config.BudgetScopeOrg:
+		return d.Org.ID == b.ID
+	case config.BudgetScopeProject:
+		return d.Project.ID == b.ID || d.Project.Name == b.ID
+	case config.BudgetScopeCategory:
+		return strings.EqualFold(d.Category, b.ID)
+	default:
+		return false
+	}
+}
+
+// isCredit reports whether a line item is a credit or adjustment (coupons, credits, and minimum
+// charges all classify as "Credits") rather than a usage charge.
+func isCredit(d billing.Detail) bool {
+	return d.Category == "Credits" || d.Cost < 0
+}
+
+// budgetName returns the budget's display name.
+func budgetName(b config.Budget) string {
+	if b.Name != "" {
+		return b.Name
+	}
+	return fmt.Sprintf("%s %s", b.Scope, b.ID)
+}
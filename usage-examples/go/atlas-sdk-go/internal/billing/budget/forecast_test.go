@@ -0,0 +1,123 @@
+package budget
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"atlas-sdk-go/internal/billing"
+	"atlas-sdk-go/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func charge(day int, project, category string, cost float64) billing.Detail {
+	return billing.Detail{
+		Org:      billing.OrgInfo{ID: "org1"},
+		Project:  billing.ProjectInfo{ID: project + "-id", Name: project},
+		Category: category,
+		Cost:     cost,
+		Date:     time.Date(2024, 6, day, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+// tenDaysAt returns $100/day of cluster charges for June 1-10
+func tenDaysAt(cost float64) []billing.Detail {
+	var details []billing.Detail
+	for d := 1; d <= 10; d++ {
+		details = append(details, charge(d, "prod", "Clusters", cost))
+	}
+	return details
+}
+
+func TestForecastBudget_ProjectsOverrunAndCrossingDate(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2024, 6, 11, 0, 0, 0, 0, time.UTC) // exactly 10 days elapsed in a 30-day month
+	b := config.Budget{Scope: config.BudgetScopeOrg, ID: "org1", MonthlyLimit: 2000}
+
+	f, err := ForecastBudget(tenDaysAt(100), b, now)
+
+	require.NoError(t, err)
+	assert.InDelta(t, 1000.0, f.SpendToDate, 0.001)
+	assert.InDelta(t, 100.0, f.DailyRunRate, 0.001)
+	assert.InDelta(t, 3000.0, f.ProjectedSpend, 0.001)
+	assert.InDelta(t, 1000.0, f.ProjectedOverrun, 0.001)
+	assert.InDelta(t, 50.0, f.UtilisationPct, 0.001)
+	assert.Equal(t, Overrun, f.Status)
+	require.NotNil(t, f.BudgetCrossingDate)
+	assert.Equal(t, time.Date(2024, 6, 21, 0, 0, 0, 0, time.UTC), *f.BudgetCrossingDate, "$2000 is crossed on day 21")
+	assert.Contains(t, f.Summary(), "projected overrun $1000.00")
+}
+
+func TestForecastBudget_PartialDayAndCredits(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2024, 6, 11, 12, 0, 0, 0, time.UTC) // 10.5 days elapsed
+	details := append(tenDaysAt(100),
+		charge(11, "prod", "Clusters", 50), // half of today so far
+		charge(5, "prod", "Credits", -300), // one-off credit
+	)
+	b := config.Budget{Scope: config.BudgetScopeProject, ID: "prod", MonthlyLimit: 5000}
+
+	f, err := ForecastBudget(details, b, now)
+
+	require.NoError(t, err)
+	assert.InDelta(t, 1050.0, f.ChargesToDate, 0.001)
+	assert.InDelta(t, -300.0, f.CreditsToDate, 0.001)
+	assert.InDelta(t, 750.0, f.SpendToDate, 0.001)
+	// 750 to date + 100/day for the remaining 19.5 days; the credit is not extrapolated
+	assert.InDelta(t, 2700.0, f.ProjectedSpend, 0.001)
+	assert.Equal(t, OnTrack, f.Status)
+	assert.Nil(t, f.BudgetCrossingDate)
+}
+
+func TestForecastBudget_RecentDaysWeighMore(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2024, 6, 5, 0, 0, 0, 0, time.UTC)
+	details := []billing.Detail{
+		charge(1, "prod", "Clusters", 0),
+		charge(2, "prod", "Clusters", 0),
+		charge(3, "prod", "Clusters", 0),
+		charge(4, "prod", "Clusters", 100),
+	}
+
+	f, err := ForecastBudget(details, config.Budget{Scope: config.BudgetScopeOrg, ID: "org1", MonthlyLimit: 10000}, now)
+
+	require.NoError(t, err)
+	assert.InDelta(t, 40.0, f.DailyRunRate, 0.001, "day 4 carries 4/10 of the weight")
+}
+
+func TestForecastBudget_ScopesAndStatus(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2024, 6, 11, 0, 0, 0, 0, time.UTC)
+	details := append(tenDaysAt(100), charge(3, "dev", "Backup", 50))
+
+	forecasts, err := ForecastAll(details, []config.Budget{
+		{Scope: config.BudgetScopeCategory, ID: "backup", MonthlyLimit: 60},
+		{Scope: config.BudgetScopeProject, ID: "dev-id", MonthlyLimit: 40, Name: "Dev"},
+		{Scope: config.BudgetScopeOrg, ID: "org1", MonthlyLimit: 3400, WarnPercent: 90},
+	}, now)
+
+	require.NoError(t, err)
+	require.Len(t, forecasts, 3)
+	assert.InDelta(t, 50.0, forecasts[0].SpendToDate, 0.001, "category match is case-insensitive")
+	assert.Equal(t, Exceeded, forecasts[1].Status)
+	assert.Equal(t, "Dev", forecasts[1].Budget)
+	assert.True(t, strings.Contains(forecasts[1].Summary(), "EXCEEDED on 2024-06-03"))
+	assert.Equal(t, Warning, forecasts[2].Status)
+}
+
+func TestForecastBudget_InvalidLimit(t *testing.T) {
+	t.Parallel()
+	_, err := ForecastBudget(nil, config.Budget{Scope: config.BudgetScopeOrg, ID: "org1"}, time.Now())
+	require.Error(t, err)
+}
+
+func TestForecast_SummaryWithoutCrossingDate(t *testing.T) {
+	t.Parallel()
+	exceeded := Forecast{Budget: "Org", Status: Exceeded}
+	overrun := Forecast{Budget: "Org", Status: Overrun, ProjectedOverrun: 10}
+
+	assert.Contains(t, exceeded.Summary(), "EXCEEDED on date unknown")
+	assert.Contains(t, overrun.Summary(), "limit crossed around date unknown")
+}
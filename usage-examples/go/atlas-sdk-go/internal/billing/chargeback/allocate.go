@@ -0,0 +1,271 @@
+package chargeback
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"atlas-sdk-go/internal/billing"
+)
+
+// computeCategory is the billing category used as the basis for proportional splits.
+const computeCategory = "Clusters"
+
+// Reasons recorded on unallocated items
+const (
+	ReasonNoTeam       = "no matching team"
+	ReasonNoBasis      = "no compute or direct spend to split by"
+	ReasonFixedPartial = "fixed percentages add up to less than 100"
+)
+
+// Statement is the chargeback for a single team.
+type Statement struct {
+	Team             string             `json:"team"`
+	CostCentre       string             `json:"costCentre,omitempty"`
+	Direct           float64            `json:"direct"`           // Line items matched to the team
+	Shared           float64            `json:"shared"`           // Team's portion of shared costs
+	Total            float64            `json:"total"`            // Direct + Shared
+	DirectByCategory map[string]float64 `json:"directByCategory"` // Direct cost per billing category
+	SharedByRule     map[string]float64 `json:"sharedByRule"`     // Shared cost per shared rule
+	Projects         []string           `json:"projects"`         // Projects with line items matched to the team
+	LineItems        int                `json:"lineItems"`        // Number of directly matched line items
+}
+
+// UnallocatedItem is cost that could not be charged to any team.
+type UnallocatedItem struct {
+	Project  string  `json:"project,omitempty"`
+	Cluster  string  `json:"cluster,omitempty"`
+	Category string  `json:"category"`
+	Cost     float64 `json:"cost"`
+	Reason   string  `json:"reason"`
+}
+
+// Unallocated summarizes cost that could not be charged to any team.
+type Unallocated struct {
+	Direct float64           `json:"direct"` // Line items that matched no team
+	Shared float64           `json:"shared"` // Shared cost that could not be split
+	Total  float64           `json:"total"`
+	Items  []UnallocatedItem `json:"items"` // Grouped by project, cluster, category, and reason
+}
+
+// Report is the result of allocating a set of line items to teams.
+// The sum of every statement's Total plus Unallocated.Total equals Total.
+type Report struct {
+	Statements  []Statement `json:"statements"`
+	Unallocated Unallocated `json:"unallocated"`
+	Total       float64     `json:"total"`
+}
+
+// Allocate charges each line item to a team and splits shared costs between teams according to m.
+//
+// A line item is shared if it matches a shared rule's categories or SKU patterns; the first
+// matching rule wins. Otherwise it is charged directly to the first team whose cluster patterns
+// match its cluster or, failing that, whose project patterns match its project name or ID.
+// Line items that match no team, and shared cost that cannot be split, are reported as
+// unallocated rather than spread across teams.
+//
+// Proportional splits use each team's direct compute (Clusters) spend, falling back to total
+// direct spend when no team has compute spend. Even splits divide cost equally across every
+// team in the mapping. Statements are sorted by total cost, highest first.
+func Allocate(details []billing.Detail, m Mapping) (*Report, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+
+	statements := make([]Statement, len(m.Teams))
+	teamIndex := make(map[string]int, len(m.Teams))
+	for i, t := range m.Teams {
+		statements[i] = Statement{
+			Team:             t.Team,
+			CostCentre:       t.CostCentre,
+			DirectByCategory: make(map[string]float64),
+			SharedByRule:     make(map[string]float64),
+		}
+		teamIndex[t.Team] = i
+	}
+
+	report := &Report{}
+	unallocated := make(map[UnallocatedItem]float64)
+	pools := make([]float64, len(m.Shared))
+	compute := make([]float64, len(m.Teams))
+
+	for _, d := range details {
+		report.Total += d.Cost
+
+		if ri := m.sharedRuleFor(d); ri >= 0 {
+			pools[ri] += d.Cost
+			continue
+		}
+
+		ti := m.teamFor(d)
+		if ti < 0 {
+			key := UnallocatedItem{
+				Project:  cmp.Or(d.Project.Name, d.Project.ID),
+				Cluster:  d.Cluster,
+				Category: d.Category,
+				Reason:   ReasonNoTeam,
+			}
+			unallocated[key] += d.Cost
+			report.Unallocated.Direct += d.Cost
+			continue
+		}
+
+		s := &statements[ti]
+		s.Direct += d.Cost
+		s.DirectByCategory[d.Category] += d.Cost
+		s.LineItems++
+		if p := cmp.Or(d.Project.Name, d.Project.ID); p != "" && !slices.Contains(s.Projects, p) {
+			s.Projects = append(s.Projects, p)
+		}
+		if d.Category == computeCategory {
+			compute[ti] += d.Cost
+		}
+	}
+
+	direct := make([]float64, len(statements))
+	for i, s := range statements {
+		direct[i] = s.Direct
+	}
+
+	for ri, rule := range m.Shared {
+		pool := pools[ri]
+		if pool == 0 {
+			continue
+		}
+		name := rule.displayName()
+
+		var weights []float64
+		switch rule.Method {
+		case Proportional:
+			weights = normalize(compute)
+			if weights == nil {
+				weights = normalize(direct)
+			}
+		case Even:
+			weights = make([]float64, len(statements))
+			for i := range weights {
+				weights[i] = 1 / float64(len(statements))
+			}
+		case Fixed:
+			weights = make([]float64, len(statements))
+			for team, pct := range rule.Percentages {
+				weights[teamIndex[team]] = pct / 100
+			}
+		}
+
+		allocated := 0.0
+		for i, w := range weights {
+			if w == 0 {
+				continue
+			}
+			share := pool * w
+			statements[i].Shared += share
+			statements[i].SharedByRule[name] += share
+			allocated += share
+		}
+
+		if rest := pool - allocated; !nearlyZero(rest) {
+			reason := ReasonFixedPartial
+			if weights == nil {
+				reason = ReasonNoBasis
+			}
+			unallocated[UnallocatedItem{Category: name, Reason: reason}] += rest
+			report.Unallocated.Shared += rest
+		}
+	}
+
+	for i := range statements {
+		s := &statements[i]
+		s.Total = s.Direct + s.Shared
+		slices.Sort(s.Projects)
+	}
+	slices.SortStableFunc(statements, func(a, b Statement) int {
+		if c := cmp.Compare(b.Total, a.Total); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Team, b.Team)
+	})
+	report.Statements = statements
+
+	report.Unallocated.Total = report.Unallocated.Direct + report.Unallocated.Shared
+	report.Unallocated.Items = make([]UnallocatedItem, 0, len(unallocated))
+	for item, cost := range unallocated {
+		item.Cost = cost
+		report.Unallocated.Items = append(report.Unallocated.Items, item)
+	}
+	slices.SortFunc(report.Unallocated.Items, func(a, b UnallocatedItem) int {
+		if c := cmp.Compare(b.Cost, a.Cost); c != 0 {
+			return c
+		}
+		return cmp.Or(
+			strings.Compare(a.Project, b.Project),
+			strings.Compare(a.Cluster, b.Cluster),
+			strings.Compare(a.Category, b.Category),
+		)
+	})
+
+	return report, nil
+}
+
+// sharedRuleFor returns the index of the first shared rule matching d, or -1.
+func (m Mapping) sharedRuleFor(d billing.Detail) int {
+	for i, r := range m.Shared {
+		for _, c := range r.Categories {
+			if strings.EqualFold(c, d.Category) {
+				return i
+			}
+		}
+		if matchAny(r.SKUs, d.SKU) {
+			return i
+		}
+	}
+	return -1
+}
+
+// teamFor returns the index of the team d is charged to, or -1.
+// Cluster patterns take precedence over project patterns.
+func (m Mapping) teamFor(d billing.Detail) int {
+	if d.HasCluster() {
+		for i, t := range m.Teams {
+			if matchAny(t.Clusters, d.Cluster) {
+				return i
+			}
+		}
+	}
+	for i, t := range m.Teams {
+		if matchAny(t.Projects, d.Project.Name) || matchAny(t.Projects, d.Project.ID) {
+			return i
+		}
+	}
+	return -1
+}
+
+// displayName returns the rule's name, defaulting to its categories and SKU patterns.
+func (r SharedRule) displayName() string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return strings.Join(append(slices.Clone(r.Categories), r.SKUs...), ", ")
+}
+
+// normalize scales positive values so they sum to 1. Negative values are treated as zero.
+// Returns nil if there is nothing to split by.
+func normalize(values []float64) []float64 {
+	total := 0.0
+	for _, v := range values {
+		total += max(v, 0)
+	}
+	if total <= 0 {
+		return nil
+	}
+	out := make([]float64, len(values))
+	for i, v := range values {
+		out[i] = max(v, 0) / total
+	}
+	return out
+}
+
+// nearlyZero reports whether v is zero, allowing for floating-point rounding.
+func nearlyZero(v float64) bool {
+	return v > -1e-9 && v < 1e-9
+}
@@ -0,0 +1,227 @@
+package chargeback
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"atlas-sdk-go/internal/billing"
+	internalerrors "atlas-sdk-go/internal/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func detail(project, cluster, category string, cost float64) billing.Detail {
+	return billing.Detail{
+		Org:      billing.OrgInfo{ID: "org1", Name: "Org One"},
+		Project:  billing.ProjectInfo{ID: project + "-id", Name: project},
+		Cluster:  cluster,
+		SKU:      "ATLAS_AWS_INSTANCE_M10",
+		Category: category,
+		Cost:     cost,
+		Date:     time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func testMapping() Mapping {
+	return Mapping{
+		Teams: []TeamRule{
+			{Team: "payments", CostCentre: "CC-100", Projects: []string{"payments-*"}},
+			{Team: "search", CostCentre: "CC-200", Projects: []string{"search"}, Clusters: []string{"search-*"}},
+		},
+		Shared: []SharedRule{
+			{Name: "Support", Categories: []string{"Support"}, Method: Proportional},
+			{Name: "Data Transfer", Categories: []string{"Data Transfer"}, Method: Even},
+		},
+	}
+}
+
+func statementFor(t *testing.T, r *Report, team string) Statement {
+	t.Helper()
+	for _, s := range r.Statements {
+		if s.Team == team {
+			return s
+		}
+	}
+	t.Fatalf("no statement for team %q", team)
+	return Statement{}
+}
+
+func TestAllocate_DirectAndSharedCosts(t *testing.T) {
+	t.Parallel()
+	details := []billing.Detail{
+		detail("payments-prod", "Cluster0", "Clusters", 300),
+		detail("payments-prod", "Cluster0", "Backup", 20),
+		// Cluster pattern takes precedence over the project pattern
+		detail("payments-prod", "search-index", "Clusters", 100),
+		detail("search", "Cluster1", "Storage", 30),
+		detail("", "", "Support", 40),
+		detail("", "", "Data Transfer", 10),
+	}
+
+	report, err := Allocate(details, testMapping())
+
+	require.NoError(t, err)
+	require.Len(t, report.Statements, 2)
+	assert.Equal(t, "payments", report.Statements[0].Team, "statements should be sorted by total descending")
+
+	payments := statementFor(t, report, "payments")
+	assert.Equal(t, "CC-100", payments.CostCentre)
+	assert.InDelta(t, 320.0, payments.Direct, 0.001)
+	assert.InDelta(t, 30.0+5.0, payments.Shared, 0.001, "support split 3:1 by compute, transfer split evenly")
+	assert.InDelta(t, 355.0, payments.Total, 0.001)
+	assert.InDelta(t, 30.0, payments.SharedByRule["Support"], 0.001)
+	assert.Equal(t, 2, payments.LineItems)
+
+	search := statementFor(t, report, "search")
+	assert.InDelta(t, 130.0, search.Direct, 0.001)
+	assert.InDelta(t, 15.0, search.Shared, 0.001)
+	assert.InDelta(t, 100.0, search.DirectByCategory["Clusters"], 0.001)
+	assert.Equal(t, []string{"payments-prod", "search"}, search.Projects)
+
+	assert.InDelta(t, 0.0, report.Unallocated.Total, 0.001)
+	assert.InDelta(t, 500.0, report.Total, 0.001)
+}
+
+func TestAllocate_ReportsUnallocatedCost(t *testing.T) {
+	t.Parallel()
+	m := testMapping()
+	m.Shared = append(m.Shared, SharedRule{
+		Name:        "Credits",
+		Categories:  []string{"Credits"},
+		Method:      Fixed,
+		Percentages: map[string]float64{"payments": 50, "search": 25},
+	})
+	details := []billing.Detail{
+		detail("payments-prod", "Cluster0", "Clusters", 100),
+		detail("marketing", "Cluster9", "Clusters", 60),
+		detail("marketing", "Cluster9", "Clusters", 40),
+		detail("", "", "Credits", -40),
+	}
+
+	report, err := Allocate(details, m)
+
+	require.NoError(t, err)
+	u := report.Unallocated
+	assert.InDelta(t, 100.0, u.Direct, 0.001)
+	assert.InDelta(t, -10.0, u.Shared, 0.001, "the 25% of credits without a team stays unallocated")
+	require.Len(t, u.Items, 2)
+	assert.Equal(t, UnallocatedItem{Project: "marketing", Cluster: "Cluster9", Category: "Clusters", Cost: 100, Reason: ReasonNoTeam}, u.Items[0])
+	assert.Equal(t, ReasonFixedPartial, u.Items[1].Reason)
+
+	assert.InDelta(t, -20.0, statementFor(t, report, "payments").Shared, 0.001)
+	assert.InDelta(t, -10.0, statementFor(t, report, "search").Shared, 0.001)
+
+	sum := u.Total
+	for _, s := range report.Statements {
+		sum += s.Total
+	}
+	assert.InDelta(t, report.Total, sum, 0.001, "statements and unallocated should reconcile to the total")
+}
+
+func TestAllocate_ProportionalFallsBackToDirectSpend(t *testing.T) {
+	t.Parallel()
+	details := []billing.Detail{
+		detail("payments-prod", "", "Backup", 30),
+		detail("search", "", "Storage", 10),
+		detail("", "", "Support", 20),
+	}
+
+	report, err := Allocate(details, testMapping())
+
+	require.NoError(t, err)
+	assert.InDelta(t, 15.0, statementFor(t, report, "payments").Shared, 0.001)
+	assert.InDelta(t, 5.0, statementFor(t, report, "search").Shared, 0.001)
+}
+
+func TestAllocate_LineItemsWithoutClusterMatchByProject(t *testing.T) {
+	t.Parallel()
+	m := testMapping()
+	m.Teams = append([]TeamRule{{Team: "platform", Clusters: []string{"*"}}}, m.Teams...)
+	details := []billing.Detail{
+		detail("payments-prod", billing.NoCluster, "Backup", 30),
+		detail("search", "", "Storage", 10),
+		detail("search", "Cluster1", "Clusters", 50),
+	}
+
+	report, err := Allocate(details, m)
+
+	require.NoError(t, err)
+	assert.InDelta(t, 30.0, statementFor(t, report, "payments").Direct, 0.001, "the N/A placeholder is not a cluster name")
+	assert.InDelta(t, 10.0, statementFor(t, report, "search").Direct, 0.001)
+	assert.InDelta(t, 50.0, statementFor(t, report, "platform").Direct, 0.001)
+}
+
+func TestAllocate_NoBasisIsUnallocated(t *testing.T) {
+	t.Parallel()
+
+	report, err := Allocate([]billing.Detail{detail("", "", "Support", 20)}, testMapping())
+
+	require.NoError(t, err)
+	require.Len(t, report.Unallocated.Items, 1)
+	assert.Equal(t, ReasonNoBasis, report.Unallocated.Items[0].Reason)
+	assert.InDelta(t, 20.0, report.Unallocated.Shared, 0.001)
+}
+
+func TestMapping_Validate(t *testing.T) {
+	t.Parallel()
+	tests := map[string]Mapping{
+		"no teams":       {},
+		"duplicate team": {Teams: []TeamRule{{Team: "a"}, {Team: "a"}}},
+		"bad pattern":    {Teams: []TeamRule{{Team: "a", Projects: []string{"["}}}},
+		"bad method":     {Teams: []TeamRule{{Team: "a"}}, Shared: []SharedRule{{Categories: []string{"Support"}, Method: "random"}}},
+		"unknown team": {Teams: []TeamRule{{Team: "a"}}, Shared: []SharedRule{
+			{Categories: []string{"Support"}, Method: Fixed, Percentages: map[string]float64{"b": 10}},
+		}},
+		"over 100": {Teams: []TeamRule{{Team: "a"}, {Team: "b"}}, Shared: []SharedRule{
+			{Categories: []string{"Support"}, Method: Fixed, Percentages: map[string]float64{"a": 60, "b": 60}},
+		}},
+	}
+	for name, m := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			err := m.Validate()
+			var vErr *internalerrors.ValidationError
+			assert.ErrorAs(t, err, &vErr)
+		})
+	}
+}
+
+func TestLoadMapping(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "chargeback.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"teams": [{"team": "payments", "cost_centre": "CC-100", "projects": ["payments-*"]}],
+		"shared": [{"categories": ["Support"], "method": "even"}]
+	}`), 0o600))
+
+	m, err := LoadMapping(path)
+	require.NoError(t, err)
+	assert.Equal(t, "CC-100", m.Teams[0].CostCentre)
+	assert.Equal(t, Even, m.Shared[0].Method)
+
+	_, err = LoadMapping(filepath.Join(dir, "missing.json"))
+	var nfErr *internalerrors.NotFoundError
+	assert.ErrorAs(t, err, &nfErr)
+}
+
+func TestReport_Table(t *testing.T) {
+	t.Parallel()
+	report, err := Allocate([]billing.Detail{
+		detail("payments-prod", "Cluster0", "Clusters", 75),
+		detail("other", "", "Clusters", 25),
+	}, testMapping())
+	require.NoError(t, err)
+
+	table := report.Table()
+	require.Len(t, table, 5)
+	assert.Equal(t, []string{"payments", "CC-100", "75.00", "0.00", "75.00", "75.0%"}, table[1])
+	assert.Equal(t, []string{"UNALLOCATED", "", "25.00", "0.00", "25.00", "25.0%"}, table[3])
+	assert.Equal(t, []string{"TOTAL", "", "", "", "100.00", "100.0%"}, table[4])
+
+	assert.Equal(t, [][]string{{"Item", "Amount"}, {"Direct: Clusters", "75.00"}, {"Total", "75.00"}},
+		report.Statements[0].Table())
+}
@@ -0,0 +1,138 @@
+package chargeback
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+
+	"atlas-sdk-go/internal/errors"
+)
+
+// Split methods supported by SharedRule.Method
+const (
+	Proportional = "proportional" // Split by each team's compute (Clusters) spend
+	Even         = "even"         // Split evenly across every team in the mapping
+	Fixed        = "fixed"        // Split by the percentages in SharedRule.Percentages
+)
+
+// Mapping assigns line items to teams and defines how shared costs are split.
+type Mapping struct {
+	Teams  []TeamRule   `json:"teams"`
+	Shared []SharedRule `json:"shared,omitempty"`
+}
+
+// TeamRule maps project and cluster name patterns to a team.
+// Patterns use shell glob syntax (e.g. "payments-*"). Cluster patterns are checked
+// before project patterns, so a cluster can be assigned to a different team than its project.
+type TeamRule struct {
+	Team       string   `json:"team"`
+	CostCentre string   `json:"cost_centre,omitempty"`
+	Projects   []string `json:"projects,omitempty"`
+	Clusters   []string `json:"clusters,omitempty"`
+}
+
+// SharedRule identifies line items that are shared across teams, by category or SKU pattern,
+// and how to split them.
+type SharedRule struct {
+	Name        string             `json:"name,omitempty"`
+	Categories  []string           `json:"categories,omitempty"`
+	SKUs        []string           `json:"skus,omitempty"`
+	Method      string             `json:"method"`
+	Percentages map[string]float64 `json:"percentages,omitempty"` // Team -> percent, for the "fixed" method
+}
+
+// LoadMapping reads and validates a chargeback mapping from a JSON file.
+func LoadMapping(filePath string) (Mapping, error) {
+	var m Mapping
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return m, &errors.NotFoundError{Resource: "chargeback mapping file", ID: filePath}
+		}
+		return m, errors.WithContext(err, "reading chargeback mapping file")
+	}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return m, errors.WithContext(err, "parsing chargeback mapping file")
+	}
+	if err := m.Validate(); err != nil {
+		return m, err
+	}
+	return m, nil
+}
+
+// Validate checks that team names are unique, patterns are well-formed, and shared rules are complete.
+func (m Mapping) Validate() error {
+	if len(m.Teams) == 0 {
+		return &errors.ValidationError{Message: "chargeback mapping must define at least one team"}
+	}
+
+	teams := make(map[string]bool, len(m.Teams))
+	for i, t := range m.Teams {
+		if t.Team == "" {
+			return &errors.ValidationError{Message: fmt.Sprintf("team rule %d: team is required", i)}
+		}
+		if teams[t.Team] {
+			return &errors.ValidationError{Message: fmt.Sprintf("team %q is defined more than once", t.Team)}
+		}
+		teams[t.Team] = true
+		if err := validatePatterns(t.Projects, t.Clusters); err != nil {
+			return &errors.ValidationError{Message: fmt.Sprintf("team %q: %v", t.Team, err)}
+		}
+	}
+
+	for i, r := range m.Shared {
+		name := r.Name
+		if name == "" {
+			name = fmt.Sprintf("%d", i)
+		}
+		if len(r.Categories) == 0 && len(r.SKUs) == 0 {
+			return &errors.ValidationError{Message: fmt.Sprintf("shared rule %s: categories or skus are required", name)}
+		}
+		if err := validatePatterns(r.SKUs); err != nil {
+			return &errors.ValidationError{Message: fmt.Sprintf("shared rule %s: %v", name, err)}
+		}
+		switch r.Method {
+		case Proportional, Even:
+		case Fixed:
+			total := 0.0
+			for team, pct := range r.Percentages {
+				if !teams[team] {
+					return &errors.ValidationError{Message: fmt.Sprintf("shared rule %s: unknown team %q", name, team)}
+				}
+				if pct < 0 {
+					return &errors.ValidationError{Message: fmt.Sprintf("shared rule %s: percentage for %q is negative", name, team)}
+				}
+				total += pct
+			}
+			if total > 100.0001 {
+				return &errors.ValidationError{Message: fmt.Sprintf("shared rule %s: percentages add up to %.2f, more than 100", name, total)}
+			}
+		default:
+			return &errors.ValidationError{Message: fmt.Sprintf("shared rule %s: method must be proportional, even, or fixed", name)}
+		}
+	}
+	return nil
+}
+
+// validatePatterns reports the first malformed glob pattern.
+func validatePatterns(lists ...[]string) error {
+	for _, patterns := range lists {
+		for _, p := range patterns {
+			if _, err := path.Match(p, ""); err != nil {
+				return fmt.Errorf("invalid pattern %q: %w", p, err)
+			}
+		}
+	}
+	return nil
+}
+
+// matchAny reports whether value matches any of the glob patterns.
+func matchAny(patterns []string, value string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, value); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package chargeback
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"atlas-sdk-go/internal/billing"
+	"atlas-sdk-go/internal/data/export"
+)
+
+// Table returns a summary of the report: a header row, one row per team, an UNALLOCATED row,
+// and a closing TOTAL row.
+func (r *Report) Table() [][]string {
+	rows := [][]string{{"Team", "Cost Centre", "Direct", "Shared", "Total", "Share"}}
+	for _, s := range r.Statements {
+		rows = append(rows, []string{
+			s.Team, s.CostCentre, billing.FormatCost(s.Direct), billing.FormatCost(s.Shared), billing.FormatCost(s.Total), r.share(s.Total),
+		})
+	}
+	u := r.Unallocated
+	rows = append(rows,
+		[]string{"UNALLOCATED", "", billing.FormatCost(u.Direct), billing.FormatCost(u.Shared), billing.FormatCost(u.Total), r.share(u.Total)},
+		[]string{"TOTAL", "", "", "", billing.FormatCost(r.Total), r.share(r.Total)},
+	)
+	return rows
+}
+
+// Table returns the team's statement as rows of item and amount: direct cost by category,
+// then shared cost by rule, then the total.
+func (s Statement) Table() [][]string {
+	rows := [][]string{{"Item", "Amount"}}
+	for _, c := range slices.Sorted(maps.Keys(s.DirectByCategory)) {
+		rows = append(rows, []string{"Direct: " + c, billing.FormatCost(s.DirectByCategory[c])})
+	}
+	for _, name := range slices.Sorted(maps.Keys(s.SharedByRule)) {
+		rows = append(rows, []string{"Shared: " + name, billing.FormatCost(s.SharedByRule[name])})
+	}
+	return append(rows, []string{"Total", billing.FormatCost(s.Total)})
+}
+
+// WriteCSV writes the summary table to a CSV file at filePath.
+func (r *Report) WriteCSV(filePath string) error {
+	return export.ToCSV(r.Table(), filePath)
+}
+
+// WriteJSON writes the full report, including per-team breakdowns and unallocated items,
+// to a JSON file at filePath.
+func (r *Report) WriteJSON(filePath string) error {
+	return export.ToJSON(r, filePath)
+}
+
+// share formats v as a percentage of the report total.
+func (r *Report) share(v float64) string {
+	if r.Total == 0 {
+		return "0.0%"
+	}
+	return fmt.Sprintf("%.1f%%", v/r.Total*100)
+}
@@ -0,0 +1,111 @@
+package billing
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"atlas-sdk-go/internal/errors"
+)
+
+// Checkpoint records how far billing data has been synced for an organization.
+// Seen holds the cost, in cents, of each line item inside the revision window so that
+// items Atlas revises after the fact can be detected on the next sync.
+type Checkpoint struct {
+	OrgID             string           `json:"orgId"`
+	LastProcessedDate time.Time        `json:"lastProcessedDate"`
+	UpdatedAt         time.Time        `json:"updatedAt"`
+	Seen              map[string]int64 `json:"seen,omitempty"`
+}
+
+// CheckpointStore loads and saves sync checkpoints keyed by organization ID.
+// Load returns nil and no error if no checkpoint exists for the organization.
+type CheckpointStore interface {
+	Load(orgID string) (*Checkpoint, error)
+	Save(cp Checkpoint) error
+}
+
+// FileCheckpointStore keeps checkpoints for all organizations in a single JSON file.
+// Writes go to a temporary file that is renamed into place, so a crash mid-write
+// leaves the previous checkpoint intact.
+type FileCheckpointStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileCheckpointStore returns a store backed by the JSON file at path.
+// The file and its parent directory are created on the first Save.
+func NewFileCheckpointStore(path string) *FileCheckpointStore {
+	return &FileCheckpointStore{path: path}
+}
+
+// Load returns the checkpoint for orgID, or nil if none has been saved.
+func (s *FileCheckpointStore) Load(orgID string) (*Checkpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all, err := s.readAll()
+	if err != nil {
+		return nil, err
+	}
+	cp, ok := all[orgID]
+	if !ok {
+		return nil, nil
+	}
+	return &cp, nil
+}
+
+// Save stores cp, replacing any existing checkpoint for the same organization.
+func (s *FileCheckpointStore) Save(cp Checkpoint) error {
+	if cp.OrgID == "" {
+		return &errors.ValidationError{Message: "checkpoint organization ID cannot be empty"}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all, err := s.readAll()
+	if err != nil {
+		return err
+	}
+	all[cp.OrgID] = cp
+
+	data, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return errors.WithContext(err, "encoding checkpoints")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return errors.WithContext(err, "creating checkpoint directory")
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return errors.WithContext(err, "writing checkpoint file")
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return errors.WithContext(err, "replacing checkpoint file")
+	}
+	return nil
+}
+
+// readAll loads every checkpoint in the file. A missing file is treated as empty.
+func (s *FileCheckpointStore) readAll() (map[string]Checkpoint, error) {
+	all := make(map[string]Checkpoint)
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return all, nil
+		}
+		return nil, errors.WithContext(err, "reading checkpoint file")
+	}
+	if len(data) == 0 {
+		return all, nil
+	}
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, errors.WithContext(err, "parsing checkpoint file")
+	}
+	return all, nil
+}
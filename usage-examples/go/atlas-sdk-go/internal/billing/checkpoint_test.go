@@ -0,0 +1,54 @@
+package billing
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileCheckpointStore_RoundTrip(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "nested", "checkpoints.json")
+	store := NewFileCheckpointStore(path)
+	date := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+
+	cp, err := store.Load("org1")
+	require.NoError(t, err)
+	assert.Nil(t, cp, "missing file should load as no checkpoint")
+
+	require.NoError(t, store.Save(Checkpoint{OrgID: "org1", LastProcessedDate: date, Seen: map[string]int64{"k": 100}}))
+	require.NoError(t, store.Save(Checkpoint{OrgID: "org2", LastProcessedDate: date.AddDate(0, 0, 1)}))
+
+	cp, err = store.Load("org1")
+	require.NoError(t, err)
+	require.NotNil(t, cp)
+	assert.True(t, date.Equal(cp.LastProcessedDate))
+	assert.Equal(t, int64(100), cp.Seen["k"])
+
+	cp, err = store.Load("org2")
+	require.NoError(t, err)
+	require.NotNil(t, cp, "saving one org should not overwrite another")
+
+	_, statErr := os.Stat(path + ".tmp")
+	assert.True(t, os.IsNotExist(statErr), "temporary file should be renamed into place")
+}
+
+func TestFileCheckpointStore_InvalidFile(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "checkpoints.json")
+	require.NoError(t, os.WriteFile(path, []byte("{not json"), 0644))
+
+	_, err := NewFileCheckpointStore(path).Load("org1")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "parsing checkpoint file")
+}
+
+func TestFileCheckpointStore_EmptyOrgID(t *testing.T) {
+	t.Parallel()
+	err := NewFileCheckpointStore(filepath.Join(t.TempDir(), "c.json")).Save(Checkpoint{})
+	require.Error(t, err)
+}
@@ -1,8 +1,11 @@
 package billing
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"atlas-sdk-go/internal/errors"
@@ -10,6 +13,9 @@ import (
 	"go.mongodb.org/atlas-sdk/v20250219001/admin"
 )
 
+// NoCluster is the Detail.Cluster value of line items that aren't billed to a cluster.
+const NoCluster = "N/A"
+
 // Detail represents the transformed billing line item
 type Detail struct {
 	Org      OrgInfo     `json:"org"`
@@ -21,6 +27,24 @@ type Detail struct {
 	Provider string      `json:"provider"`
 	Instance string      `json:"instance"`
 	Category string      `json:"category"`
+
+	Region        string `json:"region,omitempty"` // From the SKU or the line item's "region" tag
+	StorageType   string `json:"storageType,omitempty"`
+	InstanceClass string `json:"instanceClass,omitempty"` // e.g. "nvme", "low-cpu"
+
+	Quantity        float64   `json:"quantity"`                  // Units consumed, e.g. hours or GB days
+	Unit            string    `json:"unit,omitempty"`            // What Quantity measures
+	UnitPrice       float64   `json:"unitPrice"`                 // US dollars per Unit
+	Discount        float64   `json:"discount,omitempty"`        // US dollars discounted from Cost
+	DiscountPercent float64   `json:"discountPercent,omitempty"` // Percentage discounted from Cost
+	EndDate         time.Time `json:"endDate,omitzero"`
+	Tier            string    `json:"tier,omitempty"` // Usage range of a tiered SKU, e.g. "0-100" or "100+"
+	Note            string    `json:"note,omitempty"`
+}
+
+// HasCluster reports whether the line item is billed to a cluster.
+func (d Detail) HasCluster() bool {
+	return d.Cluster != "" && d.Cluster != NoCluster
 }
 
 // OrgInfo contains organization identifier information
@@ -37,8 +61,9 @@ type ProjectInfo struct {
 
 // CollectLineItemBillingData retrieves all pending invoices for the specified organization,
 // transforms them into detailed billing records, and filters out items processed before lastProcessedDate.
+// SKUs are classified with classifier, or the embedded rules if classifier is nil.
 // Returns a slice of billing Details if pending invoices exists or an error if the operation fails.
-func CollectLineItemBillingData(ctx context.Context, sdk admin.InvoicesApi, orgSdk admin.OrganizationsApi, orgID string, lastProcessedDate *time.Time) ([]Detail, error) {
+func CollectLineItemBillingData(ctx context.Context, sdk admin.InvoicesApi, orgSdk admin.OrganizationsApi, orgID string, lastProcessedDate *time.Time, classifier *SKUClassifier) ([]Detail, error) {
 	req := sdk.ListPendingInvoices(ctx, orgID)
 	r, _, err := req.Execute()
 
@@ -58,7 +83,7 @@ func CollectLineItemBillingData(ctx context.Context, sdk admin.InvoicesApi, orgS
 	}
 
 	// Process invoices and collect line items
-	billingDetails, err := processInvoices(r.GetResults(), orgID, orgName, lastProcessedDate)
+	billingDetails, err := processInvoices(r.GetResults(), orgID, orgName, lastProcessedDate, classifier)
 	if err != nil {
 		return nil, errors.WithContext(err, "processing invoices")
 	}
@@ -73,8 +98,11 @@ func CollectLineItemBillingData(ctx context.Context, sdk admin.InvoicesApi, orgS
 // processInvoices extracts and transforms billing line items from invoices into Detail structs.
 // The function iterates through all invoices and their line items, filters out items processed before
 // lastProcessedDate (if provided), then determines line item details, such as organization and project,
-// pricing, and SKU-based information.
-func processInvoices(invoices []admin.BillingInvoice, orgID, orgName string, lastProcessedDate *time.Time) ([]Detail, error) {
+// pricing, and SKU-based information. A nil classifier uses the embedded SKU rules.
+func processInvoices(invoices []admin.BillingInvoice, orgID, orgName string, lastProcessedDate *time.Time, classifier *SKUClassifier) ([]Detail, error) {
+	if classifier == nil {
+		classifier = defaultClassifier
+	}
 	var billingDetails []Detail
 
 	for _, invoice := range invoices {
@@ -86,6 +114,7 @@ func processInvoices(invoices []admin.BillingInvoice, orgID, orgName string, las
 				continue
 			}
 
+			sku := classifier.Classify(lineItem.GetSku())
 			detail := Detail{
 				Org: OrgInfo{
 					ID:   orgID,
@@ -95,13 +124,26 @@ func processInvoices(invoices []admin.BillingInvoice, orgID, orgName string, las
 					ID:   lineItem.GetGroupId(),
 					Name: lineItem.GetGroupName(),
 				},
-				Cluster:  getValueOrDefault(lineItem.GetClusterName(), "N/A"),
+				Cluster:  getValueOrDefault(lineItem.GetClusterName(), NoCluster),
 				SKU:      lineItem.GetSku(),
 				Cost:     float64(lineItem.GetTotalPriceCents()) / 100.0,
 				Date:     startDate,
-				Provider: determineProvider(lineItem.GetSku()),
-				Instance: determineInstance(lineItem.GetSku()),
-				Category: determineCategory(lineItem.GetSku()),
+				Provider: sku.Provider,
+				Instance: sku.Instance,
+				Category: sku.Category,
+
+				Region:        cmp.Or(sku.Region, tagValue(lineItem.GetTags(), "region")),
+				StorageType:   sku.StorageType,
+				InstanceClass: sku.InstanceClass,
+
+				Quantity:        lineItem.GetQuantity(),
+				Unit:            lineItem.GetUnit(),
+				UnitPrice:       lineItem.GetUnitPriceDollars(),
+				Discount:        float64(lineItem.GetDiscountCents()) / 100.0,
+				DiscountPercent: float64(lineItem.GetPercentDiscount()),
+				EndDate:         lineItem.GetEndDate(),
+				Tier:            formatTier(lineItem),
+				Note:            lineItem.GetNote(),
 			}
 			billingDetails = append(billingDetails, detail)
 		}
@@ -123,6 +165,28 @@ func getOrganizationName(ctx context.Context, sdk admin.OrganizationsApi, orgID
 	return org.GetName(), nil
 }
 
+// tagValue returns the first value of the tag named key, ignoring case.
+func tagValue(tags map[string][]string, key string) string {
+	for k, values := range tags {
+		if strings.EqualFold(k, key) && len(values) > 0 {
+			return values[0]
+		}
+	}
+	return ""
+}
+
+// formatTier returns the usage range of a tiered line item, or "" if the SKU is not tiered.
+func formatTier(lineItem admin.InvoiceLineItem) string {
+	if !lineItem.HasTierLowerBound() && !lineItem.HasTierUpperBound() {
+		return ""
+	}
+	lower := strconv.FormatFloat(lineItem.GetTierLowerBound(), 'f', -1, 64)
+	if !lineItem.HasTierUpperBound() {
+		return lower + "+"
+	}
+	return lower + "-" + strconv.FormatFloat(lineItem.GetTierUpperBound(), 'f', -1, 64)
+}
+
 // getValueOrDefault returns the value or a default if empty
 func getValueOrDefault(value string, defaultValue string) string {
 	if value == "" {
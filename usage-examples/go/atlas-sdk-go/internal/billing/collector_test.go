@@ -2,6 +2,7 @@ package billing
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -69,7 +70,7 @@ func TestCollectLineItemBillingData_Success(t *testing.T) {
 
 	// Test execution
 	lastProcessedDate := time.Now().Add(-48 * time.Hour) // Older than the invoice
-	result, err := CollectLineItemBillingData(ctx, mockInvoiceSvc, mockOrgSvc, orgID, &lastProcessedDate)
+	result, err := CollectLineItemBillingData(ctx, mockInvoiceSvc, mockOrgSvc, orgID, &lastProcessedDate, nil)
 
 	// Assertions
 	require.NoError(t, err)
@@ -102,7 +103,7 @@ func TestCollectLineItemBillingData_ApiError(t *testing.T) {
 	mockOrgSvc := mockadmin.NewOrganizationsApi(t)
 
 	// Test execution
-	result, err := CollectLineItemBillingData(ctx, mockInvoiceSvc, mockOrgSvc, orgID, nil)
+	result, err := CollectLineItemBillingData(ctx, mockInvoiceSvc, mockOrgSvc, orgID, nil, nil)
 
 	// Assertions
 	require.Error(t, err)
@@ -132,7 +133,7 @@ func TestCollectLineItemBillingData_NoInvoices(t *testing.T) {
 	mockOrgSvc := mockadmin.NewOrganizationsApi(t)
 
 	// Test execution
-	result, err := CollectLineItemBillingData(ctx, mockInvoiceSvc, mockOrgSvc, orgID, nil)
+	result, err := CollectLineItemBillingData(ctx, mockInvoiceSvc, mockOrgSvc, orgID, nil, nil)
 
 	// Assertions
 	require.NoError(t, err) // Invoice lookup returning no results is not an error
@@ -189,7 +190,7 @@ func TestCollectLineItemBillingData_OrgLookupFails(t *testing.T) {
 		Return(nil, nil, assert.AnError).Once()
 
 	// Test execution
-	result, err := CollectLineItemBillingData(ctx, mockInvoiceSvc, mockOrgSvc, orgID, nil)
+	result, err := CollectLineItemBillingData(ctx, mockInvoiceSvc, mockOrgSvc, orgID, nil, nil)
 
 	// Assertions
 	require.NoError(t, err) // Org lookup failure is non-fatal
@@ -259,7 +260,7 @@ func TestCollectLineItemBillingData_FiltersByDate(t *testing.T) {
 
 	// Test with cutoff between the two dates
 	cutoffDate := startDateOld.Add(12 * time.Hour)
-	result, err := CollectLineItemBillingData(ctx, mockInvoiceSvc, mockOrgSvc, orgID, &cutoffDate)
+	result, err := CollectLineItemBillingData(ctx, mockInvoiceSvc, mockOrgSvc, orgID, &cutoffDate, nil)
 
 	// Assertions
 	require.NoError(t, err)
@@ -267,3 +268,90 @@ func TestCollectLineItemBillingData_FiltersByDate(t *testing.T) {
 	assert.Equal(t, startDateNew, result[0].Date)
 	assert.Equal(t, 30.0, result[0].Cost) // 3000 cents = $30.00
 }
+
+func TestProcessInvoices_CarriesUsageAndPricing(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+	invoices := []admin.BillingInvoice{{
+		Id: admin.PtrString("inv1"),
+		LineItems: &[]admin.InvoiceLineItem{
+			{
+				StartDate:        admin.PtrTime(start),
+				EndDate:          admin.PtrTime(end),
+				Sku:              admin.PtrString("NDS_AWS_INSTANCE_M30"),
+				Quantity:         admin.PtrFloat64(24),
+				Unit:             admin.PtrString("server hours"),
+				UnitPriceDollars: admin.PtrFloat64(0.54),
+				TotalPriceCents:  admin.PtrInt64(1166),
+				DiscountCents:    admin.PtrInt64(130),
+				PercentDiscount:  admin.PtrFloat32(10),
+				Note:             admin.PtrString("committed use"),
+				Tags:             &map[string][]string{"Region": {"us-east-1"}},
+			},
+			{
+				StartDate:      admin.PtrTime(start),
+				Sku:            admin.PtrString("NDS_AWS_DATA_TRANSFER_INTERNET"),
+				TierLowerBound: admin.PtrFloat64(0),
+				TierUpperBound: admin.PtrFloat64(1024),
+			},
+			{
+				StartDate:      admin.PtrTime(start),
+				Sku:            admin.PtrString("NDS_AWS_DATA_TRANSFER_INTERNET"),
+				TierLowerBound: admin.PtrFloat64(1024.5),
+			},
+		},
+	}}
+
+	details, err := processInvoices(invoices, "org1", "Org One", nil, nil)
+
+	require.NoError(t, err)
+	require.Len(t, details, 3)
+	d := details[0]
+	assert.Equal(t, 24.0, d.Quantity)
+	assert.Equal(t, "server hours", d.Unit)
+	assert.Equal(t, 0.54, d.UnitPrice)
+	assert.InDelta(t, 1.30, d.Discount, 0.0001)
+	assert.InDelta(t, 10.0, d.DiscountPercent, 0.0001)
+	assert.Equal(t, end, d.EndDate)
+	assert.Equal(t, "committed use", d.Note)
+	assert.Equal(t, "us-east-1", d.Region, "region falls back to the line item's tags")
+	assert.Empty(t, d.Tier)
+	assert.Equal(t, "0-1024", details[1].Tier)
+	assert.Equal(t, "1024.5+", details[2].Tier)
+}
+
+func TestProcessInvoices_UsesGivenClassifier(t *testing.T) {
+	t.Parallel()
+	rs, err := DefaultSKURules()
+	require.NoError(t, err)
+	classifier, err := NewSKUClassifier(rs.Merge(SKURuleSet{
+		Category: []SKURule{{Name: "vector-search", Match: `VECTOR_SEARCH`, Value: "Vector Search", Priority: 1000}},
+	}))
+	require.NoError(t, err)
+	invoices := []admin.BillingInvoice{{
+		Id: admin.PtrString("inv1"),
+		LineItems: &[]admin.InvoiceLineItem{
+			{StartDate: admin.PtrTime(time.Now()), Sku: admin.PtrString("NDS_AWS_VECTOR_SEARCH_INSTANCE_S30")},
+		},
+	}}
+
+	custom, err := processInvoices(invoices, "org1", "Org One", nil, classifier)
+	require.NoError(t, err)
+	defaults, err := processInvoices(invoices, "org1", "Org One", nil, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Vector Search", custom[0].Category)
+	assert.Equal(t, "Clusters", defaults[0].Category, "nil uses the embedded rules")
+}
+
+func TestDetail_OmitsZeroEndDate(t *testing.T) {
+	t.Parallel()
+	data, err := json.Marshal(Detail{})
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "endDate")
+
+	data, err = json.Marshal(Detail{EndDate: time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)})
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"endDate":"2024-05-02T00:00:00Z"`)
+}
@@ -0,0 +1,176 @@
+package compare
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+
+	"atlas-sdk-go/internal/billing"
+
+	"go.mongodb.org/atlas-sdk/v20250219001/admin"
+)
+
+// Status describes how a line item changed between the baseline and current invoices.
+type Status string
+
+const (
+	Changed   Status = "changed"
+	Unchanged Status = "unchanged"
+	New       Status = "new"     // Only in the current invoice
+	Removed   Status = "removed" // Only in the baseline invoice
+)
+
+// Item compares the cost of one org, project, cluster, and SKU combination across two invoices.
+type Item struct {
+	OrgID            string   `json:"orgId"`
+	Project          string   `json:"project"`
+	ProjectID        string   `json:"projectId"`
+	Cluster          string   `json:"cluster"`
+	SKU              string   `json:"sku"`
+	Category         string   `json:"category"`
+	Status           Status   `json:"status"`
+	BaselineCost     float64  `json:"baselineCost"`
+	CurrentCost      float64  `json:"currentCost"`
+	Change           float64  `json:"change"`              // CurrentCost - BaselineCost
+	ChangePct        *float64 `json:"changePct,omitempty"` // Change as a percentage of BaselineCost, if non-zero
+	Unit             string   `json:"unit,omitempty"`
+	BaselineQuantity float64  `json:"baselineQuantity"`
+	CurrentQuantity  float64  `json:"currentQuantity"`
+	VolumeEffect     *float64 `json:"volumeEffect,omitempty"` // Portion of Change due to quantity
+	PriceEffect      *float64 `json:"priceEffect,omitempty"`  // Portion of Change due to unit price
+}
+
+// Report is the comparison of two invoices.
+type Report struct {
+	BaselineLabel string   `json:"baselineLabel"`
+	CurrentLabel  string   `json:"currentLabel"`
+	Items         []Item   `json:"items"` // Sorted by absolute change, largest first
+	BaselineTotal float64  `json:"baselineTotal"`
+	CurrentTotal  float64  `json:"currentTotal"`
+	Change        float64  `json:"change"`
+	ChangePct     *float64 `json:"changePct,omitempty"`
+	VolumeEffect  float64  `json:"volumeEffect"` // Sum of item volume effects, including new and removed items
+	PriceEffect   float64  `json:"priceEffect"`  // Sum of item price effects
+	Unattributed  float64  `json:"unattributed"` // Change on items without quantities
+	NewItems      int      `json:"newItems"`
+	RemovedItems  int      `json:"removedItems"`
+}
+
+// Compare aligns the line items of two invoices by org, project, cluster, and SKU, and reports
+// the change in cost for each, sorted by absolute change (largest first).
+//
+// When both invoices have a quantity for an item, the change is split into a volume effect,
+// (current quantity - baseline quantity) x baseline unit price, and a price effect,
+// (current unit price - baseline unit price) x current quantity; the two add up to the change.
+// New and removed items are attributed entirely to volume. Items without quantities are left
+// unattributed.
+func Compare(baseline, current []billing.Detail, baselineLabel, currentLabel string) *Report {
+	type agg struct {
+		item          Item
+		inBase, inCur bool // Seen in each invoice
+	}
+	index := make(map[string]int)
+	var aggs []agg
+	add := func(d billing.Detail, isCurrent bool) {
+		key := strings.Join([]string{d.Org.ID, d.Project.ID, d.Cluster, d.SKU}, "\x00")
+		i, ok := index[key]
+		if !ok {
+			i = len(aggs)
+			index[key] = i
+			aggs = append(aggs, agg{item: Item{
+				OrgID:     d.Org.ID,
+				Project:   cmp.Or(d.Project.Name, d.Project.ID),
+				ProjectID: d.Project.ID,
+				Cluster:   d.Cluster,
+				SKU:       d.SKU,
+				Category:  d.Category,
+			}})
+		}
+		a := &aggs[i]
+		a.item.Unit = cmp.Or(a.item.Unit, d.Unit)
+		if isCurrent {
+			a.inCur = true
+			a.item.CurrentCost += d.Cost
+			a.item.CurrentQuantity += d.Quantity
+		} else {
+			a.inBase = true
+			a.item.BaselineCost += d.Cost
+			a.item.BaselineQuantity += d.Quantity
+		}
+	}
+	for _, d := range baseline {
+		add(d, false)
+	}
+	for _, d := range current {
+		add(d, true)
+	}
+
+	report := &Report{BaselineLabel: baselineLabel, CurrentLabel: currentLabel, Items: make([]Item, 0, len(aggs))}
+	for _, a := range aggs {
+		it := a.item
+		it.Change = it.CurrentCost - it.BaselineCost
+		it.ChangePct = pctChange(it.BaselineCost, it.Change)
+
+		switch {
+		case !a.inBase:
+			it.Status = New
+			it.VolumeEffect = admin.PtrFloat64(it.Change)
+			report.NewItems++
+		case !a.inCur:
+			it.Status = Removed
+			it.VolumeEffect = admin.PtrFloat64(it.Change)
+			report.RemovedItems++
+		case isZero(it.Change):
+			it.Status = Unchanged
+		default:
+			it.Status = Changed
+		}
+		if a.inBase && a.inCur && it.BaselineQuantity != 0 && it.CurrentQuantity != 0 {
+			basePrice := it.BaselineCost / it.BaselineQuantity
+			curPrice := it.CurrentCost / it.CurrentQuantity
+			it.VolumeEffect = admin.PtrFloat64((it.CurrentQuantity - it.BaselineQuantity) * basePrice)
+			it.PriceEffect = admin.PtrFloat64((curPrice - basePrice) * it.CurrentQuantity)
+		}
+
+		switch {
+		case it.VolumeEffect != nil:
+			report.VolumeEffect += *it.VolumeEffect
+			if it.PriceEffect != nil {
+				report.PriceEffect += *it.PriceEffect
+			}
+		default:
+			report.Unattributed += it.Change
+		}
+		report.BaselineTotal += it.BaselineCost
+		report.CurrentTotal += it.CurrentCost
+		report.Items = append(report.Items, it)
+	}
+	report.Change = report.CurrentTotal - report.BaselineTotal
+	report.ChangePct = pctChange(report.BaselineTotal, report.Change)
+
+	slices.SortFunc(report.Items, func(a, b Item) int {
+		if c := cmp.Compare(math.Abs(b.Change), math.Abs(a.Change)); c != 0 {
+			return c
+		}
+		return cmp.Or(
+			strings.Compare(a.Project, b.Project),
+			strings.Compare(a.Cluster, b.Cluster),
+			strings.Compare(a.SKU, b.SKU),
+		)
+	})
+	return report
+}
+
+// pctChange returns change as a percentage of base, or nil when base is zero.
+func pctChange(base, change float64) *float64 {
+	if isZero(base) {
+		return nil
+	}
+	return admin.PtrFloat64(change / math.Abs(base) * 100)
+}
+
+// isZero reports whether v is zero to the nearest hundredth of a cent.
+func isZero(v float64) bool {
+	return math.Abs(v) < 0.0001
+}
@@ -0,0 +1,97 @@
+package compare
+
+import (
+	"testing"
+
+	"atlas-sdk-go/internal/billing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func detail(cluster, sku string, qty, cost float64) billing.Detail {
+	return billing.Detail{
+		Org:      billing.OrgInfo{ID: "org1"},
+		Project:  billing.ProjectInfo{ID: "p1", Name: "app"},
+		Cluster:  cluster,
+		SKU:      sku,
+		Category: "Clusters",
+		Quantity: qty,
+		Unit:     "server hours",
+		Cost:     cost,
+	}
+}
+
+func itemFor(t *testing.T, r *Report, cluster, sku string) Item {
+	t.Helper()
+	for _, it := range r.Items {
+		if it.Cluster == cluster && it.SKU == sku {
+			return it
+		}
+	}
+	t.Fatalf("no item for %s/%s", cluster, sku)
+	return Item{}
+}
+
+func TestCompare_AttributesVolumeAndPrice(t *testing.T) {
+	t.Parallel()
+	baseline := []billing.Detail{
+		detail("Cluster0", "NDS_AWS_INSTANCE_M10", 300, 30), // $0.10/h
+		detail("Cluster0", "NDS_AWS_INSTANCE_M10", 300, 30),
+	}
+	current := []billing.Detail{
+		detail("Cluster0", "NDS_AWS_INSTANCE_M10", 720, 86.40), // $0.12/h
+	}
+
+	report := Compare(baseline, current, "2024-04", "2024-05")
+
+	require.Len(t, report.Items, 1)
+	it := report.Items[0]
+	assert.Equal(t, Changed, it.Status)
+	assert.InDelta(t, 26.40, it.Change, 0.0001)
+	assert.InDelta(t, 44.0, *it.ChangePct, 0.0001)
+	assert.InDelta(t, 12.0, *it.VolumeEffect, 0.0001, "120 more hours at the old $0.10")
+	assert.InDelta(t, 14.40, *it.PriceEffect, 0.0001, "$0.02 more on 720 hours")
+	assert.InDelta(t, it.Change, *it.VolumeEffect+*it.PriceEffect, 0.0001)
+}
+
+func TestCompare_NewRemovedAndSortedByImpact(t *testing.T) {
+	t.Parallel()
+	baseline := []billing.Detail{
+		detail("Cluster0", "NDS_AWS_INSTANCE_M10", 720, 72),
+		detail("Old", "NDS_AWS_INSTANCE_M20", 720, 150),
+		detail("Cluster0", "NDS_AWS_STORAGE", 0, 10),
+	}
+	current := []billing.Detail{
+		detail("Cluster0", "NDS_AWS_INSTANCE_M10", 720, 72),
+		detail("New", "NDS_AWS_INSTANCE_M30", 100, 54),
+		detail("Cluster0", "NDS_AWS_STORAGE", 0, 13),
+	}
+
+	report := Compare(baseline, current, "before", "after")
+
+	require.Len(t, report.Items, 4)
+	assert.Equal(t, "Old", report.Items[0].Cluster, "largest absolute change first")
+	assert.Equal(t, Removed, report.Items[0].Status)
+	require.NotNil(t, report.Items[0].ChangePct)
+	assert.InDelta(t, -100.0, *report.Items[0].ChangePct, 0.0001, "removed items change by -100%")
+	assert.Equal(t, New, report.Items[1].Status)
+	assert.Nil(t, report.Items[1].ChangePct, "percentage change is undefined for new items")
+	assert.InDelta(t, 54.0, *report.Items[1].VolumeEffect, 0.0001)
+
+	storage := itemFor(t, report, "Cluster0", "NDS_AWS_STORAGE")
+	assert.Nil(t, storage.VolumeEffect, "no quantity, so no attribution")
+	assert.Equal(t, Unchanged, itemFor(t, report, "Cluster0", "NDS_AWS_INSTANCE_M10").Status)
+
+	assert.Equal(t, 1, report.NewItems)
+	assert.Equal(t, 1, report.RemovedItems)
+	assert.InDelta(t, -93.0, report.Change, 0.0001)
+	assert.InDelta(t, -96.0, report.VolumeEffect, 0.0001)
+	assert.InDelta(t, 3.0, report.Unattributed, 0.0001)
+
+	table := report.Table()
+	require.Len(t, table, 5, "header, three changed items, total; unchanged items are omitted")
+	assert.Equal(t, []string{"Status", "Project", "Cluster", "SKU", "before", "after",
+		"Change", "Change %", "Volume Effect", "Price Effect"}, table[0])
+	assert.Equal(t, "TOTAL", table[4][0])
+}
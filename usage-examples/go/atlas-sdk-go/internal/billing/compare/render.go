@@ -0,0 +1,58 @@
+package compare
+
+import (
+	"atlas-sdk-go/internal/billing"
+	"atlas-sdk-go/internal/data/export"
+)
+
+// Table returns the comparison as rows: a header row, one row per item, and a closing TOTAL row.
+// Unchanged items are omitted.
+func (r *Report) Table() [][]string {
+	rows := [][]string{{"Status", "Project", "Cluster", "SKU", r.BaselineLabel, r.CurrentLabel,
+		"Change", "Change %", "Volume Effect", "Price Effect"}}
+	for _, it := range r.Items {
+		if it.Status == Unchanged {
+			continue
+		}
+		rows = append(rows, []string{
+			string(it.Status),
+			it.Project,
+			it.Cluster,
+			it.SKU,
+			billing.FormatCost(it.BaselineCost),
+			billing.FormatCost(it.CurrentCost),
+			billing.FormatCost(it.Change),
+			billing.FormatPct(it.ChangePct),
+			formatOptionalCost(it.VolumeEffect),
+			formatOptionalCost(it.PriceEffect),
+		})
+	}
+	rows = append(rows, []string{
+		"TOTAL", "", "", "",
+		billing.FormatCost(r.BaselineTotal),
+		billing.FormatCost(r.CurrentTotal),
+		billing.FormatCost(r.Change),
+		billing.FormatPct(r.ChangePct),
+		billing.FormatCost(r.VolumeEffect),
+		billing.FormatCost(r.PriceEffect),
+	})
+	return rows
+}
+
+// WriteCSV writes the comparison table to a CSV file at filePath.
+func (r *Report) WriteCSV(filePath string) error {
+	return export.ToCSV(r.Table(), filePath)
+}
+
+// WriteJSON writes the full comparison, including unchanged items, to a JSON file at filePath.
+func (r *Report) WriteJSON(filePath string) error {
+	return export.ToJSON(r, filePath)
+}
+
+// formatOptionalCost formats an optional dollar amount, returning "n/a" when it is undefined.
+func formatOptionalCost(v *float64) string {
+	if v == nil {
+		return "n/a"
+	}
+	return billing.FormatCost(*v)
+}
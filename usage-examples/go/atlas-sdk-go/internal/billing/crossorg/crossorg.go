@@ -0,0 +1,128 @@
+package crossorg
+
+import (
+	"fmt"
+
+	"atlas-sdk-go/internal/billing"
+	"atlas-sdk-go/internal/billing/rollup"
+	"atlas-sdk-go/internal/data/export"
+)
+
+// OrgSpend is one organization's spend within a cross-organization bill.
+type OrgSpend struct {
+	OrgID   string    `json:"orgId"`
+	OrgName string    `json:"orgName"`
+	Paying  bool      `json:"paying"` // True for the paying organization's own usage
+	Costs   []float64 `json:"costs"`  // Cost per period, aligned with Report.Periods
+	Total   float64   `json:"total"`
+	Share   float64   `json:"share"` // Fraction of the paying organization's total bill (0-1)
+}
+
+// Report rolls up a paying organization's bill by the organization that incurred each cost.
+type Report struct {
+	PayingOrgID   string        `json:"payingOrgId"`
+	PayingOrgName string        `json:"payingOrgName"`
+	Period        rollup.Period `json:"period"`
+	Periods       []string      `json:"periods"`
+	Orgs          []OrgSpend    `json:"orgs"`         // Sorted by total, highest first
+	PeriodTotals  []float64     `json:"periodTotals"` // Paying organization's total bill per period
+	Total         float64       `json:"total"`        // Paying organization's total bill
+}
+
+// Build rolls up line items from the paying organization and its linked organizations (see
+// billing.CollectLinkedOrgLineItems) into spend per organization and period, with each
+// organization's share of the paying organization's total.
+func Build(details []billing.Detail, payingOrgID string, period rollup.Period) (*Report, error) {
+	r, err := rollup.Build(details, rollup.Options{GroupBy: []rollup.Dimension{rollup.OrgID}, Period: period})
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]string)
+	for _, d := range details {
+		if d.Org.Name != "" {
+			names[d.Org.ID] = d.Org.Name
+		}
+	}
+
+	report := &Report{
+		PayingOrgID:   payingOrgID,
+		PayingOrgName: nameOrID(names, payingOrgID),
+		Period:        period,
+		Periods:       r.Periods,
+		Orgs:          make([]OrgSpend, 0, len(r.Lines)),
+		PeriodTotals:  r.PeriodTotals,
+		Total:         r.Total,
+	}
+	for _, line := range r.Lines {
+		orgID := line.Keys[0]
+		report.Orgs = append(report.Orgs, OrgSpend{
+			OrgID:   orgID,
+			OrgName: nameOrID(names, orgID),
+			Paying:  orgID == payingOrgID,
+			Costs:   line.Costs,
+			Total:   line.Total,
+			Share:   line.Share,
+		})
+	}
+	return report, nil
+}
+
+// LinkedOrgs returns the number of linked organizations with spend, excluding the paying organization.
+func (r *Report) LinkedOrgs() int {
+	n := 0
+	for _, o := range r.Orgs {
+		if !o.Paying {
+			n++
+		}
+	}
+	return n
+}
+
+// Table returns the report as rows: a header row, a row for the paying organization's total bill,
+// then one row per organization with its cost per period, total, and share of the bill.
+func (r *Report) Table() [][]string {
+	header := []string{"Organization", "Org ID", "Role"}
+	header = append(header, r.Periods...)
+	header = append(header, "Total", "Share")
+	rows := [][]string{header}
+
+	total := []string{"TOTAL BILLED TO " + r.PayingOrgName, r.PayingOrgID, "paying org total"}
+	for _, c := range r.PeriodTotals {
+		total = append(total, billing.FormatCost(c))
+	}
+	total = append(total, billing.FormatCost(r.Total), "100.0%")
+	rows = append(rows, total)
+
+	for _, o := range r.Orgs {
+		role := "linked"
+		if o.Paying {
+			role = "paying (own usage)"
+		}
+		row := []string{o.OrgName, o.OrgID, role}
+		for _, c := range o.Costs {
+			row = append(row, billing.FormatCost(c))
+		}
+		row = append(row, billing.FormatCost(o.Total), fmt.Sprintf("%.1f%%", o.Share*100))
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// WriteCSV writes the report table to a CSV file at filePath.
+func (r *Report) WriteCSV(filePath string) error {
+	return export.ToCSV(r.Table(), filePath)
+}
+
+// WriteJSON writes the report to a JSON file at filePath.
+func (r *Report) WriteJSON(filePath string) error {
+	return export.ToJSON(r, filePath)
+}
+
+// nameOrID returns the organization's name, or its ID if the name is unknown.
+func nameOrID(names map[string]string, orgID string) string {
+	if name, ok := names[orgID]; ok {
+		return name
+	}
+	return orgID
+}
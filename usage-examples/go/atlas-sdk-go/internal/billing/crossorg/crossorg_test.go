@@ -0,0 +1,56 @@
+package crossorg
+
+import (
+	"testing"
+	"time"
+
+	"atlas-sdk-go/internal/billing"
+	"atlas-sdk-go/internal/billing/rollup"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func detail(orgID, orgName string, month time.Month, cost float64) billing.Detail {
+	return billing.Detail{
+		Org:  billing.OrgInfo{ID: orgID, Name: orgName},
+		Cost: cost,
+		Date: time.Date(2024, month, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestBuild_SharesOfPayingOrgTotal(t *testing.T) {
+	t.Parallel()
+	details := []billing.Detail{
+		detail("pay", "Central", 4, 10),
+		detail("pay", "Central", 5, 10),
+		detail("c1", "Team A", 4, 40),
+		detail("c1", "Team A", 5, 20),
+		detail("c2", "Team B", 5, 20),
+	}
+
+	report, err := Build(details, "pay", rollup.Month)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Central", report.PayingOrgName)
+	assert.Equal(t, []string{"2024-04", "2024-05"}, report.Periods)
+	assert.Equal(t, []float64{50, 50}, report.PeriodTotals)
+	assert.InDelta(t, 100.0, report.Total, 0.001)
+	assert.Equal(t, 2, report.LinkedOrgs())
+
+	require.Len(t, report.Orgs, 3)
+	top := report.Orgs[0]
+	assert.Equal(t, "Team A", top.OrgName)
+	assert.False(t, top.Paying)
+	assert.Equal(t, []float64{40, 20}, top.Costs)
+	assert.InDelta(t, 0.6, top.Share, 0.001)
+
+	paying := report.Orgs[2]
+	assert.Equal(t, "pay", paying.OrgID)
+	assert.True(t, paying.Paying)
+
+	table := report.Table()
+	require.Len(t, table, 5)
+	assert.Equal(t, []string{"TOTAL BILLED TO Central", "pay", "paying org total", "50.00", "50.00", "100.00", "100.0%"}, table[1])
+	assert.Equal(t, []string{"Team A", "c1", "linked", "40.00", "20.00", "60.00", "60.0%"}, table[2])
+}
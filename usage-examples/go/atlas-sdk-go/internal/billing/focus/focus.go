@@ -0,0 +1,238 @@
+package focus
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"atlas-sdk-go/internal/billing"
+	"atlas-sdk-go/internal/data/export"
+
+	"go.mongodb.org/atlas-sdk/v20250219001/admin"
+)
+
+// Version is the FOCUS specification version the export follows.
+const Version = "1.0"
+
+const (
+	providerName = "MongoDB"
+	currency     = "USD"
+)
+
+// Row is a single FOCUS cost and usage record. JSON field names are the FOCUS column names;
+// nullable columns are pointers or empty strings. Columns prefixed with x_ are Atlas-specific
+// extensions, as the specification requires.
+type Row struct {
+	BilledCost          float64   `json:"BilledCost"`
+	BillingAccountId    string    `json:"BillingAccountId"`
+	BillingAccountName  string    `json:"BillingAccountName"`
+	BillingCurrency     string    `json:"BillingCurrency"`
+	BillingPeriodEnd    time.Time `json:"BillingPeriodEnd"`
+	BillingPeriodStart  time.Time `json:"BillingPeriodStart"`
+	ChargeCategory      string    `json:"ChargeCategory"`
+	ChargeDescription   string    `json:"ChargeDescription"`
+	ChargeFrequency     string    `json:"ChargeFrequency"`
+	ChargePeriodEnd     time.Time `json:"ChargePeriodEnd"`
+	ChargePeriodStart   time.Time `json:"ChargePeriodStart"`
+	ConsumedQuantity    *float64  `json:"ConsumedQuantity"`
+	ConsumedUnit        string    `json:"ConsumedUnit"`
+	ContractedCost      float64   `json:"ContractedCost"`
+	ContractedUnitPrice *float64  `json:"ContractedUnitPrice"`
+	EffectiveCost       float64   `json:"EffectiveCost"`
+	InvoiceIssuerName   string    `json:"InvoiceIssuerName"`
+	ListCost            float64   `json:"ListCost"`
+	ListUnitPrice       *float64  `json:"ListUnitPrice"`
+	PricingCategory     string    `json:"PricingCategory"`
+	PricingQuantity     *float64  `json:"PricingQuantity"`
+	PricingUnit         string    `json:"PricingUnit"`
+	ProviderName        string    `json:"ProviderName"`
+	PublisherName       string    `json:"PublisherName"`
+	RegionId            string    `json:"RegionId"`
+	RegionName          string    `json:"RegionName"`
+	ResourceId          string    `json:"ResourceId"`
+	ResourceName        string    `json:"ResourceName"`
+	ResourceType        string    `json:"ResourceType"`
+	ServiceCategory     string    `json:"ServiceCategory"`
+	ServiceName         string    `json:"ServiceName"`
+	SkuId               string    `json:"SkuId"`
+	SkuPriceId          string    `json:"SkuPriceId"`
+	SubAccountId        string    `json:"SubAccountId"`
+	SubAccountName      string    `json:"SubAccountName"`
+	Tags                string    `json:"Tags"` // JSON object
+
+	XCloudProvider string `json:"x_CloudProvider"`
+	XInstanceSize  string `json:"x_InstanceSize"`
+	XAtlasCategory string `json:"x_AtlasCategory"`
+}
+
+// Columns lists the FOCUS columns in the order they are written to CSV.
+var Columns = []string{
+	"BilledCost", "BillingAccountId", "BillingAccountName", "BillingCurrency",
+	"BillingPeriodEnd", "BillingPeriodStart", "ChargeCategory", "ChargeDescription",
+	"ChargeFrequency", "ChargePeriodEnd", "ChargePeriodStart", "ConsumedQuantity",
+	"ConsumedUnit", "ContractedCost", "ContractedUnitPrice", "EffectiveCost",
+	"InvoiceIssuerName", "ListCost", "ListUnitPrice", "PricingCategory",
+	"PricingQuantity", "PricingUnit", "ProviderName", "PublisherName",
+	"RegionId", "RegionName", "ResourceId", "ResourceName", "ResourceType",
+	"ServiceCategory", "ServiceName", "SkuId", "SkuPriceId",
+	"SubAccountId", "SubAccountName", "Tags",
+	"x_CloudProvider", "x_InstanceSize", "x_AtlasCategory",
+}
+
+// Convert maps billing details to FOCUS rows.
+//
+// The Atlas organization is the billing account and the project is the sub-account. Clusters
+// are resources, identified as "<project ID>/<cluster name>". Atlas bills monthly, so the billing
+// period is the calendar month containing the charge. BilledCost, EffectiveCost, and
+// ContractedCost are the line item's cost, which is already net of discounts; ListCost adds the
+// discount back. Usage quantities are only reported as consumed for Usage charges. ServiceCategory,
+// ServiceName, and ChargeCategory come from CategoryMapping. Sales tax is excluded, so the rows
+// sum to the invoice subtotal before tax.
+func Convert(details []billing.Detail) []Row {
+	rows := make([]Row, 0, len(details))
+	for _, d := range details {
+		svc := serviceFor(d.Category, d.SKU)
+		start := d.Date.UTC()
+		end := d.EndDate.UTC()
+		if d.EndDate.IsZero() || !end.After(start) {
+			end = start.AddDate(0, 0, 1)
+		}
+		periodStart := time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+		row := Row{
+			BilledCost:         d.Cost,
+			BillingAccountId:   d.Org.ID,
+			BillingAccountName: d.Org.Name,
+			BillingCurrency:    currency,
+			BillingPeriodStart: periodStart,
+			BillingPeriodEnd:   periodStart.AddDate(0, 1, 0),
+			ChargeCategory:     svc.ChargeCategory,
+			ChargeDescription:  description(d),
+			ChargeFrequency:    frequency(svc.ChargeCategory),
+			ChargePeriodStart:  start,
+			ChargePeriodEnd:    end,
+			ContractedCost:     d.Cost,
+			EffectiveCost:      d.Cost,
+			InvoiceIssuerName:  providerName,
+			ListCost:           d.Cost + d.Discount,
+			ProviderName:       providerName,
+			PublisherName:      providerName,
+			RegionId:           d.Region,
+			RegionName:         d.Region,
+			ServiceCategory:    svc.ServiceCategory,
+			ServiceName:        svc.ServiceName,
+			SkuId:              d.SKU,
+			SkuPriceId:         skuPriceID(d),
+			SubAccountId:       d.Project.ID,
+			SubAccountName:     d.Project.Name,
+			Tags:               tags(d),
+			XCloudProvider:     d.Provider,
+			XInstanceSize:      d.Instance,
+			XAtlasCategory:     d.Category,
+		}
+		if d.HasCluster() {
+			row.ResourceId = d.Project.ID + "/" + d.Cluster
+			row.ResourceName = d.Cluster
+			row.ResourceType = "Cluster"
+		}
+		if d.Quantity != 0 {
+			row.PricingQuantity = admin.PtrFloat64(d.Quantity)
+			row.PricingUnit = d.Unit
+			row.ContractedUnitPrice = admin.PtrFloat64(d.UnitPrice)
+			row.ListUnitPrice = admin.PtrFloat64(row.ListCost / d.Quantity)
+			if svc.ChargeCategory == ChargeUsage {
+				row.ConsumedQuantity = admin.PtrFloat64(d.Quantity)
+				row.ConsumedUnit = d.Unit
+			}
+		}
+		if svc.ChargeCategory == ChargeUsage {
+			row.PricingCategory = "Standard"
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// WriteCSV writes rows to a CSV file at filePath with a header of FOCUS column names.
+// Timestamps are RFC 3339 in UTC and null values are empty.
+func WriteCSV(rows []Row, filePath string) error {
+	return export.ToCSVWithMapper(rows, filePath, Columns, func(r Row) []string {
+		return []string{
+			formatFloat(r.BilledCost), r.BillingAccountId, r.BillingAccountName, r.BillingCurrency,
+			formatTime(r.BillingPeriodEnd), formatTime(r.BillingPeriodStart), r.ChargeCategory, r.ChargeDescription,
+			r.ChargeFrequency, formatTime(r.ChargePeriodEnd), formatTime(r.ChargePeriodStart), formatOptional(r.ConsumedQuantity),
+			r.ConsumedUnit, formatFloat(r.ContractedCost), formatOptional(r.ContractedUnitPrice), formatFloat(r.EffectiveCost),
+			r.InvoiceIssuerName, formatFloat(r.ListCost), formatOptional(r.ListUnitPrice), r.PricingCategory,
+			formatOptional(r.PricingQuantity), r.PricingUnit, r.ProviderName, r.PublisherName,
+			r.RegionId, r.RegionName, r.ResourceId, r.ResourceName, r.ResourceType,
+			r.ServiceCategory, r.ServiceName, r.SkuId, r.SkuPriceId,
+			r.SubAccountId, r.SubAccountName, r.Tags,
+			r.XCloudProvider, r.XInstanceSize, r.XAtlasCategory,
+		}
+	})
+}
+
+// WriteJSON writes rows to a JSON file at filePath.
+func WriteJSON(rows []Row, filePath string) error {
+	return export.ToJSON(rows, filePath)
+}
+
+// description returns a human-readable charge description.
+func description(d billing.Detail) string {
+	if d.Note != "" {
+		return d.SKU + " - " + d.Note
+	}
+	return d.SKU
+}
+
+// frequency returns the FOCUS ChargeFrequency for a charge category.
+func frequency(chargeCategory string) string {
+	if chargeCategory == ChargeUsage {
+		return "Usage-Based"
+	}
+	return "One-Time"
+}
+
+// skuPriceID identifies the price applied: the SKU, plus its usage tier for tiered SKUs.
+func skuPriceID(d billing.Detail) string {
+	if d.Tier != "" {
+		return d.SKU + ":" + d.Tier
+	}
+	return d.SKU
+}
+
+// tags returns Atlas classification attributes as a FOCUS Tags JSON object.
+func tags(d billing.Detail) string {
+	t := map[string]string{}
+	if d.HasCluster() {
+		t["atlas-cluster"] = d.Cluster
+	}
+	for k, v := range map[string]string{
+		"atlas-instance-class": d.InstanceClass,
+		"atlas-storage-type":   d.StorageType,
+	} {
+		if v != "" {
+			t[k] = v
+		}
+	}
+	if len(t) == 0 {
+		return "{}"
+	}
+	b, _ := json.Marshal(t)
+	return string(b)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func formatOptional(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return formatFloat(*v)
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
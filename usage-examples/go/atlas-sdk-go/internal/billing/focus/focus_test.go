@@ -0,0 +1,118 @@
+package focus
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"atlas-sdk-go/internal/billing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func instanceDetail() billing.Detail {
+	start := time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC)
+	return billing.Detail{
+		Org:           billing.OrgInfo{ID: "org1", Name: "Org One"},
+		Project:       billing.ProjectInfo{ID: "p1", Name: "app"},
+		Cluster:       "Cluster0",
+		SKU:           "NDS_AWS_INSTANCE_M30",
+		Cost:          11.66,
+		Date:          start,
+		EndDate:       start.Add(24 * time.Hour),
+		Provider:      "AWS",
+		Instance:      "M30",
+		Category:      "Clusters",
+		Region:        "US_EAST_1",
+		InstanceClass: "general",
+		Quantity:      24,
+		Unit:          "server hours",
+		UnitPrice:     0.54,
+		Discount:      1.30,
+	}
+}
+
+func TestConvert_MapsUsageLineItem(t *testing.T) {
+	t.Parallel()
+
+	rows := Convert([]billing.Detail{instanceDetail()})
+
+	require.Len(t, rows, 1)
+	r := rows[0]
+	assert.Equal(t, "Databases", r.ServiceCategory)
+	assert.Equal(t, "MongoDB Atlas Dedicated Clusters", r.ServiceName)
+	assert.Equal(t, ChargeUsage, r.ChargeCategory)
+	assert.Equal(t, "Usage-Based", r.ChargeFrequency)
+	assert.Equal(t, "Standard", r.PricingCategory)
+	assert.Equal(t, 11.66, r.BilledCost)
+	assert.Equal(t, 11.66, r.EffectiveCost)
+	assert.InDelta(t, 12.96, r.ListCost, 0.0001, "list cost adds the discount back")
+	assert.InDelta(t, 0.54, *r.ListUnitPrice, 0.0001)
+	assert.Equal(t, 24.0, *r.ConsumedQuantity)
+	assert.Equal(t, "server hours", r.ConsumedUnit)
+	assert.Equal(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), r.BillingPeriodStart)
+	assert.Equal(t, time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), r.BillingPeriodEnd)
+	assert.Equal(t, time.Date(2024, 5, 4, 0, 0, 0, 0, time.UTC), r.ChargePeriodEnd)
+	assert.Equal(t, "org1", r.BillingAccountId)
+	assert.Equal(t, "p1", r.SubAccountId)
+	assert.Equal(t, "p1/Cluster0", r.ResourceId)
+	assert.Equal(t, "Cluster", r.ResourceType)
+	assert.Equal(t, "US_EAST_1", r.RegionId)
+	assert.Equal(t, "USD", r.BillingCurrency)
+	assert.JSONEq(t, `{"atlas-cluster": "Cluster0", "atlas-instance-class": "general"}`, r.Tags)
+}
+
+func TestConvert_CreditsAdjustmentsAndUnknownCategories(t *testing.T) {
+	t.Parallel()
+	date := time.Date(2024, 5, 31, 0, 0, 0, 0, time.UTC)
+	details := []billing.Detail{
+		{SKU: "CREDIT", Category: "Credits", Cost: -50, Date: date, Cluster: billing.NoCluster},
+		{SKU: "MINIMUM_CHARGE", Category: "Credits", Cost: 20, Date: date},
+		{SKU: "NDS_SOMETHING_NEW", Category: "Other", Cost: 1, Date: date, Quantity: 3, Unit: "units"},
+	}
+
+	rows := Convert(details)
+
+	require.Len(t, rows, 3)
+	assert.Equal(t, ChargeCredit, rows[0].ChargeCategory)
+	assert.Equal(t, "One-Time", rows[0].ChargeFrequency)
+	assert.Empty(t, rows[0].ResourceId, "line items without a cluster have no resource")
+	assert.Equal(t, "{}", rows[0].Tags)
+	assert.Empty(t, rows[0].PricingCategory)
+	assert.Equal(t, date.AddDate(0, 0, 1), rows[0].ChargePeriodEnd, "missing end dates default to one day")
+	assert.Equal(t, ChargeAdjustment, rows[1].ChargeCategory)
+	assert.Equal(t, OtherService.ServiceName, rows[2].ServiceName)
+	assert.Equal(t, "Other", rows[2].ServiceCategory)
+}
+
+func TestWriteCSV(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "focus.csv")
+
+	require.NoError(t, WriteCSV(Convert([]billing.Detail{instanceDetail()}), path))
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, Columns, records[0])
+	require.Len(t, records[1], len(Columns))
+	col := func(name string) string {
+		for i, c := range Columns {
+			if c == name {
+				return records[1][i]
+			}
+		}
+		t.Fatalf("no column %s", name)
+		return ""
+	}
+	assert.Equal(t, "11.66", col("BilledCost"))
+	assert.Equal(t, "2024-05-03T00:00:00Z", col("ChargePeriodStart"))
+	assert.Equal(t, "NDS_AWS_INSTANCE_M30", col("SkuId"))
+	assert.Equal(t, "M30", col("x_InstanceSize"))
+}
@@ -0,0 +1,56 @@
+package focus
+
+import "strings"
+
+// FOCUS charge categories. Tax is not emitted: line items are pre-tax, and sales tax is only
+// reported as an invoice total.
+const (
+	ChargeUsage      = "Usage"
+	ChargePurchase   = "Purchase"
+	ChargeCredit     = "Credit"
+	ChargeAdjustment = "Adjustment"
+)
+
+// Service is how a billing category is described in FOCUS.
+type Service struct {
+	ServiceCategory string // One of the FOCUS ServiceCategory values
+	ServiceName     string
+	ChargeCategory  string // One of the Charge* constants
+}
+
+// CategoryMapping maps each billing.Detail Category (see the SKU classification rules) to its
+// FOCUS service. Categories not listed map to OtherService.
+var CategoryMapping = map[string]Service{
+	"Clusters":                       {"Databases", "MongoDB Atlas Dedicated Clusters", ChargeUsage},
+	"Serverless Instances":           {"Databases", "MongoDB Atlas Serverless", ChargeUsage},
+	"Storage":                        {"Storage", "MongoDB Atlas Storage", ChargeUsage},
+	"Backup":                         {"Storage", "MongoDB Atlas Cloud Backup", ChargeUsage},
+	"Legacy Backup":                  {"Storage", "MongoDB Atlas Legacy Backup", ChargeUsage},
+	"Data Transfer":                  {"Networking", "MongoDB Atlas Data Transfer", ChargeUsage},
+	"Atlas Data Federation":          {"Analytics", "MongoDB Atlas Data Federation", ChargeUsage},
+	"Atlas Stream Processing":        {"Analytics", "MongoDB Atlas Stream Processing", ChargeUsage},
+	"BI Connector":                   {"Analytics", "MongoDB Atlas BI Connector", ChargeUsage},
+	"Charts":                         {"Analytics", "MongoDB Charts", ChargeUsage},
+	"App Services":                   {"Web", "MongoDB Atlas App Services", ChargeUsage},
+	"Cloud Manager Standard/Premium": {"Management and Governance", "MongoDB Cloud Manager", ChargeUsage},
+	"Premium Features":               {"Security", "MongoDB Atlas Premium Features", ChargeUsage},
+	"Support":                        {"Other", "MongoDB Atlas Support", ChargePurchase},
+	"Flex Consulting":                {"Other", "MongoDB Flex Consulting", ChargePurchase},
+	"Credits":                        {"Other", "MongoDB Atlas Credits", ChargeCredit},
+}
+
+// OtherService is the FOCUS service for categories missing from CategoryMapping.
+var OtherService = Service{"Other", "MongoDB Atlas", ChargeUsage}
+
+// serviceFor returns the FOCUS service for a line item. Minimum-charge line items, which
+// classify as Credits, are reported as adjustments.
+func serviceFor(category, sku string) Service {
+	s, ok := CategoryMapping[category]
+	if !ok {
+		s = OtherService
+	}
+	if strings.Contains(strings.ToUpper(sku), "MINIMUM_CHARGE") {
+		s.ChargeCategory = ChargeAdjustment
+	}
+	return s
+}
@@ -0,0 +1,16 @@
+package billing
+
+import "fmt"
+
+// FormatCost formats a dollar amount with two decimal places.
+func FormatCost(v float64) string {
+	return fmt.Sprintf("%.2f", v)
+}
+
+// FormatPct formats an optional signed percentage, returning "n/a" when it is undefined.
+func FormatPct(v *float64) string {
+	if v == nil {
+		return "n/a"
+	}
+	return fmt.Sprintf("%+.1f%%", *v)
+}
@@ -0,0 +1,17 @@
+package billing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/atlas-sdk/v20250219001/admin"
+)
+
+func TestFormatCostAndPct(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "1234.50", FormatCost(1234.5))
+	assert.Equal(t, "-0.01", FormatCost(-0.005001))
+	assert.Equal(t, "+12.3%", FormatPct(admin.PtrFloat64(12.34)))
+	assert.Equal(t, "-5.0%", FormatPct(admin.PtrFloat64(-5)))
+	assert.Equal(t, "n/a", FormatPct(nil))
+}
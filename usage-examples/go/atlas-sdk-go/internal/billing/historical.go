@@ -0,0 +1,176 @@
+package billing
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"atlas-sdk-go/internal/errors"
+
+	"go.mongodb.org/atlas-sdk/v20250219001/admin"
+)
+
+// DefaultInvoiceWorkers is the number of invoices fetched in parallel when the caller does not set a limit.
+const DefaultInvoiceWorkers = 4
+
+// GetInvoicesByID fetches the full invoice, including line items, for each of the given invoice IDs.
+// Requests run with at most `workers` in flight at once (DefaultInvoiceWorkers if workers <= 0).
+// Results are returned in the same order as invoiceIDs. If any request fails, the remaining
+// requests are cancelled and the first error is returned.
+//
+// Required Permissions:
+//   - Organization Billing Viewer role can view invoices for the organization
+func GetInvoicesByID(ctx context.Context, sdk admin.InvoicesApi, orgID string, invoiceIDs []string, workers int) ([]admin.BillingInvoice, error) {
+	if orgID == "" {
+		return nil, &errors.ValidationError{Message: "organization ID cannot be empty"}
+	}
+	if len(invoiceIDs) == 0 {
+		return nil, nil
+	}
+	if workers <= 0 {
+		workers = DefaultInvoiceWorkers
+	}
+	workers = min(workers, len(invoiceIDs))
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	invoices := make([]admin.BillingInvoice, len(invoiceIDs))
+	jobs := make(chan int)
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	fail := func(err error) {
+		once.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				invoice, _, err := sdk.GetInvoice(ctx, orgID, invoiceIDs[i]).Execute()
+				if err != nil {
+					fail(errors.FormatError("get invoice", invoiceIDs[i], err))
+					continue
+				}
+				if invoice == nil {
+					fail(&errors.NotFoundError{Resource: "invoice", ID: invoiceIDs[i]})
+					continue
+				}
+				invoices[i] = *invoice
+			}
+		}()
+	}
+
+	for i := range invoiceIDs {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return invoices, nil
+}
+
+// GetInvoicesForOrg lists every invoice for an organization that matches opts (for example,
+// WithStatusNames or WithDateRange) and fetches each one with its line items, in listing order.
+// Invoices are fetched with at most `workers` requests in flight (DefaultInvoiceWorkers if workers <= 0).
+// Returns nil and no error if no invoices match.
+//
+// Required Permissions:
+//   - Organization Billing Viewer role can view invoices for the organization
+func GetInvoicesForOrg(ctx context.Context, sdk admin.InvoicesApi, orgID string, workers int, opts ...InvoiceOption) ([]admin.BillingInvoice, error) {
+	metadata, err := ListAllInvoicesForOrg(ctx, sdk, &admin.ListInvoicesApiParams{OrgId: orgID}, opts...)
+	if err != nil {
+		return nil, errors.WithContext(err, "listing invoices")
+	}
+	if len(metadata) == 0 {
+		return nil, nil
+	}
+
+	invoiceIDs := make([]string, 0, len(metadata))
+	for _, m := range metadata {
+		if id := m.GetId(); id != "" {
+			invoiceIDs = append(invoiceIDs, id)
+		}
+	}
+
+	invoices, err := GetInvoicesByID(ctx, sdk, orgID, invoiceIDs, workers)
+	if err != nil {
+		return nil, errors.WithContext(err, "fetching invoice line items")
+	}
+	return invoices, nil
+}
+
+// CollectInvoiceLineItems retrieves every invoice matching the given options (for example,
+// WithDateRange or WithStatusNames), fetches each full invoice with its line items, and
+// transforms the line items into billing Details. Unlike CollectLineItemBillingData, this
+// covers closed and historical invoices, not only the pending one.
+// Invoices are fetched with at most `workers` requests in flight (DefaultInvoiceWorkers if workers <= 0).
+// SKUs are classified with classifier, or the embedded rules if classifier is nil.
+// Returns nil and no error if no invoices match.
+//
+// Required Permissions:
+//   - Organization Billing Viewer role can view invoices for the organization
+func CollectInvoiceLineItems(ctx context.Context, sdk admin.InvoicesApi, orgSdk admin.OrganizationsApi, orgID string, workers int, classifier *SKUClassifier, opts ...InvoiceOption) ([]Detail, error) {
+	invoices, err := GetInvoicesForOrg(ctx, sdk, orgID, workers, opts...)
+	if err != nil || len(invoices) == 0 {
+		return nil, err
+	}
+
+	orgName, err := getOrganizationName(ctx, orgSdk, orgID)
+	if err != nil {
+		// Non-critical error, continue with orgID as name
+		fmt.Printf("Warning: %v\n", err)
+		orgName = orgID
+	}
+
+	billingDetails, err := processInvoices(invoices, orgID, orgName, nil, classifier)
+	if err != nil {
+		return nil, errors.WithContext(err, "processing invoices")
+	}
+	return billingDetails, nil
+}
+
+// CollectInvoiceDetails fetches a single invoice and transforms its line items into billing Details.
+// SKUs are classified with classifier, or the embedded rules if classifier is nil.
+//
+// Required Permissions:
+//   - Organization Billing Viewer role can view invoices for the organization
+func CollectInvoiceDetails(ctx context.Context, sdk admin.InvoicesApi, orgSdk admin.OrganizationsApi, orgID, invoiceID string, classifier *SKUClassifier) ([]Detail, error) {
+	invoices, err := GetInvoicesByID(ctx, sdk, orgID, []string{invoiceID}, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	orgName, err := getOrganizationName(ctx, orgSdk, orgID)
+	if err != nil {
+		// Non-critical error, continue with orgID as name
+		fmt.Printf("Warning: %v\n", err)
+		orgName = orgID
+	}
+
+	billingDetails, err := processInvoices(invoices, orgID, orgName, nil, classifier)
+	if err != nil {
+		return nil, errors.WithContext(err, "processing invoice")
+	}
+	return billingDetails, nil
+}
@@ -0,0 +1,125 @@
+package billing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/atlas-sdk/v20250219001/admin"
+)
+
+func newTestAtlasClient(t *testing.T, handler http.HandlerFunc) *admin.APIClient {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	client, err := admin.NewClient(admin.UseBaseURL(server.URL))
+	require.NoError(t, err)
+	return client
+}
+
+// invoiceHandler serves invoice list, invoice detail, and organization requests for a single org
+func invoiceHandler(t *testing.T, orgID string, invoiceIDs []string, inFlight, maxInFlight *int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/orgs/"+orgID+"/invoices"):
+			results := make([]string, 0, len(invoiceIDs))
+			for _, id := range invoiceIDs {
+				results = append(results, fmt.Sprintf(`{"id": %q, "statusName": "CLOSED"}`, id))
+			}
+			_, _ = fmt.Fprintf(w, `{"results": [%s], "totalCount": %d}`, strings.Join(results, ","), len(invoiceIDs))
+		case strings.Contains(r.URL.Path, "/orgs/"+orgID+"/invoices/"):
+			n := atomic.AddInt32(inFlight, 1)
+			defer atomic.AddInt32(inFlight, -1)
+			for {
+				cur := atomic.LoadInt32(maxInFlight)
+				if n <= cur || atomic.CompareAndSwapInt32(maxInFlight, cur, n) {
+					break
+				}
+			}
+			id := path.Base(r.URL.Path)
+			_, _ = fmt.Fprintf(w, `{"id": %q, "lineItems": [{"sku": "ATLAS_AWS_INSTANCE_M10", "totalPriceCents": 1000, "groupName": "proj-%s", "startDate": "2024-05-01T00:00:00Z"}]}`, id, id)
+		case strings.HasSuffix(r.URL.Path, "/orgs/"+orgID):
+			_, _ = w.Write([]byte(`{"id": "` + orgID + `", "name": "Test Org"}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}
+}
+
+func TestGetInvoicesByID_PreservesOrderAndBoundsConcurrency(t *testing.T) {
+	t.Parallel()
+	orgID := "org123"
+	ids := []string{"inv_1", "inv_2", "inv_3", "inv_4", "inv_5"}
+	var inFlight, maxInFlight int32
+	client := newTestAtlasClient(t, invoiceHandler(t, orgID, ids, &inFlight, &maxInFlight))
+
+	invoices, err := GetInvoicesByID(context.Background(), client.InvoicesApi, orgID, ids, 2)
+
+	require.NoError(t, err)
+	require.Len(t, invoices, len(ids))
+	for i, id := range ids {
+		assert.Equal(t, id, invoices[i].GetId(), "results should be in request order")
+	}
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxInFlight), int32(2), "should not exceed worker limit")
+}
+
+func TestGetInvoicesByID_ApiError(t *testing.T) {
+	t.Parallel()
+	client := newTestAtlasClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"detail": "server error"}`))
+	})
+
+	invoices, err := GetInvoicesByID(context.Background(), client.InvoicesApi, "org123", []string{"inv_1", "inv_2"}, 2)
+
+	require.Error(t, err)
+	assert.Nil(t, invoices)
+	assert.Contains(t, err.Error(), "get invoice")
+}
+
+func TestGetInvoicesByID_EmptyOrgID(t *testing.T) {
+	t.Parallel()
+	_, err := GetInvoicesByID(context.Background(), nil, "", []string{"inv_1"}, 1)
+	require.Error(t, err)
+}
+
+func TestCollectInvoiceLineItems_Success(t *testing.T) {
+	t.Parallel()
+	orgID := "org123"
+	ids := []string{"inv_1", "inv_2"}
+	var inFlight, maxInFlight int32
+	client := newTestAtlasClient(t, invoiceHandler(t, orgID, ids, &inFlight, &maxInFlight))
+
+	details, err := CollectInvoiceLineItems(context.Background(), client.InvoicesApi, client.OrganizationsApi, orgID, 0, nil,
+		WithStatusNames([]string{"CLOSED"}))
+
+	require.NoError(t, err)
+	require.Len(t, details, 2, "Should return one line item per invoice")
+	assert.Equal(t, "Test Org", details[0].Org.Name)
+	assert.Equal(t, "proj-inv_1", details[0].Project.Name)
+	assert.Equal(t, "Clusters", details[0].Category)
+	assert.InDelta(t, 10.0, details[1].Cost, 0.001)
+}
+
+func TestCollectInvoiceDetails_Success(t *testing.T) {
+	t.Parallel()
+	orgID := "org123"
+	var inFlight, maxInFlight int32
+	client := newTestAtlasClient(t, invoiceHandler(t, orgID, nil, &inFlight, &maxInFlight))
+
+	details, err := CollectInvoiceDetails(context.Background(), client.InvoicesApi, client.OrganizationsApi, orgID, "inv_7", nil)
+
+	require.NoError(t, err)
+	require.Len(t, details, 1)
+	assert.Equal(t, "proj-inv_7", details[0].Project.Name)
+	assert.Equal(t, "Test Org", details[0].Org.Name)
+}
@@ -2,9 +2,11 @@ package billing
 
 import (
 	"context"
+	"iter"
 	"time"
 
 	"atlas-sdk-go/internal/errors"
+	"atlas-sdk-go/internal/pagination"
 
 	"go.mongodb.org/atlas-sdk/v20250219001/admin"
 )
@@ -83,14 +85,78 @@ func WithOrderBy(orderBy string) InvoiceOption {
 //   - Organization Billing Viewer role can view invoices for the organization
 //   - Organization Billing Admin or Organization Owner role can view invoices and linked invoices for the organization
 func ListInvoicesForOrg(ctx context.Context, sdk admin.InvoicesApi, p *admin.ListInvoicesApiParams, opts ...InvoiceOption) (*admin.PaginatedApiInvoiceMetadata, error) {
+	params := buildInvoiceParams(p, opts...)
+
+	r, _, err := newListInvoicesRequest(ctx, sdk, params).Execute()
+	if err != nil {
+		return nil, errors.FormatError("list invoices", p.OrgId, err)
+	}
+	if r == nil || !r.HasResults() || len(r.GetResults()) == 0 {
+		return nil, &errors.NotFoundError{Resource: "Invoices", ID: p.OrgId}
+	}
+	return r, nil
+}
+
+// IterateInvoicesForOrg returns an iterator over every invoice for the given Atlas organization,
+// requesting additional pages as needed until the reported total count is reached.
+// It accepts the same options as ListInvoicesForOrg; WithPageNum sets the first page to read and
+// WithItemsPerPage sets the page size. The iterator stops early if the context is cancelled.
+//
+// Required Permissions:
+//   - Organization Billing Viewer role can view invoices for the organization
+//   - Organization Billing Admin or Organization Owner role can view invoices and linked invoices for the organization
+func IterateInvoicesForOrg(ctx context.Context, sdk admin.InvoicesApi, p *admin.ListInvoicesApiParams, opts ...InvoiceOption) iter.Seq2[admin.BillingInvoiceMetadata, error] {
+	params := buildInvoiceParams(p, opts...)
+
+	startPage := 1
+	if params.PageNum != nil {
+		startPage = *params.PageNum
+	}
+	itemsPerPage := pagination.DefaultItemsPerPage
+	if params.ItemsPerPage != nil {
+		itemsPerPage = *params.ItemsPerPage
+	}
+
+	fetch := func(ctx context.Context, pageNum, itemsPerPage int) ([]admin.BillingInvoiceMetadata, int, error) {
+		pageParams := *params
+		pageParams.PageNum = &pageNum
+		pageParams.ItemsPerPage = &itemsPerPage
+		includeCount := true
+		pageParams.IncludeCount = &includeCount
+
+		r, _, err := newListInvoicesRequest(ctx, sdk, &pageParams).Execute()
+		if err != nil {
+			return nil, 0, errors.FormatError("list invoices", p.OrgId, err)
+		}
+		return r.GetResults(), r.GetTotalCount(), nil
+	}
+	return pagination.Iterate(ctx, fetch, startPage, itemsPerPage)
+}
+
+// ListAllInvoicesForOrg collects every page of invoices for the given Atlas organization.
+// It returns the combined results, or an error if any page fails to load.
+// Unlike ListInvoicesForOrg, an organization with no invoices returns an empty slice.
+func ListAllInvoicesForOrg(ctx context.Context, sdk admin.InvoicesApi, p *admin.ListInvoicesApiParams, opts ...InvoiceOption) ([]admin.BillingInvoiceMetadata, error) {
+	invoices, err := pagination.Collect(IterateInvoicesForOrg(ctx, sdk, p, opts...))
+	if err != nil {
+		return nil, err
+	}
+	return invoices, nil
+}
+
+// buildInvoiceParams applies opts to a fresh set of parameters for the organization in p.
+func buildInvoiceParams(p *admin.ListInvoicesApiParams, opts ...InvoiceOption) *admin.ListInvoicesApiParams {
 	params := &admin.ListInvoicesApiParams{
 		OrgId: p.OrgId,
 	}
-
 	for _, opt := range opts {
 		opt(params)
 	}
+	return params
+}
 
+// newListInvoicesRequest builds a ListInvoices request with any parameters that are set.
+func newListInvoicesRequest(ctx context.Context, sdk admin.InvoicesApi, params *admin.ListInvoicesApiParams) admin.ListInvoicesApiRequest {
 	req := sdk.ListInvoices(ctx, params.OrgId)
 
 	if params.IncludeCount != nil {
@@ -120,13 +186,5 @@ func ListInvoicesForOrg(ctx context.Context, sdk admin.InvoicesApi, p *admin.Lis
 	if params.OrderBy != nil {
 		req = req.OrderBy(*params.OrderBy)
 	}
-
-	r, _, err := req.Execute()
-	if err != nil {
-		return nil, errors.FormatError("list invoices", p.OrgId, err)
-	}
-	if r == nil || !r.HasResults() || len(r.GetResults()) == 0 {
-		return nil, &errors.NotFoundError{Resource: "Invoices", ID: p.OrgId}
-	}
-	return r, nil
+	return req
 }
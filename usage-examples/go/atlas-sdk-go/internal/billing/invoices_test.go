@@ -227,3 +227,76 @@ func TestListInvoicesForOrg_WithCombinedOptions(t *testing.T) {
 	require.Equal(t, mockResponse, result)
 	require.Len(t, *result.Results, 5, "Should return all five invoices from the mock response")
 }
+
+func TestListAllInvoicesForOrg_MultiplePages(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	orgID := "org123"
+
+	page1 := &admin.PaginatedApiInvoiceMetadata{
+		Results: &[]admin.BillingInvoiceMetadata{
+			{Id: admin.PtrString("inv_1")},
+			{Id: admin.PtrString("inv_2")},
+		},
+		TotalCount: admin.PtrInt(3),
+	}
+	page2 := &admin.PaginatedApiInvoiceMetadata{
+		Results: &[]admin.BillingInvoiceMetadata{
+			{Id: admin.PtrString("inv_3")},
+		},
+		TotalCount: admin.PtrInt(3),
+	}
+
+	mockSvc := mockadmin.NewInvoicesApi(t)
+	mockSvc.EXPECT().
+		ListInvoices(mock.Anything, orgID).
+		Return(admin.ListInvoicesApiRequest{ApiService: mockSvc}).Times(2)
+	mockSvc.EXPECT().
+		ListInvoicesExecute(mock.Anything).
+		Return(page1, nil, nil).Once()
+	mockSvc.EXPECT().
+		ListInvoicesExecute(mock.Anything).
+		Return(page2, nil, nil).Once()
+
+	params := &admin.ListInvoicesApiParams{OrgId: orgID}
+	result, err := ListAllInvoicesForOrg(ctx, mockSvc, params, WithItemsPerPage(2))
+
+	require.NoError(t, err)
+	require.Len(t, result, 3, "Should return invoices from every page")
+	assert.Equal(t, "inv_3", result[2].GetId())
+}
+
+func TestListAllInvoicesForOrg_ApiError(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	orgID := "org123"
+
+	mockSvc := mockadmin.NewInvoicesApi(t)
+	mockSvc.EXPECT().
+		ListInvoices(mock.Anything, orgID).
+		Return(admin.ListInvoicesApiRequest{ApiService: mockSvc}).Once()
+	mockSvc.EXPECT().
+		ListInvoicesExecute(mock.Anything).
+		Return(nil, nil, assert.AnError).Once()
+
+	params := &admin.ListInvoicesApiParams{OrgId: orgID}
+	result, err := ListAllInvoicesForOrg(ctx, mockSvc, params)
+
+	require.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "list invoices")
+}
+
+func TestIterateInvoicesForOrg_StopsOnCancelledContext(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// No expectations: a cancelled context must not reach the API
+	mockSvc := mockadmin.NewInvoicesApi(t)
+	params := &admin.ListInvoicesApiParams{OrgId: "org123"}
+
+	for _, err := range IterateInvoicesForOrg(ctx, mockSvc, params) {
+		require.ErrorIs(t, err, context.Canceled)
+	}
+}
@@ -46,3 +46,47 @@ func GetCrossOrgBilling(ctx context.Context, sdk admin.InvoicesApi, p *admin.Lis
 
 	return crossOrgBilling, nil
 }
+
+// CollectLinkedOrgLineItems fetches the paying organization's invoices that match opts, including
+// the invoices of linked organizations, and transforms every line item into a billing Detail
+// attributed to the organization that incurred it. Organization names are resolved through names.
+// Linked invoices are returned by the paying organization's invoice, so no access to the linked
+// organizations' invoices is required.
+// Invoices are fetched with at most `workers` requests in flight (DefaultInvoiceWorkers if workers <= 0).
+// SKUs are classified with classifier, or the embedded rules if classifier is nil.
+//
+// Required Permissions:
+//   - Organization Billing Admin or Organization Owner role can view invoices and linked invoices for the organization.
+func CollectLinkedOrgLineItems(ctx context.Context, sdk admin.InvoicesApi, names *OrgNameCache, payingOrgID string, workers int, classifier *SKUClassifier, opts ...InvoiceOption) ([]Detail, error) {
+	opts = append(opts, WithViewLinkedInvoices(true))
+	invoices, err := GetInvoicesForOrg(ctx, sdk, payingOrgID, workers, opts...)
+	if err != nil {
+		return nil, errors.FormatError("collect linked organization line items", payingOrgID, err)
+	}
+
+	var billingDetails []Detail
+	add := func(inv admin.BillingInvoice, orgID string) error {
+		details, err := processInvoices([]admin.BillingInvoice{inv}, orgID, names.Name(ctx, orgID), nil, classifier)
+		if err != nil {
+			return errors.WithContext(err, "processing invoice "+inv.GetId())
+		}
+		billingDetails = append(billingDetails, details...)
+		return nil
+	}
+
+	for _, invoice := range invoices {
+		if err := add(invoice, payingOrgID); err != nil {
+			return nil, err
+		}
+		for _, linked := range invoice.GetLinkedInvoices() {
+			orgID := linked.GetOrgId()
+			if orgID == "" {
+				continue
+			}
+			if err := add(linked, orgID); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return billingDetails, nil
+}
@@ -3,6 +3,11 @@ package billing_test
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
 
 	"atlas-sdk-go/internal/billing"
@@ -142,3 +147,49 @@ func TestGetCrossOrgBilling_MissingOrgID(t *testing.T) {
 	assert.Len(t, results[linkedOrgID], 1, "Should return one invoice for linkedOrgID")
 	assert.Equal(t, int64(1000), *results[linkedOrgID][0].AmountBilledCents, "Invoice for linkedOrgID should have 1000 cents billed")
 }
+
+func TestCollectLinkedOrgLineItems_AttributesLineItemsToEachOrg(t *testing.T) {
+	t.Parallel()
+	payingOrgID := "paying1"
+	var orgLookups atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/atlas/v2/orgs/paying1/invoices":
+			assert.Equal(t, "true", r.URL.Query().Get("viewLinkedInvoices"))
+			_, _ = w.Write([]byte(`{"results": [{"id": "inv1"}], "totalCount": 1}`))
+		case "/api/atlas/v2/orgs/paying1/invoices/inv1":
+			_, _ = w.Write([]byte(`{"id": "inv1", "orgId": "paying1",
+				"lineItems": [{"sku": "NDS_AWS_INSTANCE_M10", "totalPriceCents": 1000, "startDate": "2024-05-01T00:00:00Z"}],
+				"linkedInvoices": [
+					{"id": "inv2", "orgId": "child1", "lineItems": [
+						{"sku": "NDS_AWS_INSTANCE_M20", "totalPriceCents": 3000, "startDate": "2024-05-01T00:00:00Z"},
+						{"sku": "NDS_AWS_STORAGE_STANDARD", "totalPriceCents": 500, "startDate": "2024-05-02T00:00:00Z"}]},
+					{"id": "inv3", "orgId": "child2", "lineItems": [
+						{"sku": "NDS_AWS_INSTANCE_M10", "totalPriceCents": 700, "startDate": "2024-05-01T00:00:00Z"}]}
+				]}`))
+		case "/api/atlas/v2/orgs/paying1", "/api/atlas/v2/orgs/child1", "/api/atlas/v2/orgs/child2":
+			orgLookups.Add(1)
+			id := strings.TrimPrefix(r.URL.Path, "/api/atlas/v2/orgs/")
+			_, _ = fmt.Fprintf(w, `{"id": %q, "name": "Name of %s"}`, id, id)
+		default:
+			t.Errorf("unexpected request: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	client, err := admin.NewClient(admin.UseBaseURL(server.URL))
+	require.NoError(t, err)
+
+	names := billing.NewOrgNameCache(client.OrganizationsApi)
+	details, err := billing.CollectLinkedOrgLineItems(context.Background(), client.InvoicesApi, names, payingOrgID, 2, nil)
+
+	require.NoError(t, err)
+	require.Len(t, details, 4)
+	costs := map[string]float64{}
+	for _, d := range details {
+		costs[d.Org.Name] += d.Cost
+	}
+	assert.Equal(t, map[string]float64{"Name of paying1": 10, "Name of child1": 35, "Name of child2": 7}, costs)
+	assert.Equal(t, int32(3), orgLookups.Load(), "each organization name should be looked up once")
+}
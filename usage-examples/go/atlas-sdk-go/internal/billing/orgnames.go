@@ -0,0 +1,79 @@
+package billing
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"go.mongodb.org/atlas-sdk/v20250219001/admin"
+)
+
+// OrgNameCache resolves organization IDs to names through the Organizations API, caching each
+// name once it is found. It is safe for concurrent use; concurrent lookups of the same
+// organization share one request.
+type OrgNameCache struct {
+	sdk      admin.OrganizationsApi
+	mu       sync.Mutex
+	names    map[string]string
+	inflight map[string]*orgNameLookup
+}
+
+// orgNameLookup is a name request in progress. name is set before done is closed.
+type orgNameLookup struct {
+	done chan struct{}
+	name string
+}
+
+// NewOrgNameCache returns an empty cache that looks up names with sdk.
+func NewOrgNameCache(sdk admin.OrganizationsApi) *OrgNameCache {
+	return &OrgNameCache{sdk: sdk, names: make(map[string]string), inflight: make(map[string]*orgNameLookup)}
+}
+
+// Name returns the organization's name. If the lookup fails, a warning is printed and the
+// organization ID is returned in its place, so a missing permission on one linked organization
+// does not stop a report. Failures are not cached, so a later call tries again.
+//
+// Required Permissions:
+//   - Organization Member role can view the organization
+func (c *OrgNameCache) Name(ctx context.Context, orgID string) string {
+	c.mu.Lock()
+	if name, ok := c.names[orgID]; ok {
+		c.mu.Unlock()
+		return name
+	}
+	if l, ok := c.inflight[orgID]; ok {
+		c.mu.Unlock()
+		select {
+		case <-l.done:
+			return l.name
+		case <-ctx.Done():
+			return orgID
+		}
+	}
+	l := &orgNameLookup{done: make(chan struct{}), name: orgID}
+	c.inflight[orgID] = l
+	c.mu.Unlock()
+
+	// The lock is not held during the request, so lookups of other organizations are not blocked
+	name, err := getOrganizationName(ctx, c.sdk, orgID)
+	if err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+
+	c.mu.Lock()
+	if err == nil && name != "" {
+		l.name = name
+		c.names[orgID] = name
+	}
+	delete(c.inflight, orgID)
+	c.mu.Unlock()
+	close(l.done)
+	return l.name
+}
+
+// Set records a known name, avoiding a lookup.
+func (c *OrgNameCache) Set(orgID, name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.names[orgID] = name
+}
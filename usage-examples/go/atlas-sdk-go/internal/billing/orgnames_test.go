@@ -0,0 +1,82 @@
+package billing
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/atlas-sdk/v20250219001/admin"
+	"go.mongodb.org/atlas-sdk/v20250219001/mockadmin"
+)
+
+func TestOrgNameCache_LooksUpEachOrgOnce(t *testing.T) {
+	t.Parallel()
+	mockOrgSvc := mockadmin.NewOrganizationsApi(t)
+	mockOrgSvc.EXPECT().
+		GetOrganization(mock.Anything, "org1").
+		Return(admin.GetOrganizationApiRequest{ApiService: mockOrgSvc}).Once()
+	mockOrgSvc.EXPECT().
+		GetOrganizationExecute(mock.Anything).
+		Return(&admin.AtlasOrganization{Name: "Org One"}, nil, nil).Once()
+
+	cache := NewOrgNameCache(mockOrgSvc)
+	ctx := context.Background()
+
+	assert.Equal(t, "Org One", cache.Name(ctx, "org1"))
+	assert.Equal(t, "Org One", cache.Name(ctx, "org1"), "second lookup should be served from the cache")
+
+	cache.Set("org2", "Org Two")
+	assert.Equal(t, "Org Two", cache.Name(ctx, "org2"))
+}
+
+func TestOrgNameCache_FallsBackToID(t *testing.T) {
+	t.Parallel()
+	mockOrgSvc := mockadmin.NewOrganizationsApi(t)
+	mockOrgSvc.EXPECT().
+		GetOrganization(mock.Anything, "org1").
+		Return(admin.GetOrganizationApiRequest{ApiService: mockOrgSvc}).Twice()
+	mockOrgSvc.EXPECT().
+		GetOrganizationExecute(mock.Anything).
+		Return(nil, nil, errors.New("forbidden")).Once()
+	mockOrgSvc.EXPECT().
+		GetOrganizationExecute(mock.Anything).
+		Return(&admin.AtlasOrganization{Name: "Org One"}, nil, nil).Once()
+
+	cache := NewOrgNameCache(mockOrgSvc)
+
+	assert.Equal(t, "org1", cache.Name(context.Background(), "org1"))
+	assert.Equal(t, "Org One", cache.Name(context.Background(), "org1"), "failures are not cached")
+}
+
+func TestOrgNameCache_SharesConcurrentLookups(t *testing.T) {
+	t.Parallel()
+	release := make(chan struct{})
+	mockOrgSvc := mockadmin.NewOrganizationsApi(t)
+	mockOrgSvc.EXPECT().
+		GetOrganization(mock.Anything, "org1").
+		Return(admin.GetOrganizationApiRequest{ApiService: mockOrgSvc}).Once()
+	mockOrgSvc.EXPECT().
+		GetOrganizationExecute(mock.Anything).
+		Run(func(admin.GetOrganizationApiRequest) { <-release }).
+		Return(&admin.AtlasOrganization{Name: "Org One"}, nil, nil).Once()
+
+	cache := NewOrgNameCache(mockOrgSvc)
+	names := make([]string, 4)
+	var wg sync.WaitGroup
+	for i := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			names[i] = cache.Name(context.Background(), "org1")
+		}()
+	}
+	cache.Set("org2", "Org Two")
+	assert.Equal(t, "Org Two", cache.Name(context.Background(), "org2"), "other organizations are not blocked by a lookup")
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, []string{"Org One", "Org One", "Org One", "Org One"}, names)
+}
@@ -0,0 +1,121 @@
+package pricing
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"atlas-sdk-go/internal/billing"
+	"atlas-sdk-go/internal/errors"
+)
+
+// Price sources recorded on each Price
+const (
+	SourceFile    = "file"
+	SourceInvoice = "invoice"
+)
+
+// instanceCategory is the billing category of cluster instance charges.
+const instanceCategory = "Clusters"
+
+// Price is the hourly cost of one node of an instance size.
+type Price struct {
+	Provider string    `json:"provider"`         // e.g. "AWS", "AZURE", "GCP"
+	Tier     string    `json:"tier"`             // Instance size, e.g. "M30" or "R40"
+	Region   string    `json:"region,omitempty"` // Atlas region name, e.g. "US_EAST_1"; empty applies to any region
+	Hourly   float64   `json:"hourly"`           // US dollars per node per hour
+	Source   string    `json:"source,omitempty"`
+	AsOf     time.Time `json:"asOf,omitempty"` // Date of the line item the price was learned from
+}
+
+// Catalog holds node prices by provider, tier, and region.
+type Catalog struct {
+	Version string  `json:"version,omitempty"`
+	Prices  []Price `json:"prices"`
+}
+
+// LoadCatalog reads a price catalog from a JSON file.
+func LoadCatalog(path string) (*Catalog, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, &errors.NotFoundError{Resource: "price catalog file", ID: path}
+		}
+		return nil, errors.WithContext(err, "reading price catalog file")
+	}
+	var c Catalog
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, errors.WithContext(err, "parsing price catalog file")
+	}
+	for i := range c.Prices {
+		p := &c.Prices[i]
+		if p.Provider == "" || p.Tier == "" || p.Hourly < 0 {
+			return nil, &errors.ValidationError{Message: fmt.Sprintf("price catalog entry %d needs a provider, tier, and non-negative hourly price", i)}
+		}
+		if p.Source == "" {
+			p.Source = SourceFile
+		}
+	}
+	return &c, nil
+}
+
+// Learn adds prices observed on hourly Clusters line items, keeping the most recent unit price
+// for each provider, tier, and region. Learned prices replace catalog entries for the same key,
+// since invoices reflect any negotiated rates. It returns the number of prices learned.
+func (c *Catalog) Learn(details []billing.Detail) int {
+	learned := make(map[string]Price)
+	for _, d := range details {
+		if d.Category != instanceCategory || d.UnitPrice <= 0 || !strings.Contains(strings.ToLower(d.Unit), "hour") {
+			continue
+		}
+		if d.Provider == "" || d.Instance == "" || d.Instance == "non-instance" {
+			continue
+		}
+		p := Price{Provider: d.Provider, Tier: d.Instance, Region: d.Region, Hourly: d.UnitPrice, Source: SourceInvoice, AsOf: d.Date}
+		k := key(p.Provider, p.Tier, p.Region)
+		if prev, ok := learned[k]; ok && prev.AsOf.After(p.AsOf) {
+			continue
+		}
+		learned[k] = p
+	}
+
+	for _, p := range learned {
+		k := key(p.Provider, p.Tier, p.Region)
+		i := slices.IndexFunc(c.Prices, func(e Price) bool { return key(e.Provider, e.Tier, e.Region) == k })
+		if i >= 0 {
+			c.Prices[i] = p
+		} else {
+			c.Prices = append(c.Prices, p)
+		}
+	}
+	return len(learned)
+}
+
+// Lookup returns the hourly price of one node. A price for the exact region is preferred over
+// a price that applies to any region.
+func (c *Catalog) Lookup(provider, tier, region string) (Price, bool) {
+	var fallback *Price
+	for i, p := range c.Prices {
+		if !strings.EqualFold(p.Provider, provider) || !strings.EqualFold(p.Tier, tier) {
+			continue
+		}
+		if strings.EqualFold(p.Region, region) {
+			return p, true
+		}
+		if p.Region == "" && fallback == nil {
+			fallback = &c.Prices[i]
+		}
+	}
+	if fallback != nil {
+		return *fallback, true
+	}
+	return Price{}, false
+}
+
+// key identifies a price by provider, tier, and region.
+func key(provider, tier, region string) string {
+	return strings.ToUpper(strings.Join([]string{provider, tier, region}, "\x00"))
+}
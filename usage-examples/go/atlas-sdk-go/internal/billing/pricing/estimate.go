@@ -0,0 +1,121 @@
+package pricing
+
+import (
+	"fmt"
+
+	"atlas-sdk-go/internal/billing/uniteconomics"
+	"atlas-sdk-go/internal/errors"
+
+	"go.mongodb.org/atlas-sdk/v20250219001/admin"
+)
+
+// Node roles priced by EstimateScaling
+const (
+	RoleElectable = "electable"
+	RoleReadOnly  = "read-only"
+	RoleAnalytics = "analytics"
+)
+
+// NodeGroupCost is the cost of the nodes of one role in one region before and after scaling.
+type NodeGroupCost struct {
+	Role          string  `json:"role"`
+	Provider      string  `json:"provider"`
+	Region        string  `json:"region"`
+	Nodes         int     `json:"nodes"`
+	CurrentTier   string  `json:"currentTier"`
+	TargetTier    string  `json:"targetTier"`
+	CurrentHourly float64 `json:"currentHourly"` // All nodes in the group
+	TargetHourly  float64 `json:"targetHourly"`
+}
+
+// Estimate is the projected cost change of scaling a cluster to a target tier.
+type Estimate struct {
+	Cluster       string          `json:"cluster"`
+	TargetTier    string          `json:"targetTier"`
+	Groups        []NodeGroupCost `json:"groups"`
+	CurrentHourly float64         `json:"currentHourly"`
+	TargetHourly  float64         `json:"targetHourly"`
+	HourlyDelta   float64         `json:"hourlyDelta"`
+	MonthlyDelta  float64         `json:"monthlyDelta"` // HourlyDelta over an average month
+}
+
+// EstimateScaling prices every electable, read-only, and analytics node of cluster at its current
+// instance size and at targetTier, matching how scaling applies the target tier to all node types.
+// It returns a NotFoundError naming the first provider, tier, and region missing from the catalog.
+func EstimateScaling(cluster *admin.ClusterDescription20240805, targetTier string, c *Catalog) (*Estimate, error) {
+	if cluster == nil || !cluster.HasReplicationSpecs() {
+		return nil, &errors.ValidationError{Message: "cluster has no replication specs"}
+	}
+	if targetTier == "" {
+		return nil, &errors.ValidationError{Message: "target tier is required"}
+	}
+	if c == nil {
+		return nil, &errors.ValidationError{Message: "price catalog is required"}
+	}
+
+	e := &Estimate{Cluster: cluster.GetName(), TargetTier: targetTier}
+	for _, spec := range cluster.GetReplicationSpecs() {
+		for _, rc := range spec.GetRegionConfigs() {
+			provider := rc.GetProviderName()
+			if provider == "TENANT" {
+				provider = rc.GetBackingProviderName()
+			}
+			groups := []struct {
+				role string
+				tier string
+				n    int
+			}{
+				{RoleElectable, rc.ElectableSpecs.GetInstanceSize(), rc.ElectableSpecs.GetNodeCount()},
+				{RoleReadOnly, rc.ReadOnlySpecs.GetInstanceSize(), rc.ReadOnlySpecs.GetNodeCount()},
+				{RoleAnalytics, rc.AnalyticsSpecs.GetInstanceSize(), rc.AnalyticsSpecs.GetNodeCount()},
+			}
+			for _, g := range groups {
+				if g.n <= 0 || g.tier == "" {
+					continue
+				}
+				cur, err := lookup(c, provider, g.tier, rc.GetRegionName())
+				if err != nil {
+					return nil, err
+				}
+				tgt, err := lookup(c, provider, targetTier, rc.GetRegionName())
+				if err != nil {
+					return nil, err
+				}
+				gc := NodeGroupCost{
+					Role:          g.role,
+					Provider:      provider,
+					Region:        rc.GetRegionName(),
+					Nodes:         g.n,
+					CurrentTier:   g.tier,
+					TargetTier:    targetTier,
+					CurrentHourly: cur.Hourly * float64(g.n),
+					TargetHourly:  tgt.Hourly * float64(g.n),
+				}
+				e.Groups = append(e.Groups, gc)
+				e.CurrentHourly += gc.CurrentHourly
+				e.TargetHourly += gc.TargetHourly
+			}
+		}
+	}
+	if len(e.Groups) == 0 {
+		return nil, &errors.ValidationError{Message: fmt.Sprintf("cluster %s has no nodes to price", e.Cluster)}
+	}
+	e.HourlyDelta = e.TargetHourly - e.CurrentHourly
+	e.MonthlyDelta = e.HourlyDelta * uniteconomics.HoursPerMonth
+	return e, nil
+}
+
+// ExceedsCeiling reports whether the monthly cost increase is above ceiling. A ceiling of zero
+// or less means no limit.
+func (e *Estimate) ExceedsCeiling(ceiling float64) bool {
+	return ceiling > 0 && e.MonthlyDelta > ceiling
+}
+
+// lookup returns the catalog price for one node or a NotFoundError.
+func lookup(c *Catalog, provider, tier, region string) (Price, error) {
+	p, ok := c.Lookup(provider, tier, region)
+	if !ok {
+		return Price{}, &errors.NotFoundError{Resource: "price", ID: fmt.Sprintf("%s %s %s", provider, tier, region)}
+	}
+	return p, nil
+}
@@ -0,0 +1,125 @@
+package pricing
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"atlas-sdk-go/internal/billing"
+	internalerrors "atlas-sdk-go/internal/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/atlas-sdk/v20250219001/admin"
+)
+
+func testCatalog() *Catalog {
+	return &Catalog{Prices: []Price{
+		{Provider: "AWS", Tier: "M30", Hourly: 0.54},
+		{Provider: "AWS", Tier: "M50", Hourly: 2.00},
+		{Provider: "AWS", Tier: "M50", Region: "EU_WEST_1", Hourly: 2.20},
+	}}
+}
+
+func regionConfig(region, tier string, electable, readOnly, analytics int) admin.CloudRegionConfig20240805 {
+	rc := admin.CloudRegionConfig20240805{
+		ProviderName:   admin.PtrString("AWS"),
+		RegionName:     admin.PtrString(region),
+		ElectableSpecs: &admin.HardwareSpec20240805{InstanceSize: admin.PtrString(tier), NodeCount: admin.PtrInt(electable)},
+	}
+	if readOnly > 0 {
+		rc.ReadOnlySpecs = &admin.DedicatedHardwareSpec20240805{InstanceSize: admin.PtrString(tier), NodeCount: admin.PtrInt(readOnly)}
+	}
+	if analytics > 0 {
+		rc.AnalyticsSpecs = &admin.DedicatedHardwareSpec20240805{InstanceSize: admin.PtrString(tier), NodeCount: admin.PtrInt(analytics)}
+	}
+	return rc
+}
+
+func TestEstimateScaling_PricesAllNodeTypes(t *testing.T) {
+	t.Parallel()
+	cluster := &admin.ClusterDescription20240805{
+		Name: admin.PtrString("Cluster0"),
+		ReplicationSpecs: &[]admin.ReplicationSpec20240805{{RegionConfigs: &[]admin.CloudRegionConfig20240805{
+			regionConfig("US_EAST_1", "M30", 3, 1, 0),
+			regionConfig("EU_WEST_1", "M30", 0, 0, 1),
+		}}},
+	}
+
+	e, err := EstimateScaling(cluster, "M50", testCatalog())
+
+	require.NoError(t, err)
+	require.Len(t, e.Groups, 3)
+	assert.Equal(t, RoleReadOnly, e.Groups[1].Role)
+	assert.InDelta(t, 0.54*5, e.CurrentHourly, 0.0001)
+	assert.InDelta(t, 2.00*4+2.20, e.TargetHourly, 0.0001, "region-specific price should win over the default")
+	assert.InDelta(t, e.TargetHourly-e.CurrentHourly, e.HourlyDelta, 0.0001)
+	assert.InDelta(t, e.HourlyDelta*730, e.MonthlyDelta, 0.0001)
+	assert.True(t, e.ExceedsCeiling(1000))
+	assert.False(t, e.ExceedsCeiling(10000))
+	assert.False(t, e.ExceedsCeiling(0), "zero ceiling means no limit")
+}
+
+func TestEstimateScaling_MissingPrice(t *testing.T) {
+	t.Parallel()
+	cluster := &admin.ClusterDescription20240805{
+		ReplicationSpecs: &[]admin.ReplicationSpec20240805{{RegionConfigs: &[]admin.CloudRegionConfig20240805{
+			regionConfig("US_EAST_1", "M30", 3, 0, 0),
+		}}},
+	}
+
+	_, err := EstimateScaling(cluster, "M80", testCatalog())
+
+	var nfErr *internalerrors.NotFoundError
+	require.ErrorAs(t, err, &nfErr)
+	assert.Equal(t, "AWS M80 US_EAST_1", nfErr.ID)
+
+	_, err = EstimateScaling(&admin.ClusterDescription20240805{}, "M50", testCatalog())
+	var vErr *internalerrors.ValidationError
+	assert.ErrorAs(t, err, &vErr)
+}
+
+func TestCatalog_Learn(t *testing.T) {
+	t.Parallel()
+	c := testCatalog()
+	may := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	details := []billing.Detail{
+		{Category: "Clusters", Provider: "AWS", Instance: "M30", Region: "US_EAST_1", Unit: "server hours", UnitPrice: 0.50, Date: may},
+		{Category: "Clusters", Provider: "AWS", Instance: "M30", Region: "US_EAST_1", Unit: "server hours", UnitPrice: 0.52, Date: may.AddDate(0, 0, 1)},
+		{Category: "Clusters", Provider: "AWS", Instance: "M50", Unit: "server hours", UnitPrice: 1.90, Date: may},
+		{Category: "Storage", Provider: "AWS", Instance: "non-instance", Unit: "GB days", UnitPrice: 0.01, Date: may},
+	}
+
+	n := c.Learn(details)
+
+	assert.Equal(t, 2, n)
+	p, ok := c.Lookup("aws", "m30", "US_EAST_1")
+	require.True(t, ok)
+	assert.InDelta(t, 0.52, p.Hourly, 0.0001, "most recent unit price should be kept")
+	assert.Equal(t, SourceInvoice, p.Source)
+	p, _ = c.Lookup("AWS", "M50", "US_WEST_2")
+	assert.InDelta(t, 1.90, p.Hourly, 0.0001, "learned price should replace the file entry")
+	assert.Len(t, c.Prices, 4)
+}
+
+func TestLoadCatalog(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "prices.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"version": "2025-01", "prices": [{"provider": "AWS", "tier": "M10", "hourly": 0.08}]}`), 0o600))
+
+	c, err := LoadCatalog(path)
+	require.NoError(t, err)
+	require.Len(t, c.Prices, 1)
+	assert.Equal(t, SourceFile, c.Prices[0].Source)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"prices": [{"tier": "M10", "hourly": 0.08}]}`), 0o600))
+	_, err = LoadCatalog(path)
+	var vErr *internalerrors.ValidationError
+	assert.ErrorAs(t, err, &vErr)
+
+	_, err = LoadCatalog(filepath.Join(dir, "missing.json"))
+	var nfErr *internalerrors.NotFoundError
+	assert.ErrorAs(t, err, &nfErr)
+}
@@ -0,0 +1,164 @@
+package reconcile
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"atlas-sdk-go/internal/billing"
+
+	"go.mongodb.org/atlas-sdk/v20250219001/admin"
+)
+
+// DefaultToleranceCents is the difference, in cents, allowed between the reconciled total and
+// AmountBilledCents before an invoice is flagged, to absorb per-line rounding.
+const DefaultToleranceCents = 1
+
+// Options controls how invoices are reconciled.
+type Options struct {
+	ToleranceCents int64                  // Allowed difference before flagging a mismatch (DefaultToleranceCents if 0; negative for exact)
+	Classifier     *billing.SKUClassifier // Identifies credit line items by SKU category (the embedded SKU rules if nil)
+}
+
+// Invoice is the reconciliation of a single invoice. All amounts are in US dollars.
+type Invoice struct {
+	InvoiceID      string    `json:"invoiceId"`
+	OrgID          string    `json:"orgId"`
+	Status         string    `json:"status"`
+	StartDate      time.Time `json:"startDate"`
+	EndDate        time.Time `json:"endDate"`
+	GrossCharges   float64   `json:"grossCharges"`   // Usage line items, net of line-item discounts
+	Discounts      float64   `json:"discounts"`      // Line-item discounts already deducted from GrossCharges
+	Credits        float64   `json:"credits"`        // Credit and coupon line items (negative)
+	MinimumCharges float64   `json:"minimumCharges"` // Minimum-charge adjustments
+	Taxes          float64   `json:"taxes"`          // Sales tax
+	Reconciled     float64   `json:"reconciled"`     // GrossCharges + Credits + MinimumCharges + Taxes
+	AmountBilled   float64   `json:"amountBilled"`   // Invoice AmountBilledCents
+	Difference     float64   `json:"difference"`     // AmountBilled - Reconciled
+	Matched        bool      `json:"matched"`        // Difference is within tolerance
+	Warnings       []string  `json:"warnings,omitempty"`
+}
+
+// Report is the reconciliation of a set of invoices, with totals across all of them.
+type Report struct {
+	Invoices       []Invoice `json:"invoices"`
+	GrossCharges   float64   `json:"grossCharges"`
+	Discounts      float64   `json:"discounts"`
+	Credits        float64   `json:"credits"`
+	MinimumCharges float64   `json:"minimumCharges"`
+	Taxes          float64   `json:"taxes"`
+	Reconciled     float64   `json:"reconciled"`
+	AmountBilled   float64   `json:"amountBilled"`
+	Difference     float64   `json:"difference"`
+	Mismatches     int       `json:"mismatches"` // Number of invoices that did not reconcile
+}
+
+// Reconcile separates each invoice's line items into gross charges, credits, and minimum-charge
+// adjustments, adds sales tax, and compares the result with the invoice's AmountBilledCents.
+//
+// Credits are line items classified as "Credits" by the billing SKU classifier (e.g. CREDIT and
+// CLASSIC_COUPON); MINIMUM_CHARGE line items are reported separately. An invoice whose
+// reconciled total differs from the amount billed by more than the tolerance is flagged as a
+// mismatch. Disagreements with the invoice's SubtotalCents and CreditsCents are reported as
+// warnings, since those fields are summaries rather than the amount owed.
+func Reconcile(invoices []admin.BillingInvoice, opts Options) *Report {
+	tolerance := opts.ToleranceCents
+	if tolerance == 0 {
+		tolerance = DefaultToleranceCents
+	}
+	tolerance = max(tolerance, 0)
+	classifier := opts.Classifier
+	if classifier == nil {
+		classifier = billing.DefaultSKUClassifier()
+	}
+
+	report := &Report{Invoices: make([]Invoice, 0, len(invoices))}
+	for _, inv := range invoices {
+		r := reconcileInvoice(inv, tolerance, classifier)
+		report.Invoices = append(report.Invoices, r)
+		report.GrossCharges += r.GrossCharges
+		report.Discounts += r.Discounts
+		report.Credits += r.Credits
+		report.MinimumCharges += r.MinimumCharges
+		report.Taxes += r.Taxes
+		report.Reconciled += r.Reconciled
+		report.AmountBilled += r.AmountBilled
+		report.Difference += r.Difference
+		if !r.Matched {
+			report.Mismatches++
+		}
+	}
+	return report
+}
+
+// reconcileInvoice reconciles a single invoice, working in cents to avoid rounding drift.
+func reconcileInvoice(inv admin.BillingInvoice, tolerance int64, classifier *billing.SKUClassifier) Invoice {
+	var gross, discounts, credits, minimum, positive int64
+	for _, li := range inv.GetLineItems() {
+		cents := li.GetTotalPriceCents()
+		if cents > 0 {
+			positive += cents
+		}
+		switch {
+		case isMinimumCharge(li.GetSku()):
+			minimum += cents
+		case classifier.Classify(li.GetSku()).Category == "Credits":
+			credits += cents
+		default:
+			gross += cents
+			discounts += li.GetDiscountCents()
+		}
+	}
+	tax := inv.GetSalesTaxCents()
+	reconciled := gross + credits + minimum + tax
+	billed := inv.GetAmountBilledCents()
+	diff := billed - reconciled
+
+	r := Invoice{
+		InvoiceID:      inv.GetId(),
+		OrgID:          inv.GetOrgId(),
+		Status:         inv.GetStatusName(),
+		StartDate:      inv.GetStartDate(),
+		EndDate:        inv.GetEndDate(),
+		GrossCharges:   dollars(gross),
+		Discounts:      dollars(discounts),
+		Credits:        dollars(credits),
+		MinimumCharges: dollars(minimum),
+		Taxes:          dollars(tax),
+		Reconciled:     dollars(reconciled),
+		AmountBilled:   dollars(billed),
+		Difference:     dollars(diff),
+		Matched:        abs(diff) <= tolerance,
+	}
+
+	if inv.GetStatusName() == "PENDING" {
+		r.Warnings = append(r.Warnings, "invoice is pending; amounts may still change")
+	}
+	if inv.HasSubtotalCents() && abs(inv.GetSubtotalCents()-positive) > tolerance {
+		r.Warnings = append(r.Warnings, fmt.Sprintf("positive line items total %.2f but invoice subtotal is %.2f",
+			dollars(positive), dollars(inv.GetSubtotalCents())))
+	}
+	if inv.HasCreditsCents() && abs(inv.GetCreditsCents()-abs(credits)) > tolerance {
+		r.Warnings = append(r.Warnings, fmt.Sprintf("credit line items total %.2f but invoice credits are %.2f",
+			dollars(abs(credits)), dollars(inv.GetCreditsCents())))
+	}
+	return r
+}
+
+// isMinimumCharge reports whether a SKU is a minimum-charge adjustment.
+func isMinimumCharge(sku string) bool {
+	return strings.Contains(strings.ToUpper(sku), "MINIMUM_CHARGE")
+}
+
+// dollars converts cents to US dollars.
+func dollars(cents int64) float64 {
+	return float64(cents) / 100.0
+}
+
+// abs returns the absolute value of v.
+func abs(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,90 @@
+package reconcile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/atlas-sdk/v20250219001/admin"
+)
+
+func lineItem(sku string, cents int64) admin.InvoiceLineItem {
+	return admin.InvoiceLineItem{Sku: admin.PtrString(sku), TotalPriceCents: admin.PtrInt64(cents)}
+}
+
+func invoice(id string, billed, tax int64, items ...admin.InvoiceLineItem) admin.BillingInvoice {
+	return admin.BillingInvoice{
+		Id:                admin.PtrString(id),
+		OrgId:             admin.PtrString("org1"),
+		StatusName:        admin.PtrString("CLOSED"),
+		AmountBilledCents: admin.PtrInt64(billed),
+		SalesTaxCents:     admin.PtrInt64(tax),
+		LineItems:         &items,
+	}
+}
+
+func TestReconcile_SeparatesChargesCreditsMinimumsAndTax(t *testing.T) {
+	t.Parallel()
+	discounted := lineItem("NDS_AWS_INSTANCE_M30", 9000)
+	discounted.DiscountCents = admin.PtrInt64(1000)
+	inv := invoice("inv1", 10150, 650,
+		discounted,
+		lineItem("NDS_AWS_STORAGE_STANDARD", 500),
+		lineItem("CREDIT", -2000),
+		lineItem("CLASSIC_COUPON", -500),
+		lineItem("MINIMUM_CHARGE", 2500),
+	)
+	inv.SubtotalCents = admin.PtrInt64(12000)
+	inv.CreditsCents = admin.PtrInt64(2500)
+
+	report := Reconcile([]admin.BillingInvoice{inv}, Options{})
+
+	require.Len(t, report.Invoices, 1)
+	r := report.Invoices[0]
+	assert.InDelta(t, 95.00, r.GrossCharges, 0.001)
+	assert.InDelta(t, 10.00, r.Discounts, 0.001)
+	assert.InDelta(t, -25.00, r.Credits, 0.001)
+	assert.InDelta(t, 25.00, r.MinimumCharges, 0.001)
+	assert.InDelta(t, 6.50, r.Taxes, 0.001)
+	assert.InDelta(t, 101.50, r.Reconciled, 0.001)
+	assert.InDelta(t, 0.0, r.Difference, 0.001)
+	assert.True(t, r.Matched)
+	assert.Empty(t, r.Warnings)
+	assert.Zero(t, report.Mismatches)
+}
+
+func TestReconcile_FlagsMismatches(t *testing.T) {
+	t.Parallel()
+	ok := invoice("inv1", 1001, 0, lineItem("NDS_AWS_INSTANCE_M10", 1000))
+	off := invoice("inv2", 1500, 100, lineItem("NDS_AWS_INSTANCE_M10", 1000))
+	off.SubtotalCents = admin.PtrInt64(1200)
+
+	report := Reconcile([]admin.BillingInvoice{ok, off}, Options{})
+
+	assert.True(t, report.Invoices[0].Matched, "a one-cent difference is within the default tolerance")
+	assert.False(t, report.Invoices[1].Matched)
+	assert.InDelta(t, 4.00, report.Invoices[1].Difference, 0.001)
+	require.Len(t, report.Invoices[1].Warnings, 1)
+	assert.Contains(t, report.Invoices[1].Warnings[0], "subtotal")
+	assert.Equal(t, 1, report.Mismatches)
+	assert.InDelta(t, 25.01, report.AmountBilled, 0.001)
+
+	exact := Reconcile([]admin.BillingInvoice{ok}, Options{ToleranceCents: -1})
+	assert.False(t, exact.Invoices[0].Matched, "a negative tolerance requires an exact match")
+}
+
+func TestReport_Table(t *testing.T) {
+	t.Parallel()
+	report := Reconcile([]admin.BillingInvoice{
+		invoice("inv1", 1000, 0, lineItem("NDS_AWS_INSTANCE_M10", 1000)),
+		invoice("inv2", 900, 0, lineItem("NDS_AWS_INSTANCE_M10", 1000)),
+	}, Options{})
+
+	table := report.Table()
+
+	require.Len(t, table, 4)
+	assert.Equal(t, "OK", table[1][11])
+	assert.Equal(t, "MISMATCH", table[2][11])
+	assert.Equal(t, "-1.00", table[2][10])
+	assert.Equal(t, []string{"TOTAL", "", "", "20.00", "0.00", "0.00", "0.00", "0.00", "20.00", "19.00", "-1.00", "1 mismatched", ""}, table[3])
+}
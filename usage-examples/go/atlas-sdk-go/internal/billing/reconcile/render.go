@@ -0,0 +1,68 @@
+package reconcile
+
+import (
+	"fmt"
+	"strings"
+
+	"atlas-sdk-go/internal/billing"
+	"atlas-sdk-go/internal/data/export"
+)
+
+// Table returns the report as rows: a header row, one row per invoice, and a closing TOTAL row.
+func (r *Report) Table() [][]string {
+	rows := [][]string{{"Invoice", "Status", "Period", "Gross Charges", "Discounts", "Credits",
+		"Minimum Charges", "Taxes", "Reconciled", "Amount Billed", "Difference", "Result", "Warnings"}}
+	for _, inv := range r.Invoices {
+		period := ""
+		if !inv.StartDate.IsZero() {
+			period = inv.StartDate.Format("2006-01-02") + " to " + inv.EndDate.Format("2006-01-02")
+		}
+		rows = append(rows, []string{
+			inv.InvoiceID,
+			inv.Status,
+			period,
+			billing.FormatCost(inv.GrossCharges),
+			billing.FormatCost(inv.Discounts),
+			billing.FormatCost(inv.Credits),
+			billing.FormatCost(inv.MinimumCharges),
+			billing.FormatCost(inv.Taxes),
+			billing.FormatCost(inv.Reconciled),
+			billing.FormatCost(inv.AmountBilled),
+			billing.FormatCost(inv.Difference),
+			result(inv.Matched),
+			strings.Join(inv.Warnings, "; "),
+		})
+	}
+	rows = append(rows, []string{
+		"TOTAL", "", "",
+		billing.FormatCost(r.GrossCharges),
+		billing.FormatCost(r.Discounts),
+		billing.FormatCost(r.Credits),
+		billing.FormatCost(r.MinimumCharges),
+		billing.FormatCost(r.Taxes),
+		billing.FormatCost(r.Reconciled),
+		billing.FormatCost(r.AmountBilled),
+		billing.FormatCost(r.Difference),
+		fmt.Sprintf("%d mismatched", r.Mismatches),
+		"",
+	})
+	return rows
+}
+
+// WriteCSV writes the report table to a CSV file at filePath.
+func (r *Report) WriteCSV(filePath string) error {
+	return export.ToCSV(r.Table(), filePath)
+}
+
+// WriteJSON writes the report to a JSON file at filePath.
+func (r *Report) WriteJSON(filePath string) error {
+	return export.ToJSON(r, filePath)
+}
+
+// result labels whether an invoice reconciled.
+func result(matched bool) string {
+	if matched {
+		return "OK"
+	}
+	return "MISMATCH"
+}
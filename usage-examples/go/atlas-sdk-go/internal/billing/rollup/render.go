@@ -0,0 +1,123 @@
+package rollup
+
+import (
+	"fmt"
+	"strings"
+
+	"atlas-sdk-go/internal/billing"
+	"atlas-sdk-go/internal/data/export"
+	"atlas-sdk-go/internal/fileutils"
+)
+
+// Table returns the report as a pivot table: a header row, one row per Line, and a closing
+// row of period totals. Columns are the grouping dimensions, each period, then Total, Share,
+// Change, and Change %.
+func (r *Report) Table() [][]string {
+	header := make([]string, 0, len(r.GroupBy)+len(r.Periods)+4)
+	for _, d := range r.GroupBy {
+		header = append(header, string(d))
+	}
+	header = append(header, r.Periods...)
+	header = append(header, "Total", "Share", "Change", "Change %")
+
+	rows := make([][]string, 0, len(r.Lines)+2)
+	rows = append(rows, header)
+
+	for _, line := range r.Lines {
+		row := make([]string, 0, len(header))
+		row = append(row, line.Keys...)
+		for _, c := range line.Costs {
+			row = append(row, billing.FormatCost(c))
+		}
+		row = append(row,
+			billing.FormatCost(line.Total),
+			fmt.Sprintf("%.1f%%", line.Share*100),
+			billing.FormatCost(line.Change),
+			billing.FormatPct(line.ChangePct),
+		)
+		rows = append(rows, row)
+	}
+
+	totals := make([]string, 0, len(header))
+	for i := range r.GroupBy {
+		if i == 0 {
+			totals = append(totals, "TOTAL")
+		} else {
+			totals = append(totals, "")
+		}
+	}
+	for _, c := range r.PeriodTotals {
+		totals = append(totals, billing.FormatCost(c))
+	}
+	change, changePct := r.totalChange()
+	share := "0.0%"
+	if r.Total != 0 {
+		share = "100.0%"
+	}
+	totals = append(totals, billing.FormatCost(r.Total), share, billing.FormatCost(change), billing.FormatPct(changePct))
+	rows = append(rows, totals)
+
+	return rows
+}
+
+// Markdown returns the pivot table as a GitHub-flavored Markdown table.
+func (r *Report) Markdown() string {
+	table := r.Table()
+	var b strings.Builder
+	for i, row := range table {
+		b.WriteString("| ")
+		b.WriteString(strings.Join(escapeMarkdown(row), " | "))
+		b.WriteString(" |\n")
+		if i == 0 {
+			b.WriteString("|")
+			for j := range row {
+				if j < len(r.GroupBy) {
+					b.WriteString(" --- |")
+				} else {
+					b.WriteString(" ---: |")
+				}
+			}
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// WriteCSV writes the pivot table to a CSV file at filePath.
+func (r *Report) WriteCSV(filePath string) error {
+	return export.ToCSV(r.Table(), filePath)
+}
+
+// WriteJSON writes the full report, including unformatted values, to a JSON file at filePath.
+func (r *Report) WriteJSON(filePath string) error {
+	return export.ToJSON(r, filePath)
+}
+
+// WriteMarkdown writes the pivot table to a Markdown file at filePath.
+func (r *Report) WriteMarkdown(filePath string) error {
+	return fileutils.WriteToFile(strings.NewReader(r.Markdown()), filePath)
+}
+
+// totalChange returns the change in total cost between the last two periods.
+func (r *Report) totalChange() (float64, *float64) {
+	n := len(r.PeriodTotals)
+	if n < 2 {
+		return 0, nil
+	}
+	prev, cur := r.PeriodTotals[n-2], r.PeriodTotals[n-1]
+	change := cur - prev
+	if prev == 0 {
+		return change, nil
+	}
+	pct := change / prev * 100
+	return change, &pct
+}
+
+// escapeMarkdown escapes pipe characters so that cell values don't break the table.
+func escapeMarkdown(cells []string) []string {
+	out := make([]string, len(cells))
+	for i, c := range cells {
+		out[i] = strings.ReplaceAll(c, "|", `\|`)
+	}
+	return out
+}
@@ -0,0 +1,216 @@
+package rollup
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"atlas-sdk-go/internal/billing"
+	"atlas-sdk-go/internal/errors"
+)
+
+// Dimension identifies a billing.Detail field that costs can be grouped by.
+type Dimension string
+
+const (
+	Org      Dimension = "org"
+	OrgID    Dimension = "orgId"
+	Project  Dimension = "project"
+	Cluster  Dimension = "cluster"
+	Category Dimension = "category"
+	Provider Dimension = "provider"
+	Instance Dimension = "instance"
+	SKU      Dimension = "sku"
+)
+
+// Period is the time bucket that costs are rolled up into.
+type Period string
+
+const (
+	None  Period = ""      // a single column covering the whole dataset
+	Day   Period = "day"   // YYYY-MM-DD
+	Week  Period = "week"  // ISO week, YYYY-Www
+	Month Period = "month" // YYYY-MM
+)
+
+// Options controls how a Report is built.
+type Options struct {
+	GroupBy []Dimension // Dimensions that form each row, in column order
+	Period  Period      // Time bucket for each column
+}
+
+// Line is one row of a Report: the cost for a single combination of dimension values.
+type Line struct {
+	Keys      []string  `json:"keys"`                // Dimension values, in the order of Report.GroupBy
+	Costs     []float64 `json:"costs"`               // Cost per period, aligned with Report.Periods
+	Total     float64   `json:"total"`               // Sum of Costs
+	Share     float64   `json:"share"`               // Fraction of the report total (0-1)
+	Change    float64   `json:"change"`              // Last period minus the period before it
+	ChangePct *float64  `json:"changePct,omitempty"` // Change as a percentage of the earlier period, if non-zero
+}
+
+// Report is a pivot of cost by dimensions (rows) and periods (columns).
+type Report struct {
+	GroupBy      []Dimension `json:"groupBy"`
+	Period       Period      `json:"period"`
+	Periods      []string    `json:"periods"`
+	Lines        []Line      `json:"lines"`
+	PeriodTotals []float64   `json:"periodTotals"`
+	Total        float64     `json:"total"`
+}
+
+// Build groups details by the requested dimensions and period and computes totals,
+// share of total, and the change between the last two periods.
+// Every period between the earliest and latest detail is included, even if it has no cost.
+// Lines are sorted by total cost, highest first.
+func Build(details []billing.Detail, opts Options) (*Report, error) {
+	for _, d := range opts.GroupBy {
+		if _, err := dimensionValue(billing.Detail{}, d); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := periodKey(time.Time{}, opts.Period); err != nil {
+		return nil, err
+	}
+
+	report := &Report{
+		GroupBy: slices.Clone(opts.GroupBy),
+		Period:  opts.Period,
+		Periods: periodRange(details, opts.Period),
+	}
+	periodIndex := make(map[string]int, len(report.Periods))
+	for i, p := range report.Periods {
+		periodIndex[p] = i
+	}
+	report.PeriodTotals = make([]float64, len(report.Periods))
+
+	lineIndex := make(map[string]int)
+	for _, d := range details {
+		keys := make([]string, len(opts.GroupBy))
+		for i, dim := range opts.GroupBy {
+			keys[i], _ = dimensionValue(d, dim)
+		}
+		id := strings.Join(keys, "\x00")
+
+		li, ok := lineIndex[id]
+		if !ok {
+			li = len(report.Lines)
+			lineIndex[id] = li
+			report.Lines = append(report.Lines, Line{Keys: keys, Costs: make([]float64, len(report.Periods))})
+		}
+
+		pk, _ := periodKey(d.Date, opts.Period)
+		pi := periodIndex[pk]
+		report.Lines[li].Costs[pi] += d.Cost
+		report.Lines[li].Total += d.Cost
+		report.PeriodTotals[pi] += d.Cost
+		report.Total += d.Cost
+	}
+
+	for i := range report.Lines {
+		line := &report.Lines[i]
+		if report.Total != 0 {
+			line.Share = line.Total / report.Total
+		}
+		if n := len(line.Costs); n >= 2 {
+			prev, cur := line.Costs[n-2], line.Costs[n-1]
+			line.Change = cur - prev
+			if prev != 0 {
+				pct := line.Change / prev * 100
+				line.ChangePct = &pct
+			}
+		}
+	}
+
+	slices.SortStableFunc(report.Lines, func(a, b Line) int {
+		if a.Total != b.Total {
+			if a.Total > b.Total {
+				return -1
+			}
+			return 1
+		}
+		return slices.Compare(a.Keys, b.Keys)
+	})
+	return report, nil
+}
+
+// dimensionValue returns the value of dim for a billing detail.
+func dimensionValue(d billing.Detail, dim Dimension) (string, error) {
+	switch dim {
+	case Org:
+		return cmp.Or(d.Org.Name, d.Org.ID), nil
+	case OrgID:
+		return d.Org.ID, nil
+	case Project:
+		return cmp.Or(d.Project.Name, d.Project.ID), nil
+	case Cluster:
+		return d.Cluster, nil
+	case Category:
+		return d.Category, nil
+	case Provider:
+		return d.Provider, nil
+	case Instance:
+		return d.Instance, nil
+	case SKU:
+		return d.SKU, nil
+	default:
+		return "", &errors.ValidationError{Message: fmt.Sprintf("unsupported rollup dimension %q", dim)}
+	}
+}
+
+// periodKey returns the label of the period containing t.
+func periodKey(t time.Time, p Period) (string, error) {
+	t = t.UTC()
+	switch p {
+	case None:
+		return "Total", nil
+	case Day:
+		return t.Format(time.DateOnly), nil
+	case Week:
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%04d-W%02d", year, week), nil
+	case Month:
+		return t.Format("2006-01"), nil
+	default:
+		return "", &errors.ValidationError{Message: fmt.Sprintf("unsupported rollup period %q", p)}
+	}
+}
+
+// periodRange returns every period label from the earliest to the latest detail, in order.
+func periodRange(details []billing.Detail, p Period) []string {
+	if p == None || len(details) == 0 {
+		return []string{"Total"}
+	}
+
+	first, last := details[0].Date.UTC(), details[0].Date.UTC()
+	for _, d := range details[1:] {
+		if d.Date.Before(first) {
+			first = d.Date.UTC()
+		}
+		if d.Date.After(last) {
+			last = d.Date.UTC()
+		}
+	}
+
+	var labels []string
+	lastKey, _ := periodKey(last, p)
+	for t := first; ; {
+		key, _ := periodKey(t, p)
+		if len(labels) == 0 || labels[len(labels)-1] != key {
+			labels = append(labels, key)
+		}
+		if key == lastKey {
+			return labels
+		}
+		switch p {
+		case Day:
+			t = t.AddDate(0, 0, 1)
+		case Week:
+			t = t.AddDate(0, 0, 7)
+		case Month:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package rollup
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"atlas-sdk-go/internal/billing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func detail(project, category string, day int, cost float64) billing.Detail {
+	return billing.Detail{
+		Org:      billing.OrgInfo{ID: "org1", Name: "Org One"},
+		Project:  billing.ProjectInfo{ID: project + "-id", Name: project},
+		Cluster:  "Cluster0",
+		SKU:      "ATLAS_AWS_INSTANCE_M10",
+		Category: category,
+		Provider: "AWS",
+		Instance: "M10",
+		Cost:     cost,
+		Date:     time.Date(2024, 5, day, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestBuild_GroupsByDimensionsAndDay(t *testing.T) {
+	t.Parallel()
+	details := []billing.Detail{
+		detail("app", "Clusters", 1, 10),
+		detail("app", "Clusters", 3, 30),
+		detail("app", "Backup", 1, 5),
+		detail("reporting", "Clusters", 3, 55),
+	}
+
+	report, err := Build(details, Options{GroupBy: []Dimension{Project, Category}, Period: Day})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2024-05-01", "2024-05-02", "2024-05-03"}, report.Periods, "gaps should be filled")
+	assert.InDelta(t, 100.0, report.Total, 0.001)
+	require.Len(t, report.Lines, 3)
+
+	top := report.Lines[0]
+	assert.Equal(t, []string{"reporting", "Clusters"}, top.Keys, "lines should be sorted by total descending")
+	assert.InDelta(t, 0.55, top.Share, 0.001)
+
+	app := report.Lines[1]
+	assert.Equal(t, []string{"app", "Clusters"}, app.Keys)
+	assert.Equal(t, []float64{10, 0, 30}, app.Costs)
+	assert.InDelta(t, 30.0, app.Change, 0.001, "change compares the last two periods")
+	assert.Nil(t, app.ChangePct, "percentage change is undefined when the earlier period is zero")
+	assert.Equal(t, []float64{15, 0, 85}, report.PeriodTotals)
+}
+
+func TestBuild_WeekAndMonthPeriods(t *testing.T) {
+	t.Parallel()
+	details := []billing.Detail{detail("app", "Clusters", 1, 10), detail("app", "Clusters", 20, 15)}
+
+	weekly, err := Build(details, Options{GroupBy: []Dimension{Project}, Period: Week})
+	require.NoError(t, err)
+	assert.Equal(t, "2024-W18", weekly.Periods[0])
+	assert.Equal(t, "2024-W21", weekly.Periods[len(weekly.Periods)-1])
+	assert.Len(t, weekly.Periods, 4)
+
+	monthly, err := Build(details, Options{GroupBy: []Dimension{Project}, Period: Month})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2024-05"}, monthly.Periods)
+	assert.Equal(t, []float64{25}, monthly.Lines[0].Costs)
+}
+
+func TestBuild_PeriodOverPeriodPercent(t *testing.T) {
+	t.Parallel()
+	details := []billing.Detail{detail("app", "Clusters", 1, 20), detail("app", "Clusters", 2, 25)}
+
+	report, err := Build(details, Options{GroupBy: []Dimension{Project}, Period: Day})
+
+	require.NoError(t, err)
+	require.NotNil(t, report.Lines[0].ChangePct)
+	assert.InDelta(t, 25.0, *report.Lines[0].ChangePct, 0.001)
+}
+
+func TestBuild_InvalidOptions(t *testing.T) {
+	t.Parallel()
+	_, err := Build(nil, Options{GroupBy: []Dimension{"colour"}})
+	require.Error(t, err)
+
+	_, err = Build(nil, Options{Period: "fortnight"})
+	require.Error(t, err)
+}
+
+func TestReport_Render(t *testing.T) {
+	t.Parallel()
+	details := []billing.Detail{detail("app", "Clusters", 1, 20), detail("app|x", "Clusters", 2, 25)}
+	report, err := Build(details, Options{GroupBy: []Dimension{Project}, Period: Day})
+	require.NoError(t, err)
+
+	table := report.Table()
+	assert.Equal(t, []string{"project", "2024-05-01", "2024-05-02", "Total", "Share", "Change", "Change %"}, table[0])
+	assert.Equal(t, "TOTAL", table[len(table)-1][0])
+	assert.Equal(t, "+25.0%", table[len(table)-1][6])
+
+	md := report.Markdown()
+	assert.Contains(t, md, `app\|x`, "pipes in values should be escaped")
+	assert.Contains(t, md, "| --- | ---: |")
+
+	dir := t.TempDir()
+	require.NoError(t, report.WriteCSV(filepath.Join(dir, "r.csv")))
+	require.NoError(t, report.WriteJSON(filepath.Join(dir, "r.json")))
+	require.NoError(t, report.WriteMarkdown(filepath.Join(dir, "r.md")))
+
+	csvData, err := os.ReadFile(filepath.Join(dir, "r.csv"))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(csvData), "project,2024-05-01"))
+}
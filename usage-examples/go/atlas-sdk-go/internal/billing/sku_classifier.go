@@ -1,78 +1,290 @@
 package billing
 
 import (
+	"cmp"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
 	"strings"
+
+	"atlas-sdk-go/internal/errors"
 )
 
-// determineProvider identifies the cloud provider based on SKU
-func determineProvider(sku string) string {
-	uppercaseSku := strings.ToUpper(sku)
+//go:embed sku_rules.json
+var defaultSKURules []byte
+
+// SKURule maps SKUs matching a regular expression to a value.
+// Rules are matched against the upper-cased SKU. When several rules match, the one with the
+// highest Priority wins; ties go to the rule listed first.
+type SKURule struct {
+	Name     string `json:"name"`     // Identifies the rule; an override rule with the same name replaces it
+	Match    string `json:"match"`    // Regular expression
+	Value    string `json:"value"`    // Result; may reference capture groups (e.g. "$1")
+	Priority int    `json:"priority"` // Higher priorities are checked first
+}
+
+// SKUDefaults holds the values used when no rule matches.
+type SKUDefaults struct {
+	Provider string `json:"provider"`
+	Category string `json:"category"`
+	Instance string `json:"instance"`
+}
+
+// SKURuleSet is a versioned set of SKU classification rules, one list per attribute.
+type SKURuleSet struct {
+	Version       string      `json:"version"`
+	Defaults      SKUDefaults `json:"defaults"`
+	Provider      []SKURule   `json:"provider,omitempty"`
+	Category      []SKURule   `json:"category,omitempty"`
+	Instance      []SKURule   `json:"instance,omitempty"`
+	InstanceClass []SKURule   `json:"instance_class,omitempty"` // e.g. "nvme", "low-cpu"
+	Region        []SKURule   `json:"region,omitempty"`
+	StorageType   []SKURule   `json:"storage_type,omitempty"`
+}
+
+// SKUClassification holds the attributes derived from a SKU.
+type SKUClassification struct {
+	Provider      string
+	Category      string
+	Instance      string
+	InstanceClass string
+	Region        string
+	StorageType   string
+	Classified    bool // True if a category rule matched
+}
+
+// SKUClassifier classifies SKUs using a compiled SKURuleSet.
+type SKUClassifier struct {
+	version       string
+	defaults      SKUDefaults
+	provider      []compiledRule
+	category      []compiledRule
+	instance      []compiledRule
+	instanceClass []compiledRule
+	region        []compiledRule
+	storageType   []compiledRule
+}
+
+type compiledRule struct {
+	re    *regexp.Regexp
+	value string
+}
+
+// defaultClassifier classifies SKUs with the embedded rules.
+var defaultClassifier *SKUClassifier
 
-	if strings.Contains(uppercaseSku, "AWS") {
-		return "AWS"
-	} else if strings.Contains(strings.ToUpper(sku), "AZURE") {
-		return "AZURE"
-	} else if strings.Contains(strings.ToUpper(sku), "GCP") {
-		return "GCP"
+func init() {
+	rs, err := DefaultSKURules()
+	if err == nil {
+		if defaultClassifier, err = NewSKUClassifier(rs); err == nil {
+			return
+		}
 	}
-	return "n/a"
+	panic(fmt.Sprintf("invalid embedded SKU rules: %v", err))
 }
 
-// determineInstance extracts the instance type from SKU
-func determineInstance(sku string) string {
-	uppercaseSku := strings.ToUpper(sku)
+// DefaultSKURules returns the embedded default rule set.
+func DefaultSKURules() (SKURuleSet, error) {
+	var rs SKURuleSet
+	if err := json.Unmarshal(defaultSKURules, &rs); err != nil {
+		return rs, errors.WithContext(err, "parsing embedded SKU rules")
+	}
+	return rs, nil
+}
 
-	parts := strings.Split(uppercaseSku, "_INSTANCE_")
-	if len(parts) > 1 {
-		return parts[1]
+// LoadSKURules reads a rule set from a JSON file and merges it over the embedded defaults.
+// If path is empty, the defaults are returned unchanged.
+func LoadSKURules(path string) (SKURuleSet, error) {
+	rs, err := DefaultSKURules()
+	if err != nil || path == "" {
+		return rs, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return rs, &errors.NotFoundError{Resource: "SKU rules file", ID: path}
+		}
+		return rs, errors.WithContext(err, "reading SKU rules file")
+	}
+	var override SKURuleSet
+	if err := json.Unmarshal(data, &override); err != nil {
+		return rs, errors.WithContext(err, "parsing SKU rules file")
 	}
-	return "non-instance"
+	return rs.Merge(override), nil
 }
 
-// determineCategory identifies the service category based on SKU
-func determineCategory(sku string) string {
-	uppercaseSku := strings.ToUpper(sku)
+// Merge returns a copy of rs with the rules in override applied on top. Override rules replace
+// rules with the same name and are otherwise added. A non-empty version or default in override
+// replaces the one in rs.
+func (rs SKURuleSet) Merge(override SKURuleSet) SKURuleSet {
+	merged := SKURuleSet{
+		Version:       cmp.Or(override.Version, rs.Version),
+		Defaults:      rs.Defaults,
+		Provider:      mergeRules(rs.Provider, override.Provider),
+		Category:      mergeRules(rs.Category, override.Category),
+		Instance:      mergeRules(rs.Instance, override.Instance),
+		InstanceClass: mergeRules(rs.InstanceClass, override.InstanceClass),
+		Region:        mergeRules(rs.Region, override.Region),
+		StorageType:   mergeRules(rs.StorageType, override.StorageType),
+	}
+	merged.Defaults.Provider = cmp.Or(override.Defaults.Provider, merged.Defaults.Provider)
+	merged.Defaults.Category = cmp.Or(override.Defaults.Category, merged.Defaults.Category)
+	merged.Defaults.Instance = cmp.Or(override.Defaults.Instance, merged.Defaults.Instance)
+	return merged
+}
 
-	// Category patterns are defined in order of specificity
-	categoryPatterns := []struct {
-		pattern  string
-		category string
+// NewSKUClassifier validates and compiles a rule set.
+func NewSKUClassifier(rs SKURuleSet) (*SKUClassifier, error) {
+	if rs.Version == "" {
+		return nil, &errors.ValidationError{Message: "SKU rule set version is required"}
+	}
+	c := &SKUClassifier{version: rs.Version, defaults: rs.Defaults}
+	lists := []struct {
+		attr  string
+		rules []SKURule
+		dst   *[]compiledRule
 	}{
-		{"CLASSIC_BACKUP", "Legacy Backup"},
-		{"BACKUP_SNAPSHOT", "Backup"},
-		{"BACKUP_DOWNLOAD", "Backup"},
-		{"BACKUP_STORAGE", "Backup"},
-		{"DATA_FEDERATION", "Atlas Data Federation"},
-		{"DATA_LAKE", "Atlas Data Federation"},
-		{"STREAM_PROCESSING", "Atlas Stream Processing"},
-		{"PRIVATE_ENDPOINT", "Data Transfer"},
-		{"DATA_TRANSFER", "Data Transfer"},
-		{"SNAPSHOT_COPY", "Backup"},
-		{"SNAPSHOT_EXPORT", "Backup"},
-		{"OBJECT_STORAGE", "Backup"},
-		{"PIT_RESTORE", "Backup"},
-		{"BI_CONNECTOR", "BI Connector"},
-		{"CHARTS", "Charts"},
-		{"INSTANCE", "Clusters"},
-		{"MMS", "Cloud Manager Standard/Premium"},
-		{"CLASSIC_COUPON", "Credits"},
-		{"CREDIT", "Credits"},
-		{"MINIMUM_CHARGE", "Credits"},
-		{"FLEX_CONSULTING", "Flex Consulting"},
-		{"AUDITING", "Premium Features"},
-		{"ADVANCED_SECURITY", "Premium Features"},
-		{"SERVERLESS", "Serverless Instances"},
-		{"STORAGE", "Storage"},
-		{"ENTITLEMENTS", "Support"},
-		{"FREE_SUPPORT", "Support"},
-		{"REALM", "App Services"},
-		{"STITCH", "App Services"},
+		{"provider", rs.Provider, &c.provider},
+		{"category", rs.Category, &c.category},
+		{"instance", rs.Instance, &c.instance},
+		{"instance_class", rs.InstanceClass, &c.instanceClass},
+		{"region", rs.Region, &c.region},
+		{"storage_type", rs.StorageType, &c.storageType},
+	}
+	for _, l := range lists {
+		compiled, err := compileRules(l.attr, l.rules)
+		if err != nil {
+			return nil, err
+		}
+		*l.dst = compiled
+	}
+	return c, nil
+}
+
+// LoadSKUClassifier builds a classifier from the embedded defaults merged with the rules
+// file at path, if any.
+func LoadSKUClassifier(path string) (*SKUClassifier, error) {
+	rs, err := LoadSKURules(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewSKUClassifier(rs)
+}
+
+// DefaultSKUClassifier returns the classifier built from the embedded rules.
+func DefaultSKUClassifier() *SKUClassifier {
+	return defaultClassifier
+}
+
+// Version returns the version of the rule set the classifier was built from.
+func (c *SKUClassifier) Version() string {
+	return c.version
+}
+
+// Classify derives provider, category, instance size and class, region, and storage type from a SKU.
+func (c *SKUClassifier) Classify(sku string) SKUClassification {
+	upper := strings.ToUpper(sku)
+	category, classified := applyRules(c.category, upper)
+	provider, _ := applyRules(c.provider, upper)
+	instance, _ := applyRules(c.instance, upper)
+	instanceClass, _ := applyRules(c.instanceClass, upper)
+	region, _ := applyRules(c.region, upper)
+	storageType, _ := applyRules(c.storageType, upper)
+
+	return SKUClassification{
+		Provider:      cmp.Or(provider, c.defaults.Provider),
+		Category:      cmp.Or(category, c.defaults.Category),
+		Instance:      cmp.Or(instance, c.defaults.Instance),
+		InstanceClass: instanceClass,
+		Region:        region,
+		StorageType:   storageType,
+		Classified:    classified,
+	}
+}
+
+// UnclassifiedSKU summarizes line items whose SKU matched no category rule.
+type UnclassifiedSKU struct {
+	SKU   string  `json:"sku"`
+	Count int     `json:"count"`
+	Cost  float64 `json:"cost"`
+}
+
+// Unclassified returns the SKUs in details that match no category rule, with the number of line
+// items and total cost for each, sorted by cost (highest first) so the most expensive gaps in the
+// rules are fixed first.
+func (c *SKUClassifier) Unclassified(details []Detail) []UnclassifiedSKU {
+	index := make(map[string]int)
+	var out []UnclassifiedSKU
+	for _, d := range details {
+		if c.Classify(d.SKU).Classified {
+			continue
+		}
+		i, ok := index[d.SKU]
+		if !ok {
+			i = len(out)
+			index[d.SKU] = i
+			out = append(out, UnclassifiedSKU{SKU: d.SKU})
+		}
+		out[i].Count++
+		out[i].Cost += d.Cost
+	}
+	slices.SortStableFunc(out, func(a, b UnclassifiedSKU) int {
+		if a.Cost != b.Cost {
+			if a.Cost > b.Cost {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.SKU, b.SKU)
+	})
+	return out
+}
+
+// compileRules compiles rules and orders them by priority, highest first.
+func compileRules(attr string, rules []SKURule) ([]compiledRule, error) {
+	sorted := slices.Clone(rules)
+	slices.SortStableFunc(sorted, func(a, b SKURule) int { return b.Priority - a.Priority })
+
+	compiled := make([]compiledRule, 0, len(sorted))
+	for _, r := range sorted {
+		if r.Value == "" {
+			return nil, &errors.ValidationError{Message: fmt.Sprintf("%s rule %q has no value", attr, r.Name)}
+		}
+		re, err := regexp.Compile(r.Match)
+		if err != nil || r.Match == "" {
+			return nil, &errors.ValidationError{Message: fmt.Sprintf("%s rule %q has an invalid match pattern %q", attr, r.Name, r.Match)}
+		}
+		compiled = append(compiled, compiledRule{re: re, value: r.Value})
 	}
+	return compiled, nil
+}
+
+// mergeRules replaces base rules that share a name with an override rule and appends the rest.
+func mergeRules(base, override []SKURule) []SKURule {
+	merged := slices.Clone(base)
+	for _, o := range override {
+		i := slices.IndexFunc(merged, func(r SKURule) bool { return o.Name != "" && r.Name == o.Name })
+		if i >= 0 {
+			merged[i] = o
+		} else {
+			merged = append(merged, o)
+		}
+	}
+	return merged
+}
 
-	for _, pc := range categoryPatterns {
-		if strings.Index(uppercaseSku, pc.pattern) != -1 {
-			return pc.category
+// applyRules returns the value of the first matching rule, expanding capture group references.
+func applyRules(rules []compiledRule, sku string) (string, bool) {
+	for _, r := range rules {
+		m := r.re.FindStringSubmatchIndex(sku)
+		if m == nil {
+			continue
 		}
+		return string(r.re.ExpandString(nil, r.value, sku, m)), true
 	}
-	return "Other"
+	return "", false
 }
@@ -1,12 +1,17 @@
 package billing
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
+	internalerrors "atlas-sdk-go/internal/errors"
+
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
-func TestDetermineProvider(t *testing.T) {
+func TestSKUClassifier_Provider(t *testing.T) {
 	tests := []struct {
 		name     string
 		sku      string
@@ -22,13 +27,13 @@ func TestDetermineProvider(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			result := determineProvider(tc.sku)
+			result := DefaultSKUClassifier().Classify(tc.sku).Provider
 			assert.Equal(t, tc.expected, result)
 		})
 	}
 }
 
-func TestDetermineInstance(t *testing.T) {
+func TestSKUClassifier_Instance(t *testing.T) {
 	tests := []struct {
 		name     string
 		sku      string
@@ -43,13 +48,13 @@ func TestDetermineInstance(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			result := determineInstance(tc.sku)
+			result := DefaultSKUClassifier().Classify(tc.sku).Instance
 			assert.Equal(t, tc.expected, result)
 		})
 	}
 }
 
-func TestDetermineCategory(t *testing.T) {
+func TestSKUClassifier_Category(t *testing.T) {
 	tests := []struct {
 		name     string
 		sku      string
@@ -84,8 +89,116 @@ func TestDetermineCategory(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			result := determineCategory(tc.sku)
+			result := DefaultSKUClassifier().Classify(tc.sku).Category
 			assert.Equal(t, tc.expected, result)
 		})
 	}
 }
+
+func TestSKUClassifier_ExtractsClassesRegionAndStorage(t *testing.T) {
+	t.Parallel()
+	c := DefaultSKUClassifier()
+	tests := []struct {
+		sku           string
+		instanceClass string
+		region        string
+		storageType   string
+	}{
+		{"NDS_AWS_INSTANCE_M40_NVME", "nvme", "", "nvme"},
+		{"NDS_AWS_INSTANCE_R40", "low-cpu", "", ""},
+		{"NDS_AWS_INSTANCE_M10", "general", "", ""},
+		{"NDS_AWS_SEARCH_INSTANCE_S20_COMPUTE_NVME", "nvme", "", "nvme"},
+		{"NDS_AWS_STORAGE_PROVISIONED_IOPS", "", "", "provisioned-iops"},
+		{"NDS_AWS_STORAGE_STANDARD", "", "", "standard"},
+		{"NDS_AWS_BACKUP_SNAPSHOT_STORAGE", "", "", "snapshot"},
+		{"NDS_AWS_INSTANCE_M30_US_EAST_1", "general", "US_EAST_1", ""},
+		{"NDS_AZURE_INSTANCE_M30_WEST_EUROPE", "general", "WEST_EUROPE", ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.sku, func(t *testing.T) {
+			t.Parallel()
+			got := c.Classify(tc.sku)
+			assert.Equal(t, tc.instanceClass, got.InstanceClass, "instance class")
+			assert.Equal(t, tc.region, got.Region, "region")
+			assert.Equal(t, tc.storageType, got.StorageType, "storage type")
+		})
+	}
+}
+
+func TestSKURuleSet_MergeOverridesByNameAndPriority(t *testing.T) {
+	t.Parallel()
+	rs, err := DefaultSKURules()
+	require.NoError(t, err)
+
+	merged := rs.Merge(SKURuleSet{
+		Version: "custom-1",
+		Category: []SKURule{
+			{Name: "vector-search", Match: `VECTOR_SEARCH`, Value: "Vector Search", Priority: 1000},
+			{Name: "charts", Match: `^CHARTS_`, Value: "Visualization", Priority: 210},
+		},
+	})
+	c, err := NewSKUClassifier(merged)
+	require.NoError(t, err)
+
+	assert.Equal(t, "custom-1", c.Version())
+	assert.Equal(t, "Vector Search", c.Classify("NDS_AWS_VECTOR_SEARCH_INSTANCE_S30").Category, "higher priority wins over INSTANCE")
+	assert.Equal(t, "Visualization", c.Classify("CHARTS_DATA_DOWNLOADED").Category, "rule with the same name is replaced")
+	assert.Equal(t, "Clusters", c.Classify("NDS_AWS_INSTANCE_M10").Category, "other defaults are kept")
+	assert.Len(t, merged.Category, len(rs.Category)+1)
+}
+
+func TestNewSKUClassifier_InvalidRules(t *testing.T) {
+	t.Parallel()
+	tests := map[string]SKURuleSet{
+		"missing version": {},
+		"bad regex":       {Version: "1", Category: []SKURule{{Name: "x", Match: "(", Value: "X"}}},
+		"empty match":     {Version: "1", Category: []SKURule{{Name: "x", Value: "X"}}},
+		"missing value":   {Version: "1", Region: []SKURule{{Name: "x", Match: "US"}}},
+	}
+	for name, rs := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			_, err := NewSKUClassifier(rs)
+			var vErr *internalerrors.ValidationError
+			assert.ErrorAs(t, err, &vErr)
+		})
+	}
+}
+
+func TestLoadSKUClassifier_FromFile(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "sku_rules.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"version": "2025-03-01",
+		"defaults": {"category": "Unclassified"},
+		"category": [{"name": "vector-search", "match": "VECTOR", "value": "Vector Search", "priority": 500}]
+	}`), 0o600))
+
+	c, err := LoadSKUClassifier(path)
+	require.NoError(t, err)
+	assert.Equal(t, "2025-03-01", c.Version())
+	assert.Equal(t, "Vector Search", c.Classify("NDS_VECTOR_NODES").Category)
+	assert.Equal(t, "Unclassified", c.Classify("SOMETHING_NEW").Category)
+	assert.Equal(t, "n/a", c.Classify("SOMETHING_NEW").Provider, "unset defaults are kept")
+
+	_, err = LoadSKUClassifier(filepath.Join(t.TempDir(), "missing.json"))
+	var nfErr *internalerrors.NotFoundError
+	assert.ErrorAs(t, err, &nfErr)
+}
+
+func TestSKUClassifier_Unclassified(t *testing.T) {
+	t.Parallel()
+	details := []Detail{
+		{SKU: "NDS_AWS_INSTANCE_M10", Cost: 100},
+		{SKU: "NDS_NEW_FEATURE", Cost: 2},
+		{SKU: "NDS_NEW_FEATURE", Cost: 3},
+		{SKU: "ANOTHER_NEW_THING", Cost: 10},
+	}
+
+	got := DefaultSKUClassifier().Unclassified(details)
+
+	assert.Equal(t, []UnclassifiedSKU{
+		{SKU: "ANOTHER_NEW_THING", Count: 1, Cost: 10},
+		{SKU: "NDS_NEW_FEATURE", Count: 2, Cost: 5},
+	}, got)
+}
@@ -0,0 +1,191 @@
+package billing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func syncDetail(day int, sku string, cost float64) Detail {
+	return Detail{
+		Project: ProjectInfo{ID: "p1"},
+		Cluster: "Cluster0",
+		SKU:     sku,
+		Cost:    cost,
+		Date:    time.Date(2024, 5, day, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestDiffLineItems_FirstSync(t *testing.T) {
+	t.Parallel()
+	since := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	details := []Detail{syncDetail(1, "A", 1), syncDetail(2, "A", 2), syncDetail(2, "B", 3)}
+
+	items, next := diffLineItems(Checkpoint{OrgID: "org1"}, details, since, 48*time.Hour)
+
+	assert.Len(t, items, 3, "all items should be new on first sync")
+	assert.True(t, next.LastProcessedDate.Equal(details[2].Date))
+	assert.Len(t, next.Seen, 3, "items within the revision window should be remembered")
+}
+
+func TestDiffLineItems_DetectsRevisionsAndSkipsUnchanged(t *testing.T) {
+	t.Parallel()
+	window := 48 * time.Hour
+	_, cp := diffLineItems(Checkpoint{OrgID: "org1"}, []Detail{syncDetail(9, "A", 1), syncDetail(10, "A", 2)},
+		time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), window)
+
+	since := cp.LastProcessedDate.Add(-window)
+	details := []Detail{
+		syncDetail(9, "A", 1),    // unchanged
+		syncDetail(10, "A", 2.5), // revised by Atlas
+		syncDetail(11, "A", 4),   // new
+	}
+	items, next := diffLineItems(cp, details, since, window)
+
+	require.Len(t, items, 2)
+	assert.True(t, items[0].Revised)
+	assert.InDelta(t, 2.0, items[0].PreviousCost, 0.001)
+	assert.InDelta(t, 2.5, items[0].Cost, 0.001)
+	assert.False(t, items[1].Revised)
+	assert.True(t, next.LastProcessedDate.Equal(details[2].Date))
+	assert.Len(t, next.Seen, 3)
+}
+
+func TestDiffLineItems_PrunesOutsideWindow(t *testing.T) {
+	t.Parallel()
+	details := []Detail{syncDetail(1, "A", 1), syncDetail(5, "A", 1)}
+
+	_, next := diffLineItems(Checkpoint{OrgID: "org1"}, details, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), 24*time.Hour)
+
+	assert.Len(t, next.Seen, 1, "items older than the revision window should be forgotten")
+}
+
+func TestDiffLineItems_DuplicateKeys(t *testing.T) {
+	t.Parallel()
+	details := []Detail{syncDetail(1, "A", 1), syncDetail(1, "A", 2)}
+
+	items, next := diffLineItems(Checkpoint{OrgID: "org1"}, details, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), 24*time.Hour)
+
+	assert.Len(t, items, 2)
+	assert.Len(t, next.Seen, 2)
+}
+
+func TestDiffLineItems_TiersMatchRegardlessOfOrder(t *testing.T) {
+	t.Parallel()
+	since := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	low, high := syncDetail(1, "A", 1), syncDetail(1, "A", 2)
+	low.Tier, high.Tier = "0-100", "100+"
+	_, cp := diffLineItems(Checkpoint{OrgID: "org1"}, []Detail{low, high}, since, 24*time.Hour)
+
+	items, _ := diffLineItems(cp, []Detail{high, low}, since, 24*time.Hour)
+
+	assert.Empty(t, items, "reordered tiers are not revisions")
+}
+
+func TestSyncLineItems_CheckpointAdvancesOnlyAfterCommit(t *testing.T) {
+	t.Parallel()
+	orgID := "org123"
+	var inFlight, maxInFlight int32
+	client := newTestAtlasClient(t, invoiceHandler(t, orgID, []string{"inv_1"}, &inFlight, &maxInFlight))
+	store := NewFileCheckpointStore(filepath.Join(t.TempDir(), "checkpoints.json"))
+	opts := SyncOptions{
+		InitialSince: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		Now:          func() time.Time { return time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC) },
+	}
+
+	failing := func([]SyncedDetail) error { return errors.New("disk full") }
+	_, err := SyncLineItems(context.Background(), client.InvoicesApi, client.OrganizationsApi, orgID, store, failing, opts)
+	require.Error(t, err)
+	cp, err := store.Load(orgID)
+	require.NoError(t, err)
+	assert.Nil(t, cp, "checkpoint must not be written when commit fails")
+
+	var commits int32
+	ok := func(items []SyncedDetail) error {
+		atomic.AddInt32(&commits, 1)
+		return nil
+	}
+	result, err := SyncLineItems(context.Background(), client.InvoicesApi, client.OrganizationsApi, orgID, store, ok, opts)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.NewCount)
+
+	cp, err = store.Load(orgID)
+	require.NoError(t, err)
+	require.NotNil(t, cp)
+	assert.Equal(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), cp.LastProcessedDate.UTC())
+
+	// A second run with no changes should not commit again
+	result, err = SyncLineItems(context.Background(), client.InvoicesApi, client.OrganizationsApi, orgID, store, ok, opts)
+	require.NoError(t, err)
+	assert.Empty(t, result.Items)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&commits))
+}
+
+func TestSyncLineItems_ReadsCurrentInvoiceAfterCheckpoint(t *testing.T) {
+	t.Parallel()
+	orgID := "org123"
+	invoiceStarts := map[string]string{"inv_apr": "2024-04-01", "inv_may": "2024-05-01"}
+	var mayItems atomic.Value
+	mayItems.Store([]string{"2024-05-01", "2024-05-19"})
+
+	// Like Atlas, list only invoices that start on or after fromDate
+	client := newTestAtlasClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/orgs/"+orgID+"/invoices"):
+			from := r.URL.Query().Get("fromDate")
+			var results []string
+			for _, id := range []string{"inv_apr", "inv_may"} {
+				if invoiceStarts[id] >= from {
+					results = append(results, fmt.Sprintf(`{"id": %q, "statusName": "PENDING", "startDate": "%sT00:00:00Z"}`, id, invoiceStarts[id]))
+				}
+			}
+			_, _ = fmt.Fprintf(w, `{"results": [%s], "totalCount": %d}`, strings.Join(results, ","), len(results))
+		case strings.Contains(r.URL.Path, "/orgs/"+orgID+"/invoices/"):
+			id := path.Base(r.URL.Path)
+			days := []string{"2024-04-10"}
+			if id == "inv_may" {
+				days = mayItems.Load().([]string)
+			}
+			var items []string
+			for _, day := range days {
+				items = append(items, fmt.Sprintf(`{"sku": "ATLAS_AWS_INSTANCE_M10", "totalPriceCents": 1000, "groupId": "p1", "startDate": "%sT00:00:00Z"}`, day))
+			}
+			_, _ = fmt.Fprintf(w, `{"id": %q, "lineItems": [%s]}`, id, strings.Join(items, ","))
+		case strings.HasSuffix(r.URL.Path, "/orgs/"+orgID):
+			_, _ = w.Write([]byte(`{"id": "` + orgID + `", "name": "Test Org"}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	store := NewFileCheckpointStore(filepath.Join(t.TempDir(), "checkpoints.json"))
+	commit := func([]SyncedDetail) error { return nil }
+	opts := SyncOptions{
+		InitialSince: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		Now:          func() time.Time { return time.Date(2024, 5, 20, 0, 0, 0, 0, time.UTC) },
+	}
+
+	result, err := SyncLineItems(context.Background(), client.InvoicesApi, client.OrganizationsApi, orgID, store, commit, opts)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.NewCount)
+
+	// Later in the month the checkpoint is well past the invoice's start date
+	mayItems.Store([]string{"2024-05-01", "2024-05-19", "2024-05-21"})
+	opts.Now = func() time.Time { return time.Date(2024, 5, 22, 0, 0, 0, 0, time.UTC) }
+	result, err = SyncLineItems(context.Background(), client.InvoicesApi, client.OrganizationsApi, orgID, store, commit, opts)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 5, 16, 0, 0, 0, 0, time.UTC), result.Since)
+	require.Len(t, result.Items, 1)
+	assert.Equal(t, time.Date(2024, 5, 21, 0, 0, 0, 0, time.UTC), result.Items[0].Date.UTC())
+}
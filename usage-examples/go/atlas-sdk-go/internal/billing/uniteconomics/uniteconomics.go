@@ -0,0 +1,168 @@
+package uniteconomics
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"atlas-sdk-go/internal/billing"
+	"atlas-sdk-go/internal/data/export"
+)
+
+// Average month length used to convert hour- and day-based quantities to months.
+const (
+	HoursPerMonth = 730.0
+	DaysPerMonth  = HoursPerMonth / 24
+)
+
+// instanceCategory is the billing category of cluster instance charges.
+const instanceCategory = "Clusters"
+
+// ClusterCost is the unit economics of a single cluster.
+type ClusterCost struct {
+	OrgID               string   `json:"orgId"`
+	Project             string   `json:"project"`
+	Cluster             string   `json:"cluster"`
+	TotalCost           float64  `json:"totalCost"`                     // All line items for the cluster
+	InstanceHours       float64  `json:"instanceHours"`                 // Hours billed for cluster instances
+	InstanceCost        float64  `json:"instanceCost"`                  // Cost of the instance hours
+	CostPerInstanceHour *float64 `json:"costPerInstanceHour,omitempty"` // InstanceCost / InstanceHours
+	GBMonths            float64  `json:"gbMonths"`                      // Storage billed, normalized to GB-months
+	StorageCost         float64  `json:"storageCost"`                   // Cost of the GB-months
+	CostPerGBMonth      *float64 `json:"costPerGbMonth,omitempty"`      // StorageCost / GBMonths
+}
+
+// Report lists unit economics per cluster, sorted by total cost, highest first.
+type Report struct {
+	Clusters []ClusterCost `json:"clusters"`
+}
+
+// Build computes cost per instance-hour and cost per GB-month for each cluster from the
+// Quantity and Unit of its line items.
+//
+// Instance hours are taken from Clusters line items billed by the hour. Storage is taken from any
+// line item billed in GB per unit of time (hours, days, or months) and normalized to GB-months
+// using an average month of HoursPerMonth hours. Line items without a cluster are ignored.
+func Build(details []billing.Detail) *Report {
+	index := make(map[string]int)
+	var clusters []ClusterCost
+	for _, d := range details {
+		if !d.HasCluster() {
+			continue
+		}
+		project := cmp.Or(d.Project.Name, d.Project.ID)
+		key := strings.Join([]string{d.Org.ID, d.Project.ID, d.Cluster}, "\x00")
+		i, ok := index[key]
+		if !ok {
+			i = len(clusters)
+			index[key] = i
+			clusters = append(clusters, ClusterCost{OrgID: d.Org.ID, Project: project, Cluster: d.Cluster})
+		}
+		c := &clusters[i]
+		c.TotalCost += d.Cost
+
+		switch kind, factor := classifyUnit(d.Unit); kind {
+		case unitHours:
+			if d.Category == instanceCategory {
+				c.InstanceHours += d.Quantity * factor
+				c.InstanceCost += d.Cost
+			}
+		case unitGBTime:
+			c.GBMonths += d.Quantity * factor
+			c.StorageCost += d.Cost
+		}
+	}
+
+	for i := range clusters {
+		c := &clusters[i]
+		c.CostPerInstanceHour = ratio(c.InstanceCost, c.InstanceHours)
+		c.CostPerGBMonth = ratio(c.StorageCost, c.GBMonths)
+	}
+	slices.SortStableFunc(clusters, func(a, b ClusterCost) int {
+		if c := cmp.Compare(b.TotalCost, a.TotalCost); c != 0 {
+			return c
+		}
+		return cmp.Or(strings.Compare(a.Project, b.Project), strings.Compare(a.Cluster, b.Cluster))
+	})
+	return &Report{Clusters: clusters}
+}
+
+// Table returns the report as rows with a header row first.
+func (r *Report) Table() [][]string {
+	rows := [][]string{{"Project", "Cluster", "Total Cost", "Instance Hours", "Instance Cost",
+		"Cost/Instance-Hour", "GB-Months", "Storage Cost", "Cost/GB-Month"}}
+	for _, c := range r.Clusters {
+		rows = append(rows, []string{
+			c.Project,
+			c.Cluster,
+			fmt.Sprintf("%.2f", c.TotalCost),
+			fmt.Sprintf("%.1f", c.InstanceHours),
+			fmt.Sprintf("%.2f", c.InstanceCost),
+			formatRate(c.CostPerInstanceHour),
+			fmt.Sprintf("%.2f", c.GBMonths),
+			fmt.Sprintf("%.2f", c.StorageCost),
+			formatRate(c.CostPerGBMonth),
+		})
+	}
+	return rows
+}
+
+// WriteCSV writes the report table to a CSV file at filePath.
+func (r *Report) WriteCSV(filePath string) error {
+	return export.ToCSV(r.Table(), filePath)
+}
+
+// WriteJSON writes the report to a JSON file at filePath.
+func (r *Report) WriteJSON(filePath string) error {
+	return export.ToJSON(r, filePath)
+}
+
+type unitKind int
+
+const (
+	unitOther  unitKind = iota
+	unitHours           // e.g. "server hours"
+	unitGBTime          // e.g. "GB days", "GB-months"
+)
+
+// classifyUnit identifies time-based units and returns the factor that converts a quantity to
+// hours (for unitHours) or GB-months (for unitGBTime).
+func classifyUnit(unit string) (unitKind, float64) {
+	u := strings.ToLower(unit)
+	isGB := strings.Contains(u, "gb") || strings.Contains(u, "gigabyte")
+	switch {
+	case isGB && strings.Contains(u, "month"):
+		return unitGBTime, 1
+	case isGB && strings.Contains(u, "day"):
+		return unitGBTime, 1 / DaysPerMonth
+	case isGB && strings.Contains(u, "hour"):
+		return unitGBTime, 1 / HoursPerMonth
+	case isGB:
+		// Data transferred or scanned rather than stored over time
+		return unitOther, 0
+	case strings.Contains(u, "hour"):
+		return unitHours, 1
+	case strings.Contains(u, "day"):
+		return unitHours, 24
+	default:
+		return unitOther, 0
+	}
+}
+
+// ratio returns cost/qty, or nil when qty is zero.
+func ratio(cost, qty float64) *float64 {
+	if qty == 0 {
+		return nil
+	}
+	v := cost / qty
+	return &v
+}
+
+// formatRate formats an optional unit cost, returning "n/a" when it is undefined.
+func formatRate(v *float64) string {
+	if v == nil {
+		return "n/a"
+	}
+	return fmt.Sprintf("%.4f", *v)
+}
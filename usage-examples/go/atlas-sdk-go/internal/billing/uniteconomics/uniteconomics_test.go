@@ -0,0 +1,64 @@
+package uniteconomics
+
+import (
+	"testing"
+	"time"
+
+	"atlas-sdk-go/internal/billing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func detail(cluster, category, unit string, qty, cost float64) billing.Detail {
+	return billing.Detail{
+		Org:      billing.OrgInfo{ID: "org1"},
+		Project:  billing.ProjectInfo{ID: "p1", Name: "app"},
+		Cluster:  cluster,
+		Category: category,
+		Unit:     unit,
+		Quantity: qty,
+		Cost:     cost,
+		Date:     time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestBuild_CostPerInstanceHourAndGBMonth(t *testing.T) {
+	t.Parallel()
+	details := []billing.Detail{
+		detail("Cluster0", "Clusters", "server hours", 48, 24),
+		detail("Cluster0", "Clusters", "server hours", 24, 12),
+		detail("Cluster0", "Storage", "GB days", 10*DaysPerMonth, 5),
+		detail("Cluster0", "Backup", "GB months", 10, 5),
+		detail("Cluster0", "Data Transfer", "GB", 100, 9),
+		detail("Cluster1", "Clusters", "server hours", 10, 100),
+		detail("N/A", "Support", "", 0, 50),
+	}
+
+	report := Build(details)
+
+	require.Len(t, report.Clusters, 2, "line items without a cluster should be ignored")
+	c1 := report.Clusters[0]
+	assert.Equal(t, "Cluster1", c1.Cluster, "clusters should be sorted by total cost descending")
+	require.NotNil(t, c1.CostPerInstanceHour)
+	assert.InDelta(t, 10.0, *c1.CostPerInstanceHour, 0.0001)
+	assert.Nil(t, c1.CostPerGBMonth, "undefined without storage")
+
+	c0 := report.Clusters[1]
+	assert.InDelta(t, 55.0, c0.TotalCost, 0.0001)
+	assert.InDelta(t, 72.0, c0.InstanceHours, 0.0001)
+	assert.InDelta(t, 0.5, *c0.CostPerInstanceHour, 0.0001)
+	assert.InDelta(t, 20.0, c0.GBMonths, 0.0001, "GB days are normalized to GB-months")
+	assert.InDelta(t, 10.0, c0.StorageCost, 0.0001, "data transfer is not storage")
+	assert.InDelta(t, 0.5, *c0.CostPerGBMonth, 0.0001)
+}
+
+func TestReport_Table(t *testing.T) {
+	t.Parallel()
+	report := Build([]billing.Detail{detail("Cluster0", "Clusters", "server hours", 4, 2)})
+
+	table := report.Table()
+
+	require.Len(t, table, 2)
+	assert.Equal(t, []string{"app", "Cluster0", "2.00", "4.0", "2.00", "0.5000", "0.00", "0.00", "n/a"}, table[1])
+}
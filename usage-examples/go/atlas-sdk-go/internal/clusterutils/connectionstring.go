@@ -1,8 +1,11 @@
 package clusterutils
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 
 	"go.mongodb.org/atlas-sdk/v20250219001/admin"
 
@@ -23,3 +26,75 @@ func GetClusterSRVConnectionString(ctx context.Context, client *admin.APIClient,
 	}
 	return *cluster.ConnectionStrings.StandardSrv, nil
 }
+
+// Connection string types accepted by ResolveConnectionString
+const (
+	ConnectionStandard        = "standard"
+	ConnectionPrivateEndpoint = "private_endpoint"
+	ConnectionPrivate         = "private"
+)
+
+// DefaultConnectionTypes is the preference order used when none is given.
+var DefaultConnectionTypes = []string{ConnectionStandard, ConnectionPrivateEndpoint, ConnectionPrivate}
+
+// ConnectionString is a cluster connection string and the network path it uses.
+type ConnectionString struct {
+	Type string // ConnectionStandard, ConnectionPrivateEndpoint, or ConnectionPrivate
+	URI  string
+}
+
+// GetClusterConnectionString fetches a cluster and resolves its connection string with ResolveConnectionString.
+func GetClusterConnectionString(ctx context.Context, client *admin.APIClient, projectID, clusterName string,
+	types []string, privateEndpointID string) (ConnectionString, error) {
+	if client == nil {
+		return ConnectionString{}, fmt.Errorf("nil atlas api client")
+	}
+	cluster, _, err := client.ClustersApi.GetCluster(ctx, projectID, clusterName).Execute()
+	if err != nil {
+		return ConnectionString{}, errors.FormatError("get cluster", projectID, err)
+	}
+	return ResolveConnectionString(cluster.GetConnectionStrings(), types, privateEndpointID)
+}
+
+// ResolveConnectionString returns the first connection string available in preference order
+// (DefaultConnectionTypes if types is empty). SRV strings are preferred over the equivalent
+// seed list. If privateEndpointID is set, only the private endpoint that includes it is used;
+// otherwise the first private endpoint is used.
+func ResolveConnectionString(cs admin.ClusterConnectionStrings, types []string, privateEndpointID string) (ConnectionString, error) {
+	if len(types) == 0 {
+		types = DefaultConnectionTypes
+	}
+	for _, t := range types {
+		var uri string
+		switch t {
+		case ConnectionStandard:
+			uri = cmp.Or(cs.GetStandardSrv(), cs.GetStandard())
+		case ConnectionPrivateEndpoint:
+			uri = privateEndpointURI(cs.GetPrivateEndpoint(), privateEndpointID)
+		case ConnectionPrivate:
+			uri = cmp.Or(cs.GetPrivateSrv(), cs.GetPrivate())
+		default:
+			return ConnectionString{}, &errors.ValidationError{Message: fmt.Sprintf("unknown connection string type %q", t)}
+		}
+		if uri != "" {
+			return ConnectionString{Type: t, URI: uri}, nil
+		}
+	}
+	return ConnectionString{}, &errors.NotFoundError{Resource: "connection string", ID: strings.Join(types, ",")}
+}
+
+// privateEndpointURI returns the connection string of the private endpoint that includes
+// endpointID, or of the first private endpoint if endpointID is empty.
+func privateEndpointURI(endpoints []admin.ClusterDescriptionConnectionStringsPrivateEndpoint, endpointID string) string {
+	for _, pe := range endpoints {
+		if endpointID != "" && !slices.ContainsFunc(pe.GetEndpoints(), func(e admin.ClusterDescriptionConnectionStringsPrivateEndpointEndpoint) bool {
+			return e.GetEndpointId() == endpointID
+		}) {
+			continue
+		}
+		if uri := cmp.Or(pe.GetSrvConnectionString(), pe.GetConnectionString()); uri != "" {
+			return uri
+		}
+	}
+	return ""
+}
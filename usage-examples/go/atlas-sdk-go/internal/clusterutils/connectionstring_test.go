@@ -0,0 +1,57 @@
+package clusterutils
+
+import (
+	"testing"
+
+	internalerrors "atlas-sdk-go/internal/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/atlas-sdk/v20250219001/admin"
+)
+
+func TestResolveConnectionString(t *testing.T) {
+	t.Parallel()
+	cs := admin.ClusterConnectionStrings{
+		Standard:    admin.PtrString("mongodb://cluster0-shard-00-00.ab1cd.mongodb.net:27017"),
+		StandardSrv: admin.PtrString("mongodb+srv://cluster0.ab1cd.mongodb.net"),
+		Private:     admin.PtrString("mongodb://cluster0-shard-00-00-pri.ab1cd.mongodb.net:27017"),
+		PrivateEndpoint: &[]admin.ClusterDescriptionConnectionStringsPrivateEndpoint{
+			{
+				SrvConnectionString: admin.PtrString("mongodb+srv://cluster0-pl-0.ab1cd.mongodb.net"),
+				Endpoints:           &[]admin.ClusterDescriptionConnectionStringsPrivateEndpointEndpoint{{EndpointId: admin.PtrString("vpce-111")}},
+			},
+			{
+				SrvConnectionString: admin.PtrString("mongodb+srv://cluster0-pl-1.ab1cd.mongodb.net"),
+				Endpoints:           &[]admin.ClusterDescriptionConnectionStringsPrivateEndpointEndpoint{{EndpointId: admin.PtrString("vpce-222")}},
+			},
+		},
+	}
+	cases := []struct {
+		name       string
+		types      []string
+		endpointID string
+		expect     ConnectionString
+	}{
+		{"default_prefers_standard_srv", nil, "", ConnectionString{ConnectionStandard, "mongodb+srv://cluster0.ab1cd.mongodb.net"}},
+		{"private_endpoint_first", []string{ConnectionPrivateEndpoint}, "", ConnectionString{ConnectionPrivateEndpoint, "mongodb+srv://cluster0-pl-0.ab1cd.mongodb.net"}},
+		{"private_endpoint_by_id", []string{ConnectionPrivateEndpoint}, "vpce-222", ConnectionString{ConnectionPrivateEndpoint, "mongodb+srv://cluster0-pl-1.ab1cd.mongodb.net"}},
+		{"falls_through_to_private", []string{ConnectionPrivateEndpoint, ConnectionPrivate}, "vpce-999", ConnectionString{ConnectionPrivate, "mongodb://cluster0-shard-00-00-pri.ab1cd.mongodb.net:27017"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ResolveConnectionString(cs, c.types, c.endpointID)
+			require.NoError(t, err)
+			assert.Equal(t, c.expect, got)
+		})
+	}
+
+	_, err := ResolveConnectionString(admin.ClusterConnectionStrings{StandardSrv: admin.PtrString("x")}, []string{ConnectionPrivateEndpoint}, "")
+	var nfErr *internalerrors.NotFoundError
+	assert.ErrorAs(t, err, &nfErr, "only private endpoint allowed but none configured")
+
+	_, err = ResolveConnectionString(cs, []string{"vpn"}, "")
+	var vErr *internalerrors.ValidationError
+	assert.ErrorAs(t, err, &vErr)
+}
@@ -0,0 +1,77 @@
+package clusterutils
+
+import (
+	"context"
+	"iter"
+
+	"atlas-sdk-go/internal/errors"
+	"atlas-sdk-go/internal/pagination"
+
+	"go.mongodb.org/atlas-sdk/v20250219001/admin"
+)
+
+// IterateClusters returns an iterator over every cluster in a project, requesting additional
+// pages until the reported total count is reached. PageNum and ItemsPerPage on p, if set,
+// control the first page and the page size. The iterator stops early if the context is cancelled.
+func IterateClusters(ctx context.Context, sdk admin.ClustersApi, p *admin.ListClustersApiParams) iter.Seq2[admin.ClusterDescription20240805, error] {
+	fetch := func(ctx context.Context, pageNum, itemsPerPage int) ([]admin.ClusterDescription20240805, int, error) {
+		req := sdk.ListClusters(ctx, p.GroupId).
+			PageNum(pageNum).
+			ItemsPerPage(itemsPerPage).
+			IncludeCount(true)
+		if p.IncludeDeletedWithRetainedBackups != nil {
+			req = req.IncludeDeletedWithRetainedBackups(*p.IncludeDeletedWithRetainedBackups)
+		}
+
+		r, _, err := req.Execute()
+		if err != nil {
+			return nil, 0, errors.FormatError("list clusters", p.GroupId, err)
+		}
+		return r.GetResults(), r.GetTotalCount(), nil
+	}
+	return pagination.Iterate(ctx, fetch, valueOrZero(p.PageNum), valueOrZero(p.ItemsPerPage))
+}
+
+// ListAllClusters collects every page of clusters in a project.
+func ListAllClusters(ctx context.Context, sdk admin.ClustersApi, p *admin.ListClustersApiParams) ([]admin.ClusterDescription20240805, error) {
+	clusters, err := pagination.Collect(IterateClusters(ctx, sdk, p))
+	if err != nil {
+		return nil, err
+	}
+	return clusters, nil
+}
+
+// IterateProcesses returns an iterator over every Atlas process in a project, requesting
+// additional pages until the reported total count is reached. PageNum and ItemsPerPage on p,
+// if set, control the first page and the page size. The iterator stops early if the context is cancelled.
+func IterateProcesses(ctx context.Context, sdk admin.MonitoringAndLogsApi, p *admin.ListAtlasProcessesApiParams) iter.Seq2[admin.ApiHostViewAtlas, error] {
+	fetch := func(ctx context.Context, pageNum, itemsPerPage int) ([]admin.ApiHostViewAtlas, int, error) {
+		r, _, err := sdk.ListAtlasProcesses(ctx, p.GroupId).
+			PageNum(pageNum).
+			ItemsPerPage(itemsPerPage).
+			IncludeCount(true).
+			Execute()
+		if err != nil {
+			return nil, 0, errors.FormatError("list atlas processes", p.GroupId, err)
+		}
+		return r.GetResults(), r.GetTotalCount(), nil
+	}
+	return pagination.Iterate(ctx, fetch, valueOrZero(p.PageNum), valueOrZero(p.ItemsPerPage))
+}
+
+// ListAllProcesses collects every page of Atlas processes in a project.
+func ListAllProcesses(ctx context.Context, sdk admin.MonitoringAndLogsApi, p *admin.ListAtlasProcessesApiParams) ([]admin.ApiHostViewAtlas, error) {
+	processes, err := pagination.Collect(IterateProcesses(ctx, sdk, p))
+	if err != nil {
+		return nil, err
+	}
+	return processes, nil
+}
+
+// valueOrZero returns the dereferenced int pointer or zero.
+func valueOrZero(p *int) int {
+	if p == nil {
+		return 0
+	}
+	return *p
+}
@@ -0,0 +1,88 @@
+package clusterutils
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/atlas-sdk/v20250219001/admin"
+	"go.mongodb.org/atlas-sdk/v20250219001/mockadmin"
+)
+
+func TestListAllClusters_MultiplePages(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	groupID := "group123"
+
+	page1 := &admin.PaginatedClusterDescription20240805{
+		Results:    &[]admin.ClusterDescription20240805{{Name: admin.PtrString("A")}, {Name: admin.PtrString("B")}},
+		TotalCount: admin.PtrInt(3),
+	}
+	page2 := &admin.PaginatedClusterDescription20240805{
+		Results:    &[]admin.ClusterDescription20240805{{Name: admin.PtrString("C")}},
+		TotalCount: admin.PtrInt(3),
+	}
+
+	mockSvc := mockadmin.NewClustersApi(t)
+	mockSvc.EXPECT().
+		ListClusters(mock.Anything, groupID).
+		Return(admin.ListClustersApiRequest{ApiService: mockSvc}).Times(2)
+	mockSvc.EXPECT().ListClustersExecute(mock.Anything).Return(page1, nil, nil).Once()
+	mockSvc.EXPECT().ListClustersExecute(mock.Anything).Return(page2, nil, nil).Once()
+
+	params := &admin.ListClustersApiParams{GroupId: groupID, ItemsPerPage: admin.PtrInt(2)}
+	result, err := ListAllClusters(ctx, mockSvc, params)
+
+	require.NoError(t, err)
+	require.Len(t, result, 3)
+	assert.Equal(t, "C", result[2].GetName())
+}
+
+func TestListAllProcesses_MultiplePages(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	groupID := "group123"
+
+	page1 := &admin.PaginatedHostViewAtlas{
+		Results:    &[]admin.ApiHostViewAtlas{{Id: admin.PtrString("h1:27017")}},
+		TotalCount: admin.PtrInt(2),
+	}
+	page2 := &admin.PaginatedHostViewAtlas{
+		Results:    &[]admin.ApiHostViewAtlas{{Id: admin.PtrString("h2:27017")}},
+		TotalCount: admin.PtrInt(2),
+	}
+
+	mockSvc := mockadmin.NewMonitoringAndLogsApi(t)
+	mockSvc.EXPECT().
+		ListAtlasProcesses(mock.Anything, groupID).
+		Return(admin.ListAtlasProcessesApiRequest{ApiService: mockSvc}).Times(2)
+	mockSvc.EXPECT().ListAtlasProcessesExecute(mock.Anything).Return(page1, nil, nil).Once()
+	mockSvc.EXPECT().ListAtlasProcessesExecute(mock.Anything).Return(page2, nil, nil).Once()
+
+	params := &admin.ListAtlasProcessesApiParams{GroupId: groupID, ItemsPerPage: admin.PtrInt(1)}
+	result, err := ListAllProcesses(ctx, mockSvc, params)
+
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	assert.Equal(t, "h2:27017", result[1].GetId())
+}
+
+func TestListAllProcesses_ApiError(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	groupID := "group123"
+
+	mockSvc := mockadmin.NewMonitoringAndLogsApi(t)
+	mockSvc.EXPECT().
+		ListAtlasProcesses(mock.Anything, groupID).
+		Return(admin.ListAtlasProcessesApiRequest{ApiService: mockSvc}).Once()
+	mockSvc.EXPECT().ListAtlasProcessesExecute(mock.Anything).Return(nil, nil, assert.AnError).Once()
+
+	result, err := ListAllProcesses(ctx, mockSvc, &admin.ListAtlasProcessesApiParams{GroupId: groupID})
+
+	require.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "list atlas processes")
+}
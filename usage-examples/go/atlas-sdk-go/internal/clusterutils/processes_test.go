@@ -2,8 +2,10 @@ package clusterutils
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -90,85 +92,76 @@ func TestListClusterNames_NoClusters(t *testing.T) {
 	require.NoError(t, err, "No error should be returned when no clusters exist")
 }
 
-func TestGetProcessIdForCluster_Success(t *testing.T) {
-	t.Parallel()
-	ctx := context.Background()
-	groupID := "group123"
-	clusterName := "host"
-	processID := "host:27017"
+// topologyHandler serves a single cluster description and the project's processes.
+func topologyHandler(t *testing.T, cluster admin.ClusterDescription20240805, processes []admin.ApiHostViewAtlas) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var body any
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/clusters/"+cluster.GetName()):
+			body = cluster
+		case strings.HasSuffix(r.URL.Path, "/processes"):
+			body = admin.PaginatedHostViewAtlas{Results: &processes, TotalCount: admin.PtrInt(len(processes))}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errorCode":"CLUSTER_NOT_FOUND","error":404}`))
+			return
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(body))
+	}
+}
 
-	mockResponse := &admin.PaginatedHostViewAtlas{
-		Results: &[]admin.ApiHostViewAtlas{
-			{
-				GroupId:   admin.PtrString(groupID),
-				Id:        admin.PtrString(processID),
-				UserAlias: admin.PtrString(clusterName),
-			},
+func TestGetProcessIdForCluster_ReturnsPrimary(t *testing.T) {
+	t.Parallel()
+	// Atlas shortens long cluster names in host names, so only the topology links them
+	cluster := admin.ClusterDescription20240805{
+		Name: admin.PtrString("orders-analytics-production-cluster"),
+		ConnectionStrings: &admin.ClusterConnectionStrings{
+			Standard: admin.PtrString("mongodb://orders-analytics-p-shard-00-00.ab1.mongodb.net:27017,orders-analytics-p-shard-00-01.ab1.mongodb.net:27017/?replicaSet=atlas-ccc333-shard-0"),
 		},
 	}
+	processes := []admin.ApiHostViewAtlas{
+		process("orders-analytics-p-shard-00-01.ab1.mongodb.net", "atlas-ccc333-shard-00-01.ab1.mongodb.net", 27017, "atlas-ccc333-shard-0", "REPLICA_SECONDARY"),
+		process("orders-analytics-p-shard-00-00.ab1.mongodb.net", "atlas-ccc333-shard-00-00.ab1.mongodb.net", 27017, "atlas-ccc333-shard-0", "REPLICA_PRIMARY"),
+	}
+	client := newTestAtlasClient(t, topologyHandler(t, cluster, processes))
 
-	mockSvc := mockadmin.NewMonitoringAndLogsApi(t)
-	mockSvc.EXPECT().
-		ListAtlasProcesses(mock.Anything, groupID).
-		Return(admin.ListAtlasProcessesApiRequest{ApiService: mockSvc}).Once()
-	mockSvc.EXPECT().
-		ListAtlasProcessesExecute(mock.Anything).
-		Return(mockResponse, nil, nil).Once()
-
-	params := &admin.ListAtlasProcessesApiParams{GroupId: groupID}
-	result, err := GetProcessIdForCluster(ctx, mockSvc, params, clusterName)
+	id, err := GetProcessIdForCluster(context.Background(), client, "proj1", cluster.GetName())
 
 	require.NoError(t, err)
-	require.NotNil(t, result)
-	require.Equal(t, processID, result, "Process ID should match expected value")
+	assert.Equal(t, "atlas-ccc333-shard-00-00.ab1.mongodb.net:27017", id, "the primary should be chosen over a secondary listed first")
 }
 
-func TestGetProcessIdForCluster_ApiError(t *testing.T) {
+func TestGetProcessIdForCluster_NoPrimary(t *testing.T) {
 	t.Parallel()
-	ctx := context.Background()
-	groupID := "group123"
-	clusterName := "host"
-
-	mockSvc := mockadmin.NewMonitoringAndLogsApi(t)
-	mockSvc.EXPECT().
-		ListAtlasProcesses(mock.Anything, groupID).
-		Return(admin.ListAtlasProcessesApiRequest{ApiService: mockSvc}).Once()
-	mockSvc.EXPECT().
-		ListAtlasProcessesExecute(mock.Anything).
-		Return(nil, nil, assert.AnError).Once()
+	app, _, processes := prefixClusters()
+	var secondaries []admin.ApiHostViewAtlas
+	for _, p := range processes {
+		if p.GetTypeName() == "REPLICA_SECONDARY" {
+			secondaries = append(secondaries, p)
+		}
+	}
+	client := newTestAtlasClient(t, topologyHandler(t, app, secondaries))
 
-	params := &admin.ListAtlasProcessesApiParams{GroupId: groupID}
-	result, err := GetProcessIdForCluster(ctx, mockSvc, params, clusterName)
+	id, err := GetProcessIdForCluster(context.Background(), client, "proj1", "app")
 
-	require.Error(t, err)
-	require.Emptyf(t, result, "Process ID should be empty on error")
-	assert.Contains(t, err.Error(), "list atlas processes", "Should return formatted error when API call fails")
+	require.NoError(t, err)
+	assert.Empty(t, id, "no primary should return an empty ID and no error")
 }
 
-func TestGetProcessIdForCluster_NoProcesses(t *testing.T) {
+func TestGetProcessIdForCluster_Errors(t *testing.T) {
 	t.Parallel()
-	ctx := context.Background()
-	groupID := "group123"
-	clusterName := "host"
-
-	// Create empty response
-	mockResponse := &admin.PaginatedHostViewAtlas{
-		Results: &[]admin.ApiHostViewAtlas{},
-	}
-
-	mockSvc := mockadmin.NewMonitoringAndLogsApi(t)
-	mockSvc.EXPECT().
-		ListAtlasProcesses(mock.Anything, groupID).
-		Return(admin.ListAtlasProcessesApiRequest{ApiService: mockSvc}).Once()
-	mockSvc.EXPECT().
-		ListAtlasProcessesExecute(mock.Anything).
-		Return(mockResponse, nil, nil).Once()
+	app, _, processes := prefixClusters()
+	client := newTestAtlasClient(t, topologyHandler(t, app, processes))
 
-	params := &admin.ListAtlasProcessesApiParams{GroupId: groupID}
-	result, err := GetProcessIdForCluster(ctx, mockSvc, params, clusterName)
+	_, err := GetProcessIdForCluster(context.Background(), client, "proj1", "missing")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "get cluster")
 
-	require.Emptyf(t, result, "Process ID should be empty when no processes exist")
-	require.NoError(t, err, "No error should be returned when no processes exist")
+	_, err = GetProcessIdForCluster(context.Background(), client, "", "app")
+	require.Error(t, err)
+	_, err = GetProcessIdForCluster(context.Background(), client, "proj1", "")
+	require.Error(t, err)
 }
 
 func newTestAtlasClient(t *testing.T, handler http.HandlerFunc) *admin.APIClient {
@@ -0,0 +1,33 @@
+package clusterutils
+
+// Atlas process type names, as reported in ApiHostViewAtlas.TypeName. Members of a sharded cluster's
+// shards report SHARD_PRIMARY and SHARD_SECONDARY; config servers report SHARD_CONFIG whatever their
+// replica set state.
+const (
+	RoleReplicaPrimary   = "REPLICA_PRIMARY"
+	RoleReplicaSecondary = "REPLICA_SECONDARY"
+	RoleShardPrimary     = "SHARD_PRIMARY"
+	RoleShardSecondary   = "SHARD_SECONDARY"
+	RoleConfig           = "SHARD_CONFIG"
+	RoleMongos           = "SHARD_MONGOS"
+)
+
+// IsPrimary reports whether an Atlas type name is a replica set or shard primary.
+func IsPrimary(role string) bool {
+	return role == RoleReplicaPrimary || role == RoleShardPrimary
+}
+
+// IsSecondary reports whether an Atlas type name is a replica set or shard secondary.
+func IsSecondary(role string) bool {
+	return role == RoleReplicaSecondary || role == RoleShardSecondary
+}
+
+// IsConfig reports whether an Atlas type name is a config server.
+func IsConfig(role string) bool {
+	return role == RoleConfig
+}
+
+// IsMongos reports whether an Atlas type name is a mongos router.
+func IsMongos(role string) bool {
+	return role == RoleMongos
+}
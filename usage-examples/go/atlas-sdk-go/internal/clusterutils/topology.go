@@ -0,0 +1,361 @@
+package clusterutils
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/atlas-sdk/v20250219001/admin"
+
+	"atlas-sdk-go/internal/errors"
+)
+
+// NodeType is the kind of replica set member configured in a cluster's region configs.
+type NodeType string
+
+// Node types of replica set members
+const (
+	NodeElectable NodeType = "ELECTABLE"
+	NodeReadOnly  NodeType = "READ_ONLY"
+	NodeAnalytics NodeType = "ANALYTICS"
+)
+
+// Member is a single mongod or mongos process of a cluster.
+type Member struct {
+	ID         string `json:"id"` // hostname:port
+	Hostname   string `json:"hostname"`
+	UserAlias  string `json:"userAlias,omitempty"`
+	Port       int    `json:"port"`
+	Role       string `json:"role"` // Atlas typeName e.g. REPLICA_PRIMARY, SHARD_SECONDARY, SHARD_MONGOS
+	ReplicaSet string `json:"replicaSet,omitempty"`
+	Version    string `json:"version,omitempty"`
+	// Provider, Region, and NodeType are best-effort: Atlas does not report them per process, so they
+	// are inferred from the replication spec (see DiscoverTopology) and may not match the actual node.
+	Provider string   `json:"provider,omitempty"`
+	Region   string   `json:"region,omitempty"`
+	NodeType NodeType `json:"nodeType,omitempty"`
+}
+
+// ReplicaSet is a shard or config server replica set and its members.
+type ReplicaSet struct {
+	Name    string   `json:"name"`
+	Members []Member `json:"members"`
+}
+
+// Topology describes the processes that make up a cluster.
+// A replica set cluster has a single shard and no config servers or mongos.
+type Topology struct {
+	Cluster       string       `json:"cluster"`
+	ClusterType   string       `json:"clusterType,omitempty"` // REPLICASET, SHARDED, or GEOSHARDED
+	Shards        []ReplicaSet `json:"shards"`
+	ConfigServers *ReplicaSet  `json:"configServers,omitempty"`
+	Mongos        []Member     `json:"mongos,omitempty"`
+}
+
+// Members returns every process in the topology: shard members, then config servers, then mongos.
+func (t *Topology) Members() []Member {
+	var out []Member
+	for _, s := range t.Shards {
+		out = append(out, s.Members...)
+	}
+	if t.ConfigServers != nil {
+		out = append(out, t.ConfigServers.Members...)
+	}
+	return append(out, t.Mongos...)
+}
+
+// Primaries returns the primary of each shard, in shard order.
+func (t *Topology) Primaries() []Member {
+	var out []Member
+	for _, s := range t.Shards {
+		for _, m := range s.Members {
+			if IsPrimary(m.Role) {
+				out = append(out, m)
+			}
+		}
+	}
+	return out
+}
+
+// GetClusterTopology returns the topology of a single cluster.
+func GetClusterTopology(ctx context.Context, client *admin.APIClient, projectID, clusterName string) (*Topology, error) {
+	if client == nil {
+		return nil, fmt.Errorf("nil atlas api client")
+	}
+	cluster, _, err := client.ClustersApi.GetCluster(ctx, projectID, clusterName).Execute()
+	if err != nil {
+		return nil, errors.FormatError("get cluster", projectID, err)
+	}
+	processes, err := ListAllProcesses(ctx, client.MonitoringAndLogsApi, &admin.ListAtlasProcessesApiParams{GroupId: projectID})
+	if err != nil {
+		return nil, err
+	}
+	return DiscoverTopology(*cluster, processes), nil
+}
+
+// ListClusterTopologies returns the topology of every cluster in a project, keyed by cluster name.
+func ListClusterTopologies(ctx context.Context, client *admin.APIClient, projectID string) (map[string]*Topology, error) {
+	if client == nil {
+		return nil, fmt.Errorf("nil atlas api client")
+	}
+	clusters, err := ListAllClusters(ctx, client.ClustersApi, &admin.ListClustersApiParams{GroupId: projectID})
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]*Topology, len(clusters))
+	if len(clusters) == 0 {
+		return out, nil
+	}
+	processes, err := ListAllProcesses(ctx, client.MonitoringAndLogsApi, &admin.ListAtlasProcessesApiParams{GroupId: projectID})
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range clusters {
+		out[c.GetName()] = DiscoverTopology(c, processes)
+	}
+	return out, nil
+}
+
+// DiscoverTopology selects the processes that belong to cluster and arranges them into shards,
+// config servers, and mongos.
+//
+// A process belongs to the cluster if its hostname or user alias is a host in one of the
+// cluster's standard, private, or private endpoint connection strings, or if it is a member of
+// a replica set of such a process or of the same cluster (Atlas names a cluster's replica sets
+// "<prefix>-shard-<n>" and "<prefix>-config-<n>"). Mongos processes running on a member's host
+// are included too.
+//
+// Atlas does not report a member's provider, region, or node type, so they are a best-effort
+// inference from the matching replication spec: members, in host order, fill the electable nodes
+// of each region config in priority order, then the read-only nodes, then the analytics nodes.
+// Host numbering does not always follow that order (e.g. after nodes are added to a region), so
+// treat these fields as a hint rather than as the member's actual placement.
+func DiscoverTopology(cluster admin.ClusterDescription20240805, processes []admin.ApiHostViewAtlas) *Topology {
+	hosts, replicaSets := connectionStringHosts(cluster.GetConnectionStrings())
+
+	// Seed with processes named in the connection strings, then widen to their replica sets
+	var matched []admin.ApiHostViewAtlas
+	seen := make(map[string]bool)
+	add := func(p admin.ApiHostViewAtlas) {
+		if id := p.GetId(); id != "" && !seen[id] {
+			seen[id] = true
+			matched = append(matched, p)
+		}
+	}
+	for _, p := range processes {
+		if hosts[strings.ToLower(p.GetHostname())] || hosts[strings.ToLower(p.GetUserAlias())] {
+			add(p)
+			if rs := p.GetReplicaSetName(); rs != "" {
+				replicaSets[rs] = true
+			}
+		}
+	}
+	prefixes := make(map[string]bool)
+	for rs := range replicaSets {
+		if prefix, ok := replicaSetPrefix(rs); ok {
+			prefixes[prefix] = true
+		}
+	}
+	for _, p := range processes {
+		rs := p.GetReplicaSetName()
+		prefix, ok := replicaSetPrefix(rs)
+		if replicaSets[rs] || (ok && prefixes[prefix]) {
+			add(p)
+		}
+	}
+	memberHosts := make(map[string]bool)
+	for _, p := range matched {
+		memberHosts[strings.ToLower(p.GetHostname())] = true
+	}
+	for _, p := range processes {
+		if IsMongos(p.GetTypeName()) && memberHosts[strings.ToLower(p.GetHostname())] {
+			add(p)
+		}
+	}
+
+	return buildTopology(cluster, matched)
+}
+
+// buildTopology groups the processes of a cluster by role and replica set.
+func buildTopology(cluster admin.ClusterDescription20240805, processes []admin.ApiHostViewAtlas) *Topology {
+	t := &Topology{Cluster: cluster.GetName(), ClusterType: cluster.GetClusterType()}
+	shards := make(map[string][]Member)
+	var config []Member
+	for _, p := range processes {
+		m := Member{
+			ID:         p.GetId(),
+			Hostname:   p.GetHostname(),
+			UserAlias:  p.GetUserAlias(),
+			Port:       p.GetPort(),
+			Role:       p.GetTypeName(),
+			ReplicaSet: p.GetReplicaSetName(),
+			Version:    p.GetVersion(),
+		}
+		switch {
+		case IsMongos(m.Role):
+			t.Mongos = append(t.Mongos, m)
+		case IsConfig(m.Role) || configReplicaSet.MatchString(m.ReplicaSet):
+			config = append(config, m)
+		default:
+			shards[m.ReplicaSet] = append(shards[m.ReplicaSet], m)
+		}
+	}
+
+	specs := cluster.GetReplicationSpecs()
+	names := make([]string, 0, len(shards))
+	for name := range shards {
+		names = append(names, name)
+	}
+	slices.SortFunc(names, func(a, b string) int { return cmp.Or(cmp.Compare(shardIndex(a), shardIndex(b)), cmp.Compare(a, b)) })
+	for i, name := range names {
+		members := sortMembers(shards[name])
+		if len(specs) > 0 {
+			spec := specs[min(i, len(specs)-1)]
+			if n := shardIndex(name); n >= 0 && n < len(specs) {
+				spec = specs[n]
+			}
+			assignPlacement(members, spec, true)
+		}
+		t.Shards = append(t.Shards, ReplicaSet{Name: name, Members: members})
+	}
+	if len(config) > 0 {
+		members := sortMembers(config)
+		if len(specs) > 0 {
+			assignPlacement(members, specs[0], false)
+		}
+		t.ConfigServers = &ReplicaSet{Name: members[0].ReplicaSet, Members: members}
+	}
+
+	// Mongos take the placement of the member on the same host
+	placement := make(map[string]Member)
+	for _, m := range t.Members() {
+		if !IsMongos(m.Role) && m.Region != "" {
+			placement[strings.ToLower(m.Hostname)] = m
+		}
+	}
+	t.Mongos = sortMembers(t.Mongos)
+	for i := range t.Mongos {
+		if p, ok := placement[strings.ToLower(t.Mongos[i].Hostname)]; ok {
+			t.Mongos[i].Provider, t.Mongos[i].Region, t.Mongos[i].NodeType = p.Provider, p.Region, p.NodeType
+		}
+	}
+	return t
+}
+
+// assignPlacement sets a best-effort provider, region, and node type on members from the node counts
+// of spec, by position. If allNodes is false, only electable nodes are assigned (config servers).
+func assignPlacement(members []Member, spec admin.ReplicationSpec20240805, allNodes bool) {
+	type slot struct {
+		provider, region string
+		nodeType         NodeType
+	}
+	rcs := slices.Clone(spec.GetRegionConfigs())
+	slices.SortStableFunc(rcs, func(a, b admin.CloudRegionConfig20240805) int { return b.GetPriority() - a.GetPriority() })
+
+	var slots []slot
+	appendSlots := func(nodeType NodeType, count func(rc admin.CloudRegionConfig20240805) int) {
+		for _, rc := range rcs {
+			for range count(rc) {
+				slots = append(slots, slot{rc.GetProviderName(), rc.GetRegionName(), nodeType})
+			}
+		}
+	}
+	appendSlots(NodeElectable, func(rc admin.CloudRegionConfig20240805) int { return rc.ElectableSpecs.GetNodeCount() })
+	if allNodes {
+		appendSlots(NodeReadOnly, func(rc admin.CloudRegionConfig20240805) int { return rc.ReadOnlySpecs.GetNodeCount() })
+		appendSlots(NodeAnalytics, func(rc admin.CloudRegionConfig20240805) int { return rc.AnalyticsSpecs.GetNodeCount() })
+	}
+	for i := range members {
+		if i >= len(slots) {
+			break
+		}
+		members[i].Provider, members[i].Region, members[i].NodeType = slots[i].provider, slots[i].region, slots[i].nodeType
+	}
+}
+
+var (
+	shardReplicaSet  = regexp.MustCompile(`^(.+)-shard-(\d+)$`)
+	configReplicaSet = regexp.MustCompile(`^(.+)-config-(\d+)$`)
+)
+
+// replicaSetPrefix returns the cluster-specific prefix of an Atlas shard or config replica set name.
+func replicaSetPrefix(rs string) (string, bool) {
+	if m := shardReplicaSet.FindStringSubmatch(rs); m != nil {
+		return m[1], true
+	}
+	if m := configReplicaSet.FindStringSubmatch(rs); m != nil {
+		return m[1], true
+	}
+	return "", false
+}
+
+// shardIndex returns n for a replica set named "<prefix>-shard-<n>", or -1.
+func shardIndex(rs string) int {
+	if m := shardReplicaSet.FindStringSubmatch(rs); m != nil {
+		if n, err := strconv.Atoi(m[2]); err == nil {
+			return n
+		}
+	}
+	return -1
+}
+
+// sortMembers orders members by user alias (or hostname) and port, which follows the order
+// in which Atlas numbers a replica set's hosts.
+func sortMembers(members []Member) []Member {
+	slices.SortFunc(members, func(a, b Member) int {
+		return cmp.Or(
+			cmp.Compare(strings.ToLower(cmp.Or(a.UserAlias, a.Hostname)), strings.ToLower(cmp.Or(b.UserAlias, b.Hostname))),
+			cmp.Compare(a.Port, b.Port),
+		)
+	})
+	return members
+}
+
+// connectionStringHosts returns the lower-cased hosts and replicaSet options of the cluster's
+// non-SRV connection strings. SRV strings are skipped because their hosts are only known via DNS.
+func connectionStringHosts(cs admin.ClusterConnectionStrings) (map[string]bool, map[string]bool) {
+	uris := []string{cs.GetStandard(), cs.GetPrivate()}
+	for _, pe := range cs.GetPrivateEndpoint() {
+		uris = append(uris, pe.GetConnectionString())
+	}
+	for _, v := range cs.GetAwsPrivateLink() {
+		uris = append(uris, v)
+	}
+
+	hosts := make(map[string]bool)
+	replicaSets := make(map[string]bool)
+	for _, uri := range uris {
+		rest, ok := strings.CutPrefix(uri, "mongodb://")
+		if !ok {
+			continue
+		}
+		if i := strings.LastIndex(rest, "@"); i >= 0 {
+			rest = rest[i+1:]
+		}
+		hostList, query, _ := strings.Cut(rest, "?")
+		hostList, _, _ = strings.Cut(hostList, "/")
+		for _, h := range strings.Split(hostList, ",") {
+			if host := hostOnly(h); host != "" {
+				hosts[host] = true
+			}
+		}
+		if v, err := url.ParseQuery(query); err == nil && v.Get("replicaSet") != "" {
+			replicaSets[v.Get("replicaSet")] = true
+		}
+	}
+	return hosts, replicaSets
+}
+
+// hostOnly strips the port from host:port and lower-cases the result.
+func hostOnly(hostPort string) string {
+	h := strings.TrimSpace(hostPort)
+	if i := strings.LastIndex(h, ":"); i >= 0 && !strings.HasSuffix(h, "]") {
+		h = h[:i]
+	}
+	return strings.ToLower(strings.Trim(h, "[]"))
+}
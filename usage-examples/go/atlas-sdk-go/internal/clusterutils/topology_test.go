@@ -0,0 +1,200 @@
+package clusterutils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/atlas-sdk/v20250219001/admin"
+)
+
+func process(alias, host string, port int, rs, role string) admin.ApiHostViewAtlas {
+	p := admin.ApiHostViewAtlas{
+		Id:        admin.PtrString(fmt.Sprintf("%s:%d", host, port)),
+		Hostname:  admin.PtrString(host),
+		UserAlias: admin.PtrString(alias),
+		Port:      admin.PtrInt(port),
+		TypeName:  admin.PtrString(role),
+	}
+	if rs != "" {
+		p.ReplicaSetName = admin.PtrString(rs)
+	}
+	return p
+}
+
+func regionConfig(provider, region string, priority, electable, readOnly, analytics int) admin.CloudRegionConfig20240805 {
+	return admin.CloudRegionConfig20240805{
+		ProviderName:   admin.PtrString(provider),
+		RegionName:     admin.PtrString(region),
+		Priority:       admin.PtrInt(priority),
+		ElectableSpecs: &admin.HardwareSpec20240805{NodeCount: admin.PtrInt(electable)},
+		ReadOnlySpecs:  &admin.DedicatedHardwareSpec20240805{NodeCount: admin.PtrInt(readOnly)},
+		AnalyticsSpecs: &admin.DedicatedHardwareSpec20240805{NodeCount: admin.PtrInt(analytics)},
+	}
+}
+
+// Two replica set clusters where one name is a prefix of the other
+func prefixClusters() (admin.ClusterDescription20240805, admin.ClusterDescription20240805, []admin.ApiHostViewAtlas) {
+	app := admin.ClusterDescription20240805{
+		Name:        admin.PtrString("app"),
+		ClusterType: admin.PtrString("REPLICASET"),
+		ConnectionStrings: &admin.ClusterConnectionStrings{
+			Standard: admin.PtrString("mongodb://app-shard-00-00.ab1.mongodb.net:27017,app-shard-00-01.ab1.mongodb.net:27017/?ssl=true&replicaSet=atlas-aaa111-shard-0"),
+		},
+		ReplicationSpecs: &[]admin.ReplicationSpec20240805{{RegionConfigs: &[]admin.CloudRegionConfig20240805{
+			regionConfig("AWS", "US_EAST_1", 7, 2, 0, 0),
+			regionConfig("AWS", "US_WEST_2", 6, 0, 0, 1),
+		}}},
+	}
+	reporting := admin.ClusterDescription20240805{
+		Name: admin.PtrString("app-reporting"),
+		ConnectionStrings: &admin.ClusterConnectionStrings{
+			Standard: admin.PtrString("mongodb://app-reporting-shard-00-00.ab1.mongodb.net:27017/?replicaSet=atlas-bbb222-shard-0"),
+		},
+	}
+	processes := []admin.ApiHostViewAtlas{
+		process("app-reporting-shard-00-00.ab1.mongodb.net", "atlas-bbb222-shard-00-00.ab1.mongodb.net", 27017, "atlas-bbb222-shard-0", "REPLICA_PRIMARY"),
+		process("app-shard-00-01.ab1.mongodb.net", "atlas-aaa111-shard-00-01.ab1.mongodb.net", 27017, "atlas-aaa111-shard-0", "REPLICA_SECONDARY"),
+		process("app-shard-00-00.ab1.mongodb.net", "atlas-aaa111-shard-00-00.ab1.mongodb.net", 27017, "atlas-aaa111-shard-0", "REPLICA_PRIMARY"),
+		// Analytics node: not in the connection string, found through its replica set
+		process("app-shard-00-02.ab1.mongodb.net", "atlas-aaa111-shard-00-02.ab1.mongodb.net", 27017, "atlas-aaa111-shard-0", "REPLICA_SECONDARY"),
+		// Belongs to no cluster
+		process("stray.ab1.mongodb.net", "stray.ab1.mongodb.net", 27017, "other-rs", "REPLICA_PRIMARY"),
+	}
+	return app, reporting, processes
+}
+
+func TestDiscoverTopology_ReplicaSetWithPrefixName(t *testing.T) {
+	t.Parallel()
+	app, reporting, processes := prefixClusters()
+
+	topo := DiscoverTopology(app, processes)
+
+	require.Len(t, topo.Shards, 1)
+	assert.Nil(t, topo.ConfigServers)
+	assert.Empty(t, topo.Mongos)
+	rs := topo.Shards[0]
+	assert.Equal(t, "atlas-aaa111-shard-0", rs.Name)
+	require.Len(t, rs.Members, 3, "app-reporting and unrelated processes should be excluded")
+	assert.Equal(t, "atlas-aaa111-shard-00-00.ab1.mongodb.net:27017", rs.Members[0].ID)
+	assert.Equal(t, NodeElectable, rs.Members[1].NodeType)
+	assert.Equal(t, "US_EAST_1", rs.Members[1].Region)
+	assert.Equal(t, NodeAnalytics, rs.Members[2].NodeType)
+	assert.Equal(t, "US_WEST_2", rs.Members[2].Region)
+	require.Len(t, topo.Primaries(), 1)
+
+	other := DiscoverTopology(reporting, processes)
+	require.Len(t, other.Members(), 1)
+	assert.Equal(t, "atlas-bbb222-shard-00-00.ab1.mongodb.net:27017", other.Members()[0].ID)
+}
+
+func TestDiscoverTopology_ShardedCluster(t *testing.T) {
+	t.Parallel()
+	spec := admin.ReplicationSpec20240805{RegionConfigs: &[]admin.CloudRegionConfig20240805{
+		regionConfig("GCP", "CENTRAL_US", 7, 1, 1, 0),
+	}}
+	cluster := admin.ClusterDescription20240805{
+		Name:        admin.PtrString("Orders"),
+		ClusterType: admin.PtrString("SHARDED"),
+		ConnectionStrings: &admin.ClusterConnectionStrings{
+			Standard: admin.PtrString("mongodb://orders-shard-00-00.cd2.mongodb.net:27016,orders-shard-01-00.cd2.mongodb.net:27016/?ssl=true"),
+		},
+		ReplicationSpecs: &[]admin.ReplicationSpec20240805{spec, spec},
+	}
+	processes := []admin.ApiHostViewAtlas{
+		process("orders-shard-00-00.cd2.mongodb.net", "atlas-ccc333-shard-00-00.cd2.mongodb.net", 27016, "", "SHARD_MONGOS"),
+		process("orders-shard-01-00.cd2.mongodb.net", "atlas-ccc333-shard-01-00.cd2.mongodb.net", 27016, "", "SHARD_MONGOS"),
+		process("orders-shard-00-00.cd2.mongodb.net", "atlas-ccc333-shard-00-00.cd2.mongodb.net", 27017, "atlas-ccc333-shard-0", "SHARD_PRIMARY"),
+		process("orders-shard-00-01.cd2.mongodb.net", "atlas-ccc333-shard-00-01.cd2.mongodb.net", 27017, "atlas-ccc333-shard-0", "SHARD_SECONDARY"),
+		process("orders-shard-01-00.cd2.mongodb.net", "atlas-ccc333-shard-01-00.cd2.mongodb.net", 27017, "atlas-ccc333-shard-1", "SHARD_PRIMARY"),
+		process("orders-shard-01-01.cd2.mongodb.net", "atlas-ccc333-shard-01-01.cd2.mongodb.net", 27017, "atlas-ccc333-shard-1", "SHARD_SECONDARY"),
+		process("orders-config-00-00.cd2.mongodb.net", "atlas-ccc333-config-00-00.cd2.mongodb.net", 27017, "atlas-ccc333-config-0", "SHARD_CONFIG"),
+	}
+
+	topo := DiscoverTopology(cluster, processes)
+
+	require.Len(t, topo.Shards, 2)
+	assert.Equal(t, "atlas-ccc333-shard-0", topo.Shards[0].Name)
+	assert.Equal(t, "atlas-ccc333-shard-1", topo.Shards[1].Name)
+	assert.Equal(t, NodeReadOnly, topo.Shards[1].Members[1].NodeType)
+	require.NotNil(t, topo.ConfigServers)
+	assert.Equal(t, "atlas-ccc333-config-0", topo.ConfigServers.Name)
+	assert.Equal(t, NodeElectable, topo.ConfigServers.Members[0].NodeType)
+	require.Len(t, topo.Mongos, 2)
+	assert.Equal(t, "CENTRAL_US", topo.Mongos[0].Region, "mongos should take the placement of the member on its host")
+	assert.Len(t, topo.Members(), 7)
+	primaries := topo.Primaries()
+	require.Len(t, primaries, 2, "shard members report SHARD_PRIMARY")
+	assert.Equal(t, "atlas-ccc333-shard-00-00.cd2.mongodb.net:27017", primaries[0].ID)
+	assert.Equal(t, "atlas-ccc333-shard-01-00.cd2.mongodb.net:27017", primaries[1].ID)
+}
+
+func TestRolePredicates(t *testing.T) {
+	t.Parallel()
+	for _, role := range []string{"REPLICA_PRIMARY", "SHARD_PRIMARY"} {
+		assert.True(t, IsPrimary(role), role)
+		assert.False(t, IsSecondary(role), role)
+	}
+	for _, role := range []string{"REPLICA_SECONDARY", "SHARD_SECONDARY"} {
+		assert.True(t, IsSecondary(role), role)
+		assert.False(t, IsPrimary(role), role)
+	}
+	assert.True(t, IsConfig("SHARD_CONFIG"))
+	assert.False(t, IsPrimary("SHARD_CONFIG"))
+	assert.False(t, IsSecondary("SHARD_CONFIG"))
+	assert.True(t, IsMongos("SHARD_MONGOS"))
+	for _, role := range []string{"RECOVERING", "NO_DATA", "SHARD_STANDALONE"} {
+		assert.False(t, IsPrimary(role) || IsSecondary(role) || IsConfig(role) || IsMongos(role), role)
+	}
+}
+
+func TestListClusterProcessDetails_NoSingleClusterFallback(t *testing.T) {
+	t.Parallel()
+	app, _, processes := prefixClusters()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var body any
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/clusters"):
+			body = admin.PaginatedClusterDescription20240805{Results: &[]admin.ClusterDescription20240805{app}, TotalCount: admin.PtrInt(1)}
+		case strings.HasSuffix(r.URL.Path, "/processes"):
+			body = admin.PaginatedHostViewAtlas{Results: &processes, TotalCount: admin.PtrInt(len(processes))}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(body))
+	}
+	client := newTestAtlasClient(t, handler)
+
+	details, err := ListClusterProcessDetails(context.Background(), client, "proj1")
+
+	require.NoError(t, err)
+	require.Len(t, details["app"], 3, "processes of other clusters should not be attributed to the only cluster")
+	id, ok := GetPrimaryProcessID(details["app"])
+	assert.True(t, ok)
+	assert.Equal(t, "atlas-aaa111-shard-00-00.ab1.mongodb.net:27017", id)
+	assert.Equal(t, "atlas-aaa111-shard-0", details["app"][0].ReplicaSet)
+}
+
+func TestConnectionStringHosts(t *testing.T) {
+	t.Parallel()
+	cs := admin.ClusterConnectionStrings{
+		Standard:    admin.PtrString("mongodb://user:pw@Host-A.example.net:27017,host-b.example.net/admin?replicaSet=rs0&ssl=true"),
+		StandardSrv: admin.PtrString("mongodb+srv://cluster0.example.net"),
+		PrivateEndpoint: &[]admin.ClusterDescriptionConnectionStringsPrivateEndpoint{{
+			ConnectionString: admin.PtrString("mongodb://pl-0-us-east-1.example.net:1024"),
+		}},
+	}
+
+	hosts, replicaSets := connectionStringHosts(cs)
+
+	assert.Equal(t, map[string]bool{"host-a.example.net": true, "host-b.example.net": true, "pl-0-us-east-1.example.net": true}, hosts)
+	assert.Equal(t, map[string]bool{"rs0": true}, replicaSets)
+}
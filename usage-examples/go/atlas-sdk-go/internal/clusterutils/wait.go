@@ -0,0 +1,229 @@
+package clusterutils
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"atlas-sdk-go/internal/errors"
+
+	"go.mongodb.org/atlas-sdk/v20250219001/admin"
+)
+
+// Cluster states reported in the stateName field of a cluster description.
+const (
+	StateIdle      = "IDLE"
+	StateCreating  = "CREATING"
+	StateUpdating  = "UPDATING"
+	StateRepairing = "REPAIRING"
+	StateDeleting  = "DELETING"
+	StateDeleted   = "DELETED"
+)
+
+// WaitOptions controls how WaitForState polls a cluster.
+type WaitOptions struct {
+	// TargetState is the state to wait for. Defaults to IDLE.
+	TargetState string
+	// Timeout bounds the whole wait. Zero or less means wait until the context is done.
+	Timeout time.Duration
+	// InitialInterval is the delay before the second poll. It doubles after each poll up to MaxInterval.
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+	// AwaitTransition, if positive, accepts the target state only after the cluster has been seen in
+	// another state or AwaitTransition has elapsed. Set it when waiting right after an update, since
+	// Atlas can still report the old IDLE state before the change starts.
+	AwaitTransition time.Duration
+	// Progress, if set, is called after every poll.
+	Progress func(WaitProgress)
+}
+
+// DefaultAwaitTransition is how long to wait for an updated cluster to leave its current state.
+const DefaultAwaitTransition = 2 * time.Minute
+
+// DefaultWaitOptions waits up to 30 minutes for a cluster to become IDLE, polling after
+// 5 seconds and backing off to once a minute.
+func DefaultWaitOptions() WaitOptions {
+	return WaitOptions{
+		TargetState:     StateIdle,
+		Timeout:         30 * time.Minute,
+		InitialInterval: 5 * time.Second,
+		MaxInterval:     time.Minute,
+	}
+}
+
+// WaitProgress describes a single poll of a cluster's state.
+type WaitProgress struct {
+	Cluster       string
+	State         string
+	PreviousState string
+	// Changed is true on the first poll and whenever State differs from PreviousState.
+	Changed bool
+	Elapsed time.Duration
+	Polls   int
+}
+
+// WaitResult is the outcome of waiting on one cluster.
+type WaitResult struct {
+	Cluster string
+	// State is the last state observed, or empty if the cluster was never read.
+	State   string
+	Elapsed time.Duration
+	Err     error
+}
+
+// TerminalStateError reports that a cluster entered a state from which the target state cannot be reached.
+type TerminalStateError struct {
+	Cluster     string
+	State       string
+	TargetState string
+}
+
+func (e *TerminalStateError) Error() string {
+	return fmt.Sprintf("cluster %s entered terminal state %s while waiting for %s", e.Cluster, e.State, e.TargetState)
+}
+
+// WaitForState polls a cluster with exponential backoff until it reaches the target state.
+// It returns early with a TerminalStateError if the cluster is being deleted while waiting
+// for any other state, and with a NotFoundError if the cluster disappears. When waiting for
+// DELETED, a cluster that no longer exists counts as reached. Server errors, rate limiting and
+// network failures are retried on the next poll; other API errors end the wait. The returned result's Err matches the returned error.
+func WaitForState(ctx context.Context, sdk admin.ClustersApi, projectID, clusterName string, opts WaitOptions) (WaitResult, error) {
+	opts = withWaitDefaults(opts)
+	result := WaitResult{Cluster: clusterName}
+	if projectID == "" || clusterName == "" {
+		result.Err = &errors.ValidationError{Message: "project ID and cluster name are required"}
+		return result, result.Err
+	}
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	interval := opts.InitialInterval
+	transitioned := opts.AwaitTransition <= 0
+	for polls := 1; ; polls++ {
+		state, done, err := pollState(ctx, sdk, projectID, clusterName, opts.TargetState)
+		result.Elapsed = time.Since(start)
+		if err != nil {
+			result.Err = err
+			return result, err
+		}
+		if state != "" {
+			if opts.Progress != nil {
+				opts.Progress(WaitProgress{
+					Cluster:       clusterName,
+					State:         state,
+					PreviousState: result.State,
+					Changed:       state != result.State,
+					Elapsed:       result.Elapsed,
+					Polls:         polls,
+				})
+			}
+			result.State = state
+			transitioned = transitioned || state != opts.TargetState
+		}
+		if done && (transitioned || result.Elapsed >= opts.AwaitTransition) {
+			return result, nil
+		}
+		if isTerminalState(state, opts.TargetState) {
+			result.Err = &TerminalStateError{Cluster: clusterName, State: state, TargetState: opts.TargetState}
+			return result, result.Err
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			result.Elapsed = time.Since(start)
+			result.Err = fmt.Errorf("waiting for cluster %s to reach %s (last state %q): %w",
+				clusterName, opts.TargetState, result.State, ctx.Err())
+			return result, result.Err
+		case <-timer.C:
+		}
+		interval = min(interval*2, opts.MaxInterval)
+	}
+}
+
+// WaitForClusters waits on several clusters in the same project concurrently and returns one
+// result per cluster, in the order given. Progress callbacks are serialized, so opts.Progress
+// need not be safe for concurrent use.
+func WaitForClusters(ctx context.Context, sdk admin.ClustersApi, projectID string, clusterNames []string, opts WaitOptions) []WaitResult {
+	if opts.Progress != nil {
+		var mu sync.Mutex
+		progress := opts.Progress
+		opts.Progress = func(p WaitProgress) {
+			mu.Lock()
+			defer mu.Unlock()
+			progress(p)
+		}
+	}
+
+	results := make([]WaitResult, len(clusterNames))
+	var wg sync.WaitGroup
+	for i, name := range clusterNames {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _ = WaitForState(ctx, sdk, projectID, name, opts)
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// pollState reads the cluster state once. It reports done when the target state is reached,
+// and returns an empty state without error when the read failed with a retryable or non-API error.
+func pollState(ctx context.Context, sdk admin.ClustersApi, projectID, clusterName, target string) (string, bool, error) {
+	cluster, _, err := sdk.GetCluster(ctx, projectID, clusterName).Execute()
+	if err != nil {
+		if admin.IsErrorCode(err, "CLUSTER_NOT_FOUND") {
+			if target == StateDeleted {
+				return StateDeleted, true, nil
+			}
+			return "", false, &errors.NotFoundError{Resource: "cluster", ID: clusterName}
+		}
+		if ctx.Err() != nil {
+			return "", false, fmt.Errorf("waiting for cluster %s to reach %s: %w", clusterName, target, ctx.Err())
+		}
+		if apiErr, ok := admin.AsError(err); !ok || retryableStatus(apiErr.GetError()) {
+			return "", false, nil
+		}
+		return "", false, errors.FormatError("get cluster", clusterName, err)
+	}
+	state := cluster.GetStateName()
+	return state, state == target, nil
+}
+
+// isTerminalState reports whether a cluster in state can no longer reach target.
+func isTerminalState(state, target string) bool {
+	switch target {
+	case StateDeleting, StateDeleted:
+		return false
+	}
+	return state == StateDeleting || state == StateDeleted
+}
+
+// retryableStatus reports whether an HTTP status is worth polling through.
+func retryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
+
+// withWaitDefaults fills unset options from DefaultWaitOptions. Timeout is left as given.
+func withWaitDefaults(opts WaitOptions) WaitOptions {
+	def := DefaultWaitOptions()
+	if opts.TargetState == "" {
+		opts.TargetState = def.TargetState
+	}
+	if opts.InitialInterval <= 0 {
+		opts.InitialInterval = def.InitialInterval
+	}
+	if opts.MaxInterval <= 0 {
+		opts.MaxInterval = def.MaxInterval
+	}
+	opts.MaxInterval = max(opts.MaxInterval, opts.InitialInterval)
+	return opts
+}
@@ -0,0 +1,181 @@
+package clusterutils
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	internalerrors "atlas-sdk-go/internal/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/atlas-sdk/v20250219001/admin"
+)
+
+// stateServer serves GetCluster for each cluster from a scripted sequence of states, repeating the last one.
+// A state of "404" or "500" answers with that error instead.
+func stateServer(t *testing.T, states map[string][]string) http.HandlerFunc {
+	var mu sync.Mutex
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		mu.Lock()
+		seq := states[name]
+		state := seq[0]
+		if len(seq) > 1 {
+			states[name] = seq[1:]
+		}
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		switch state {
+		case "404":
+			w.WriteHeader(http.StatusNotFound)
+			assert.NoError(t, json.NewEncoder(w).Encode(admin.ApiError{Error: 404, ErrorCode: "CLUSTER_NOT_FOUND"}))
+		case "500":
+			w.WriteHeader(http.StatusInternalServerError)
+			assert.NoError(t, json.NewEncoder(w).Encode(admin.ApiError{Error: 500, ErrorCode: "UNEXPECTED_ERROR"}))
+		default:
+			assert.NoError(t, json.NewEncoder(w).Encode(admin.ClusterDescription20240805{
+				Name:      admin.PtrString(name),
+				StateName: admin.PtrString(state),
+			}))
+		}
+	}
+}
+
+func fastWait() WaitOptions {
+	return WaitOptions{InitialInterval: time.Millisecond, MaxInterval: 2 * time.Millisecond, Timeout: 5 * time.Second}
+}
+
+func TestWaitForState_ReportsProgressUntilIdle(t *testing.T) {
+	t.Parallel()
+	client := newTestAtlasClient(t, stateServer(t, map[string][]string{
+		"Cluster0": {StateUpdating, StateUpdating, "500", StateRepairing, StateIdle},
+	}))
+	opts := fastWait()
+	var progress []WaitProgress
+	opts.Progress = func(p WaitProgress) { progress = append(progress, p) }
+
+	result, err := WaitForState(context.Background(), client.ClustersApi, "proj1", "Cluster0", opts)
+
+	require.NoError(t, err, "server errors are retried")
+	assert.Equal(t, StateIdle, result.State)
+	require.Len(t, progress, 4)
+	var changes []string
+	for _, p := range progress {
+		if p.Changed {
+			changes = append(changes, p.PreviousState+"->"+p.State)
+		}
+	}
+	assert.Equal(t, []string{"->UPDATING", "UPDATING->REPAIRING", "REPAIRING->IDLE"}, changes)
+	assert.Equal(t, 5, progress[3].Polls)
+}
+
+func TestWaitForState_AwaitTransition(t *testing.T) {
+	t.Parallel()
+	client := newTestAtlasClient(t, stateServer(t, map[string][]string{
+		"Cluster0": {StateIdle, StateIdle, StateUpdating, StateIdle},
+		"Cluster1": {StateIdle},
+	}))
+	opts := fastWait()
+	opts.AwaitTransition = time.Minute
+	var polls int
+	opts.Progress = func(p WaitProgress) { polls = p.Polls }
+
+	result, err := WaitForState(context.Background(), client.ClustersApi, "proj1", "Cluster0", opts)
+	require.NoError(t, err)
+	assert.Equal(t, StateIdle, result.State)
+	assert.Equal(t, 4, polls, "IDLE before the update starts is not accepted")
+
+	opts.AwaitTransition = 20 * time.Millisecond
+	result, err = WaitForState(context.Background(), client.ClustersApi, "proj1", "Cluster1", opts)
+	require.NoError(t, err, "IDLE is accepted once AwaitTransition has elapsed")
+	assert.GreaterOrEqual(t, result.Elapsed, opts.AwaitTransition)
+}
+
+func TestWaitForState_RetriesNetworkErrors(t *testing.T) {
+	t.Parallel()
+	var mu sync.Mutex
+	dropped := false
+	states := stateServer(t, map[string][]string{"Cluster0": {StateIdle}})
+	client := newTestAtlasClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		drop := !dropped
+		dropped = true
+		mu.Unlock()
+		if drop {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			require.NoError(t, err)
+			assert.NoError(t, conn.Close())
+			return
+		}
+		states(w, r)
+	})
+
+	result, err := WaitForState(context.Background(), client.ClustersApi, "proj1", "Cluster0", fastWait())
+
+	require.NoError(t, err, "a dropped connection is retried")
+	assert.Equal(t, StateIdle, result.State)
+}
+
+func TestWaitForState_TerminalAndMissing(t *testing.T) {
+	t.Parallel()
+	client := newTestAtlasClient(t, stateServer(t, map[string][]string{
+		"Deleting": {StateUpdating, StateDeleting},
+		"Gone":     {"404"},
+	}))
+
+	result, err := WaitForState(context.Background(), client.ClustersApi, "proj1", "Deleting", fastWait())
+	var termErr *TerminalStateError
+	require.ErrorAs(t, err, &termErr)
+	assert.Equal(t, StateDeleting, termErr.State)
+	assert.Equal(t, err, result.Err)
+
+	_, err = WaitForState(context.Background(), client.ClustersApi, "proj1", "Gone", fastWait())
+	var nfErr *internalerrors.NotFoundError
+	assert.ErrorAs(t, err, &nfErr)
+
+	opts := fastWait()
+	opts.TargetState = StateDeleted
+	result, err = WaitForState(context.Background(), client.ClustersApi, "proj1", "Gone", opts)
+	require.NoError(t, err, "a missing cluster has reached DELETED")
+	assert.Equal(t, StateDeleted, result.State)
+}
+
+func TestWaitForState_Timeout(t *testing.T) {
+	t.Parallel()
+	client := newTestAtlasClient(t, stateServer(t, map[string][]string{"Cluster0": {StateUpdating}}))
+	opts := fastWait()
+	opts.Timeout = 20 * time.Millisecond
+
+	result, err := WaitForState(context.Background(), client.ClustersApi, "proj1", "Cluster0", opts)
+
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, StateUpdating, result.State)
+}
+
+func TestWaitForClusters(t *testing.T) {
+	t.Parallel()
+	client := newTestAtlasClient(t, stateServer(t, map[string][]string{
+		"a": {StateUpdating, StateIdle},
+		"b": {StateIdle},
+		"c": {StateDeleting},
+	}))
+	opts := fastWait()
+	calls := 0
+	opts.Progress = func(WaitProgress) { calls++ }
+
+	results := WaitForClusters(context.Background(), client.ClustersApi, "proj1", []string{"a", "b", "c"}, opts)
+
+	require.Len(t, results, 3)
+	assert.Equal(t, "a", results[0].Cluster)
+	assert.NoError(t, results[0].Err)
+	assert.NoError(t, results[1].Err)
+	var termErr *TerminalStateError
+	assert.ErrorAs(t, results[2].Err, &termErr)
+	assert.Equal(t, 4, calls)
+}
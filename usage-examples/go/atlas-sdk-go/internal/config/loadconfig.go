@@ -2,6 +2,7 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"strings"
 
@@ -10,14 +11,19 @@ import (
 
 // Config holds the configuration for connecting to MongoDB Atlas
 type Config struct {
-	BaseURL     string        `json:"MONGODB_ATLAS_BASE_URL"`
-	OrgID       string        `json:"ATLAS_ORG_ID"`
-	ProjectID   string        `json:"ATLAS_PROJECT_ID"`
-	ClusterName string        `json:"ATLAS_CLUSTER_NAME"`
-	HostName    string        `json:"ATLAS_HOSTNAME"`
-	ProcessID   string        `json:"ATLAS_PROCESS_ID"`
-	DR          DrOptions     `json:"disaster_recovery,omitempty"`
-	Scaling     ScalingConfig `json:"programmatic_scaling,omitempty"`
+	BaseURL      string         `json:"MONGODB_ATLAS_BASE_URL"`
+	OrgID        string         `json:"ATLAS_ORG_ID"`
+	ProjectID    string         `json:"ATLAS_PROJECT_ID"`
+	ClusterName  string         `json:"ATLAS_CLUSTER_NAME"`
+	HostName     string         `json:"ATLAS_HOSTNAME"`
+	ProcessID    string         `json:"ATLAS_PROCESS_ID"`
+	DR           DrOptions      `json:"disaster_recovery,omitempty"`
+	Scaling      ScalingConfig  `json:"programmatic_scaling,omitempty"`
+	Budgets      []Budget       `json:"budgets,omitempty"`
+	SKURulesPath string         `json:"sku_rules_path,omitempty"` // JSON file of SKU rules merged over the embedded defaults
+	Database     DatabaseConfig `json:"database,omitempty"`
+	Health       HealthConfig   `json:"health,omitempty"`
+	Exporter     ExporterConfig `json:"prometheus_exporter,omitempty"`
 }
 
 // DrOptions holds the disaster recovery configuration parameters.
@@ -38,6 +44,72 @@ type ScalingConfig struct {
 	CPUThreshold  float64 `json:"cpu_threshold,omitempty"`      // Average CPU % threshold to trigger reactive scale
 	PeriodMinutes int     `json:"cpu_period_minutes,omitempty"` // Lookback window in minutes for CPU averaging
 	DryRun        bool    `json:"dry_run,omitempty"`            // If true, only log intended actions without executing
+
+	PriceCatalogPath       string  `json:"price_catalog_path,omitempty"`         // JSON file of hourly node prices used to estimate cost impact
+	LearnPrices            bool    `json:"learn_prices_from_invoices,omitempty"` // If true, add prices seen on recent invoices to the catalog
+	MaxMonthlyCostIncrease float64 `json:"max_monthly_cost_increase,omitempty"`  // Refuse scaling above this monthly increase in US dollars (0: no limit)
+}
+
+// Database authentication mechanisms supported by DatabaseConfig.AuthMechanism
+const (
+	AuthNone  = ""
+	AuthSCRAM = "scram"
+	AuthX509  = "x509"
+	AuthOIDC  = "oidc"
+)
+
+// DatabaseConfig controls how examples connect to clusters with the MongoDB Go Driver.
+type DatabaseConfig struct {
+	ConnectionTypes   []string `json:"connection_types,omitempty"`    // Preference order of "standard", "private_endpoint", "private" (default: all, in that order)
+	PrivateEndpointID string   `json:"private_endpoint_id,omitempty"` // Endpoint to use when a cluster has several private endpoints
+	AuthMechanism     string   `json:"auth_mechanism,omitempty"`      // "scram", "x509", or "oidc" (default: no credentials)
+	X509CertFile      string   `json:"x509_cert_file,omitempty"`      // PEM file with the client certificate and private key (x509)
+	OIDCEnvironment   string   `json:"oidc_environment,omitempty"`    // Workload identity provider: "azure" or "gcp" (oidc)
+	OIDCTokenResource string   `json:"oidc_token_resource,omitempty"` // Token audience for the workload identity provider (oidc)
+	TLSCAFile         string   `json:"tls_ca_file,omitempty"`         // CA bundle used to verify the cluster certificate (default: system roots)
+}
+
+// HealthConfig holds the thresholds used to rate replica set members green, yellow, or red.
+// Zero values use the defaults; set a connections threshold to rate connection counts.
+type HealthConfig struct {
+	ReplicationLagWarnSeconds float64 `json:"replication_lag_warn_seconds,omitempty"` // Secondary lag rated yellow above this (default: 60)
+	ReplicationLagCritSeconds float64 `json:"replication_lag_crit_seconds,omitempty"` // Secondary lag rated red above this (default: 300)
+	OplogWindowWarnHours      float64 `json:"oplog_window_warn_hours,omitempty"`      // Oplog window rated yellow below this (default: 24)
+	OplogWindowCritHours      float64 `json:"oplog_window_crit_hours,omitempty"`      // Oplog window rated red below this (default: 8)
+	ConnectionsWarn           float64 `json:"connections_warn,omitempty"`             // Open connections rated yellow above this (default: not rated)
+	ConnectionsCrit           float64 `json:"connections_crit,omitempty"`             // Open connections rated red above this (default: not rated)
+	TicketsAvailableWarn      float64 `json:"tickets_available_warn,omitempty"`       // Available read or write tickets rated yellow below this (default: 32)
+	TicketsAvailableCrit      float64 `json:"tickets_available_crit,omitempty"`       // Available read or write tickets rated red below this (default: 8)
+}
+
+// ExporterConfig controls which measurements the Prometheus exporter pulls from Atlas and how often.
+// Leaving all three measurement lists empty uses the default lists; an empty list otherwise skips that kind.
+type ExporterConfig struct {
+	ListenAddress          string   `json:"listen_address,omitempty"`           // Address serving /metrics (default: ":9216")
+	RefreshIntervalSeconds int      `json:"refresh_interval_seconds,omitempty"` // Time between pulls from Atlas (default: 60)
+	ProcessMeasurements    []string `json:"process_measurements,omitempty"`     // e.g. "CONNECTIONS", "PROCESS_CPU_USER"
+	DiskMeasurements       []string `json:"disk_measurements,omitempty"`        // e.g. "DISK_PARTITION_SPACE_USED"
+	DatabaseMeasurements   []string `json:"database_measurements,omitempty"`    // e.g. "DATABASE_DATA_SIZE"
+	Granularity            string   `json:"granularity,omitempty"`              // Measurement granularity (default: "PT1M")
+	Period                 string   `json:"period,omitempty"`                   // Lookback for the latest value (default: "PT10M")
+	Workers                int      `json:"workers,omitempty"`                  // Concurrent Atlas requests (default: 8)
+	RequestTimeoutSeconds  int      `json:"request_timeout_seconds,omitempty"`  // Timeout for each Atlas request (default: 30)
+}
+
+// Budget scopes supported by Budget.Scope
+const (
+	BudgetScopeOrg      = "org"
+	BudgetScopeProject  = "project"
+	BudgetScopeCategory = "category"
+)
+
+// Budget holds a monthly spending limit for an organization, project, or billing category.
+type Budget struct {
+	Name         string  `json:"name,omitempty"`         // Label used in reports (default: scope and ID)
+	Scope        string  `json:"scope"`                  // "org", "project", or "category"
+	ID           string  `json:"id"`                     // Org ID, project ID or name, or category name (e.g. "Clusters")
+	MonthlyLimit float64 `json:"monthly_limit"`          // Monthly limit in US dollars
+	WarnPercent  float64 `json:"warn_percent,omitempty"` // Utilisation % that triggers a warning (default: 80)
 }
 
 // LoadConfig reads a JSON configuration file and returns a Config struct
@@ -83,6 +155,26 @@ func LoadConfig(path string) (Config, error) {
 		}
 	}
 
+	for i, b := range config.Budgets {
+		switch b.Scope {
+		case BudgetScopeOrg, BudgetScopeProject, BudgetScopeCategory:
+		default:
+			return config, &errors.ValidationError{
+				Message: fmt.Sprintf("budget %d: scope must be one of org, project, or category", i),
+			}
+		}
+		if b.ID == "" {
+			return config, &errors.ValidationError{
+				Message: fmt.Sprintf("budget %d: id is required", i),
+			}
+		}
+		if b.MonthlyLimit <= 0 {
+			return config, &errors.ValidationError{
+				Message: fmt.Sprintf("budget %d: monthly_limit must be greater than zero", i),
+			}
+		}
+	}
+
 	if config.BaseURL == "" {
 		config.BaseURL = "https://cloud.mongodb.com" // Default base URL if not provided
 	}
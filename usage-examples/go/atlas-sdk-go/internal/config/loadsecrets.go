@@ -9,6 +9,8 @@ import (
 const (
 	envServiceAccountID     = "MONGODB_ATLAS_SERVICE_ACCOUNT_ID"
 	envServiceAccountSecret = "MONGODB_ATLAS_SERVICE_ACCOUNT_SECRET"
+	envDatabaseUsername     = "MONGODB_DATABASE_USERNAME"
+	envDatabasePassword     = "MONGODB_DATABASE_PASSWORD"
 )
 
 var errMissingEnv = errors.New("missing environment variable")
@@ -17,6 +19,8 @@ var errMissingEnv = errors.New("missing environment variable")
 type Secrets struct {
 	serviceAccountID     string
 	serviceAccountSecret string
+	databaseUsername     string
+	databasePassword     string
 }
 
 func (s Secrets) ServiceAccountID() string {
@@ -27,8 +31,18 @@ func (s Secrets) ServiceAccountSecret() string {
 	return s.serviceAccountSecret
 }
 
+// DatabaseUsername returns the database user for SCRAM authentication, if set.
+func (s Secrets) DatabaseUsername() string {
+	return s.databaseUsername
+}
+
+// DatabasePassword returns the database user's password for SCRAM authentication, if set.
+func (s Secrets) DatabasePassword() string {
+	return s.databasePassword
+}
+
 // LoadSecrets loads sensitive configuration from environment variables
-// Returns error if any required environment variable is missing
+// Returns error if any required environment variable is missing. Database user credentials are optional.
 func LoadSecrets() (Secrets, error) {
 	s := Secrets{}
 	var missing []string
@@ -47,6 +61,9 @@ func LoadSecrets() (Secrets, error) {
 	if len(missing) > 0 {
 		return Secrets{}, fmt.Errorf("load secrets: %w (missing: %v)", errMissingEnv, missing)
 	}
+
+	s.databaseUsername = os.Getenv(envDatabaseUsername)
+	s.databasePassword = os.Getenv(envDatabasePassword)
 	return s, nil
 }
 
@@ -58,4 +75,12 @@ func NewSecrets(id, secret string) Secrets {
 	return Secrets{serviceAccountID: id, serviceAccountSecret: secret}
 }
 
+// WithDatabaseUser returns a copy of s with database user credentials set.
+// Used for testing or to set secrets programmatically.
+func (s Secrets) WithDatabaseUser(username, password string) Secrets {
+	s.databaseUsername = username
+	s.databasePassword = password
+	return s
+}
+
 // :remove-end:
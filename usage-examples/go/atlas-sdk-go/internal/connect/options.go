@@ -0,0 +1,121 @@
+package connect
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+
+	"atlas-sdk-go/internal/clusterutils"
+	"atlas-sdk-go/internal/config"
+	"atlas-sdk-go/internal/errors"
+
+	"go.mongodb.org/atlas-sdk/v20250219001/admin"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Driver authentication mechanism names
+const (
+	mechanismX509 = "MONGODB-X509"
+	mechanismOIDC = "MONGODB-OIDC"
+)
+
+// ForCluster resolves the cluster's connection string in the configured preference order and
+// returns driver client options for it. The returned ConnectionString reports which network
+// path was chosen.
+func ForCluster(ctx context.Context, sdk *admin.APIClient, projectID, clusterName string,
+	db config.DatabaseConfig, secrets config.Secrets) (*options.ClientOptions, clusterutils.ConnectionString, error) {
+	cs, err := clusterutils.GetClusterConnectionString(ctx, sdk, projectID, clusterName, db.ConnectionTypes, db.PrivateEndpointID)
+	if err != nil {
+		return nil, cs, err
+	}
+	opts, err := ClientOptions(cs.URI, db, secrets)
+	return opts, cs, err
+}
+
+// ClientOptions returns driver client options for uri with TLS enabled and credentials for the
+// configured auth mechanism:
+//   - scram: the database user and password from secrets
+//   - x509: the client certificate and key in X509CertFile
+//   - oidc: workload identity from OIDCEnvironment ("azure" or "gcp")
+//
+// With no auth mechanism, no credential is set.
+func ClientOptions(uri string, db config.DatabaseConfig, secrets config.Secrets) (*options.ClientOptions, error) {
+	if uri == "" {
+		return nil, &errors.ValidationError{Message: "connection string is required"}
+	}
+	tlsConfig, err := newTLSConfig(db.TLSCAFile)
+	if err != nil {
+		return nil, err
+	}
+	opts := options.Client().ApplyURI(uri)
+
+	switch strings.ToLower(db.AuthMechanism) {
+	case config.AuthNone:
+	case config.AuthSCRAM:
+		if secrets.DatabaseUsername() == "" || secrets.DatabasePassword() == "" {
+			return nil, &errors.ValidationError{Message: "scram authentication requires a database username and password"}
+		}
+		opts.SetAuth(options.Credential{
+			AuthSource: "admin",
+			Username:   secrets.DatabaseUsername(),
+			Password:   secrets.DatabasePassword(),
+		})
+	case config.AuthX509:
+		if db.X509CertFile == "" {
+			return nil, &errors.ValidationError{Message: "x509 authentication requires x509_cert_file"}
+		}
+		cert, err := tls.LoadX509KeyPair(db.X509CertFile, db.X509CertFile)
+		if err != nil {
+			return nil, errors.WithContext(err, "loading x509 client certificate")
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+		opts.SetAuth(options.Credential{AuthMechanism: mechanismX509, AuthSource: "$external"})
+	case config.AuthOIDC:
+		env := strings.ToLower(db.OIDCEnvironment)
+		if env != "azure" && env != "gcp" {
+			return nil, &errors.ValidationError{Message: fmt.Sprintf("oidc authentication requires oidc_environment \"azure\" or \"gcp\", got %q", db.OIDCEnvironment)}
+		}
+		props := map[string]string{"ENVIRONMENT": env}
+		if db.OIDCTokenResource != "" {
+			props["TOKEN_RESOURCE"] = db.OIDCTokenResource
+		}
+		// On Azure the username selects a user-assigned managed identity by client ID
+		opts.SetAuth(options.Credential{
+			AuthMechanism:           mechanismOIDC,
+			AuthMechanismProperties: props,
+			Username:                secrets.DatabaseUsername(),
+		})
+	default:
+		return nil, &errors.ValidationError{Message: fmt.Sprintf("unknown auth mechanism %q", db.AuthMechanism)}
+	}
+
+	opts.SetTLSConfig(tlsConfig)
+	if err := opts.Validate(); err != nil {
+		return nil, errors.WithContext(err, "validating client options")
+	}
+	return opts, nil
+}
+
+// newTLSConfig returns a TLS 1.2+ configuration that trusts caFile, or the system roots if empty.
+func newTLSConfig(caFile string) (*tls.Config, error) {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if caFile == "" {
+		return cfg, nil
+	}
+	pem, err := os.ReadFile(caFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, &errors.NotFoundError{Resource: "TLS CA file", ID: caFile}
+		}
+		return nil, errors.WithContext(err, "reading TLS CA file")
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, &errors.ValidationError{Message: fmt.Sprintf("no certificates found in TLS CA file %s", caFile)}
+	}
+	cfg.RootCAs = pool
+	return cfg, nil
+}
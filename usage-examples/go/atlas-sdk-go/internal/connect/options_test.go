@@ -0,0 +1,118 @@
+package connect
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"atlas-sdk-go/internal/config"
+	internalerrors "atlas-sdk-go/internal/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testURI = "mongodb://cluster0-shard-00-00-pri.ab1cd.mongodb.net:27017"
+
+// writeCertificate writes a self-signed certificate and its private key to one PEM file.
+func writeCertificate(t *testing.T) string {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "app-user"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IsCA:         true,
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "client.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	data = append(data, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})...)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+func TestClientOptions_SCRAM(t *testing.T) {
+	t.Parallel()
+	secrets := config.NewSecrets("id", "secret").WithDatabaseUser("app", "pw")
+
+	opts, err := ClientOptions(testURI, config.DatabaseConfig{AuthMechanism: config.AuthSCRAM}, secrets)
+
+	require.NoError(t, err)
+	require.NotNil(t, opts.Auth)
+	assert.Equal(t, "app", opts.Auth.Username)
+	assert.Equal(t, "pw", opts.Auth.Password)
+	assert.Equal(t, "admin", opts.Auth.AuthSource)
+	require.NotNil(t, opts.TLSConfig)
+
+	_, err = ClientOptions(testURI, config.DatabaseConfig{AuthMechanism: config.AuthSCRAM}, config.NewSecrets("id", "secret"))
+	var vErr *internalerrors.ValidationError
+	assert.ErrorAs(t, err, &vErr, "scram without a database user should fail")
+}
+
+func TestClientOptions_X509(t *testing.T) {
+	t.Parallel()
+	certFile := writeCertificate(t)
+
+	opts, err := ClientOptions(testURI, config.DatabaseConfig{AuthMechanism: config.AuthX509, X509CertFile: certFile, TLSCAFile: certFile},
+		config.NewSecrets("id", "secret"))
+
+	require.NoError(t, err)
+	assert.Equal(t, "MONGODB-X509", opts.Auth.AuthMechanism)
+	assert.Equal(t, "$external", opts.Auth.AuthSource)
+	assert.Len(t, opts.TLSConfig.Certificates, 1)
+	assert.NotNil(t, opts.TLSConfig.RootCAs)
+
+	_, err = ClientOptions(testURI, config.DatabaseConfig{AuthMechanism: config.AuthX509}, config.Secrets{})
+	var vErr *internalerrors.ValidationError
+	assert.ErrorAs(t, err, &vErr)
+}
+
+func TestClientOptions_OIDC(t *testing.T) {
+	t.Parallel()
+	db := config.DatabaseConfig{AuthMechanism: config.AuthOIDC, OIDCEnvironment: "gcp", OIDCTokenResource: "api://atlas"}
+
+	opts, err := ClientOptions(testURI, db, config.Secrets{})
+
+	require.NoError(t, err)
+	assert.Equal(t, "MONGODB-OIDC", opts.Auth.AuthMechanism)
+	assert.Equal(t, map[string]string{"ENVIRONMENT": "gcp", "TOKEN_RESOURCE": "api://atlas"}, opts.Auth.AuthMechanismProperties)
+
+	db.OIDCEnvironment = "laptop"
+	_, err = ClientOptions(testURI, db, config.Secrets{})
+	var vErr *internalerrors.ValidationError
+	assert.ErrorAs(t, err, &vErr)
+}
+
+func TestClientOptions_NoAuth(t *testing.T) {
+	t.Parallel()
+
+	opts, err := ClientOptions(testURI, config.DatabaseConfig{}, config.Secrets{})
+
+	require.NoError(t, err)
+	assert.Nil(t, opts.Auth)
+	assert.Equal(t, uint16(0x0303), opts.TLSConfig.MinVersion, "TLS 1.2 minimum")
+
+	_, err = ClientOptions(testURI, config.DatabaseConfig{AuthMechanism: "kerberos"}, config.Secrets{})
+	var vErr *internalerrors.ValidationError
+	assert.ErrorAs(t, err, &vErr)
+
+	_, err = ClientOptions(testURI, config.DatabaseConfig{TLSCAFile: "missing.pem"}, config.Secrets{})
+	var nfErr *internalerrors.NotFoundError
+	assert.ErrorAs(t, err, &nfErr)
+}
@@ -0,0 +1,286 @@
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"atlas-sdk-go/internal/clusterutils"
+	"atlas-sdk-go/internal/config"
+	"atlas-sdk-go/internal/errors"
+	"atlas-sdk-go/internal/metrics"
+
+	"go.mongodb.org/atlas-sdk/v20250219001/admin"
+)
+
+// ContentType is the media type of the Prometheus text exposition format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Default measurements pulled when none are configured
+var (
+	DefaultProcessMeasurements = []string{
+		"PROCESS_CPU_USER", "PROCESS_CPU_KERNEL", "SYSTEM_NORMALIZED_CPU_USER", "CONNECTIONS",
+		"OPCOUNTER_QUERY", "OPCOUNTER_INSERT", "OPCOUNTER_UPDATE", "OPCOUNTER_DELETE",
+		"OPLOG_REPLICATION_LAG_TIME", "OPLOG_MASTER_TIME", "MEMORY_RESIDENT",
+	}
+	DefaultDiskMeasurements = []string{
+		"DISK_PARTITION_SPACE_USED", "DISK_PARTITION_SPACE_FREE", "DISK_PARTITION_IOPS_READ",
+		"DISK_PARTITION_IOPS_WRITE", "DISK_PARTITION_LATENCY_READ", "DISK_PARTITION_LATENCY_WRITE",
+	}
+	DefaultDatabaseMeasurements = []string{
+		"DATABASE_DATA_SIZE", "DATABASE_STORAGE_SIZE", "DATABASE_INDEX_SIZE", "DATABASE_AVERAGE_OBJECT_SIZE",
+	}
+)
+
+// Options exposes config within the exporter package while reusing config.ExporterConfig.
+type Options = config.ExporterConfig
+
+// LoadOptions returns the configured exporter options with defaults for unset values.
+func LoadOptions(cfg config.Config) Options {
+	o := cfg.Exporter
+	if o.ListenAddress == "" {
+		o.ListenAddress = ":9216"
+	}
+	if o.RefreshIntervalSeconds == 0 {
+		o.RefreshIntervalSeconds = 60
+	}
+	if len(o.ProcessMeasurements) == 0 && len(o.DiskMeasurements) == 0 && len(o.DatabaseMeasurements) == 0 {
+		o.ProcessMeasurements = DefaultProcessMeasurements
+		o.DiskMeasurements = DefaultDiskMeasurements
+		o.DatabaseMeasurements = DefaultDatabaseMeasurements
+	}
+	if o.Granularity == "" {
+		o.Granularity = "PT1M"
+	}
+	if o.Period == "" {
+		o.Period = "PT10M"
+	}
+	if o.Workers == 0 {
+		o.Workers = metrics.DefaultCollectWorkers
+	}
+	if o.RequestTimeoutSeconds == 0 {
+		o.RequestTimeoutSeconds = int(metrics.DefaultRequestTimeout / time.Second)
+	}
+	return o
+}
+
+// Exporter pulls measurements for every process, disk partition, and database in a project on
+// each refresh and serves the latest cached values to Prometheus. Scrapes never call Atlas.
+type Exporter struct {
+	client    *admin.APIClient
+	projectID string
+	opts      Options
+	now       func() time.Time
+
+	mu      sync.RWMutex
+	samples []Sample // from the last successful refresh
+	status  status
+}
+
+// status tracks the exporter's own health, reported alongside the cached samples.
+type status struct {
+	lastRefresh     time.Time
+	lastSuccess     time.Time
+	refreshDuration time.Duration
+	refreshes       int
+	failures        int
+	up              bool
+	targets         int
+	targetErrors    int
+}
+
+// New returns an exporter for a project. Options should come from LoadOptions.
+func New(client *admin.APIClient, projectID string, opts Options) *Exporter {
+	return &Exporter{client: client, projectID: projectID, opts: opts, now: time.Now}
+}
+
+// Refresh pulls the configured measurements and replaces the cached samples. Targets that fail
+// are left out and returned; the refresh only fails if clusters or processes cannot be listed,
+// in which case the previous samples are kept.
+func (e *Exporter) Refresh(ctx context.Context) ([]metrics.TargetError, error) {
+	start := e.now()
+	samples, targets, targetErrs, err := e.collect(ctx)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.status.lastRefresh = start
+	e.status.refreshDuration = e.now().Sub(start)
+	e.status.refreshes++
+	if err != nil {
+		e.status.failures++
+		e.status.up = false
+		return nil, err
+	}
+	e.samples = samples
+	e.status.lastSuccess = start
+	e.status.up = true
+	e.status.targets = targets
+	e.status.targetErrors = len(targetErrs)
+	return targetErrs, nil
+}
+
+// Run refreshes immediately and then every refresh interval until ctx is done, logging failures.
+func (e *Exporter) Run(ctx context.Context) {
+	interval := time.Duration(e.opts.RefreshIntervalSeconds) * time.Second
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		targetErrs, err := e.Refresh(ctx)
+		switch {
+		case err != nil:
+			log.Printf("Refresh failed, serving cached metrics: %v", err)
+		case len(targetErrs) > 0:
+			log.Printf("Refresh skipped %d targets (first: %v)", len(targetErrs), &targetErrs[0])
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ServeHTTP writes the cached samples and the exporter's own metrics in the Prometheus text format.
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	var buf bytes.Buffer
+	if err := e.Write(&buf); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", ContentType)
+	_, _ = w.Write(buf.Bytes())
+}
+
+// Write writes the cached samples and the exporter's own metrics in the Prometheus text format.
+func (e *Exporter) Write(w io.Writer) error {
+	e.mu.RLock()
+	samples := append(e.selfSamples(), e.samples...)
+	e.mu.RUnlock()
+	return WriteText(w, samples)
+}
+
+// collect lists the project's clusters and processes, discovers their targets, and fetches them.
+func (e *Exporter) collect(ctx context.Context) ([]Sample, int, []metrics.TargetError, error) {
+	if e.projectID == "" {
+		return nil, 0, nil, &errors.ValidationError{Message: "project ID is required"}
+	}
+	clusters, err := clusterutils.ListAllClusters(ctx, e.client.ClustersApi, &admin.ListClustersApiParams{GroupId: e.projectID})
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	processes, err := clusterutils.ListAllProcesses(ctx, e.client.MonitoringAndLogsApi, &admin.ListAtlasProcessesApiParams{GroupId: e.projectID})
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	opts := metrics.CollectOptions{
+		ProcessMeasurements:  e.opts.ProcessMeasurements,
+		DiskMeasurements:     e.opts.DiskMeasurements,
+		DatabaseMeasurements: e.opts.DatabaseMeasurements,
+		Granularity:          e.opts.Granularity,
+		Period:               e.opts.Period,
+		Workers:              e.opts.Workers,
+		RequestTimeout:       time.Duration(e.opts.RequestTimeoutSeconds) * time.Second,
+	}
+	targets, targetErrs := metrics.DiscoverProcessTargets(ctx, e.client.MonitoringAndLogsApi, e.projectID, processes, opts)
+	c := metrics.Collect(ctx, e.client.MonitoringAndLogsApi, e.projectID, targets, opts)
+	targetErrs = append(targetErrs, c.Errors...)
+
+	return BuildSamples(e.projectID, processLabels(clusters, processes), c.Results), len(targets), targetErrs, nil
+}
+
+// ProcessInfo holds the cluster and role labels of a process.
+type ProcessInfo struct {
+	Cluster string
+	Role    string // Atlas typeName e.g. REPLICA_PRIMARY, SHARD_MONGOS
+}
+
+// processLabels maps each process ID to its cluster and role. Processes that belong to no
+// cluster keep an empty cluster label.
+func processLabels(clusters []admin.ClusterDescription20240805, processes []admin.ApiHostViewAtlas) map[string]ProcessInfo {
+	out := make(map[string]ProcessInfo, len(processes))
+	for _, p := range processes {
+		out[p.GetId()] = ProcessInfo{Role: p.GetTypeName()}
+	}
+	for _, c := range clusters {
+		for _, m := range clusterutils.DiscoverTopology(c, processes).Members() {
+			info := out[m.ID]
+			info.Cluster = c.GetName()
+			out[m.ID] = info
+		}
+	}
+	return out
+}
+
+// BuildSamples converts the latest valid point of every series in results into a sample labelled
+// with the project, cluster, process, role, and, for disk and database targets, the partition or
+// database. Series without a valid point are left out.
+func BuildSamples(projectID string, processes map[string]ProcessInfo, results []metrics.Result) []Sample {
+	var out []Sample
+	for _, r := range results {
+		info := processes[r.Target.ProcessID]
+		labels := []Label{
+			{"project", projectID},
+			{"cluster", info.Cluster},
+			{"process", r.Target.ProcessID},
+			{"role", info.Role},
+		}
+		switch r.Target.Kind {
+		case metrics.TargetDisk:
+			labels = append(labels, Label{"partition", r.Target.Partition})
+		case metrics.TargetDatabase:
+			labels = append(labels, Label{"database", r.Target.Database})
+		}
+		for _, name := range slices.Sorted(maps.Keys(r.Series)) {
+			s := r.Series[name]
+			p, ok := s.Latest()
+			if !ok {
+				continue
+			}
+			help := "Atlas measurement " + name
+			if s.Units != "" {
+				help += fmt.Sprintf(" (%s)", s.Units)
+			}
+			out = append(out, Sample{Name: MetricName(name, s.Units), Help: help, Type: TypeGauge, Labels: labels, Value: p.Value})
+		}
+	}
+	return out
+}
+
+// selfSamples reports the exporter's own health. Callers must hold e.mu.
+func (e *Exporter) selfSamples() []Sample {
+	st := e.status
+	up := 0.0
+	if st.up {
+		up = 1
+	}
+	out := []Sample{
+		{Name: "atlas_exporter_up", Help: "Whether the last refresh from Atlas succeeded.", Value: up},
+		{Name: "atlas_exporter_refreshes_total", Help: "Refreshes attempted.", Type: TypeCounter, Value: float64(st.refreshes)},
+		{Name: "atlas_exporter_refresh_failures_total", Help: "Refreshes that failed to list clusters or processes.", Type: TypeCounter, Value: float64(st.failures)},
+		{Name: "atlas_exporter_refresh_duration_seconds", Help: "Duration of the last refresh.", Value: st.refreshDuration.Seconds()},
+		{Name: "atlas_exporter_targets", Help: "Processes, disk partitions, and databases fetched in the last successful refresh.", Value: float64(st.targets)},
+		{Name: "atlas_exporter_target_errors", Help: "Targets that failed in the last successful refresh.", Value: float64(st.targetErrors)},
+	}
+	if !st.lastRefresh.IsZero() {
+		out = append(out, Sample{Name: "atlas_exporter_last_refresh_timestamp_seconds", Help: "Unix time of the last refresh.", Value: unixSeconds(st.lastRefresh)})
+	}
+	if !st.lastSuccess.IsZero() {
+		out = append(out,
+			Sample{Name: "atlas_exporter_last_success_timestamp_seconds", Help: "Unix time of the last successful refresh.", Value: unixSeconds(st.lastSuccess)},
+			Sample{Name: "atlas_exporter_cache_age_seconds", Help: "Age of the cached measurements.", Value: e.now().Sub(st.lastSuccess).Seconds()},
+		)
+	}
+	return out
+}
+
+func unixSeconds(t time.Time) float64 {
+	return float64(t.UnixNano()) / float64(time.Second)
+}
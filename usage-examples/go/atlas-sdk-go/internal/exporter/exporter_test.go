@@ -0,0 +1,161 @@
+package exporter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/atlas-sdk/v20250219001/admin"
+
+	"atlas-sdk-go/internal/config"
+	"atlas-sdk-go/internal/metrics"
+)
+
+func TestMetricName(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		measurement, units, want string
+	}{
+		{"DISK_PARTITION_SPACE_USED", metrics.UnitsBytes, "atlas_disk_partition_space_used_bytes"},
+		{"OPLOG_REPLICATION_LAG_TIME", metrics.UnitsSeconds, "atlas_oplog_replication_lag_time_seconds"},
+		{"PROCESS_CPU_USER", metrics.UnitsPercent, "atlas_process_cpu_user_percent"},
+		{"DISK_PARTITION_SPACE_PERCENT_FREE", metrics.UnitsPercent, "atlas_disk_partition_space_percent_free"},
+		{"OPCOUNTER_QUERY", "SCALAR_PER_SECOND", "atlas_opcounter_query"},
+		{"ODD-NAME.x", "", "atlas_odd_name_x"},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.want, MetricName(tc.measurement, tc.units), tc.measurement)
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	t.Parallel()
+	samples := []Sample{
+		{Name: "b_metric", Help: "B", Value: 2, Labels: []Label{{"process", "h2:27017"}}},
+		{Name: "a_total", Help: "A\ncount", Type: TypeCounter, Value: 3},
+		{Name: "b_metric", Help: "B", Value: 0.5, Labels: []Label{{"process", "h1:27017"}, {"database", `we"ird\db`}}},
+	}
+	var sb strings.Builder
+	require.NoError(t, WriteText(&sb, samples))
+
+	want := `# HELP a_total A\ncount
+# TYPE a_total counter
+a_total 3
+# HELP b_metric B
+# TYPE b_metric gauge
+b_metric{process="h2:27017"} 2
+b_metric{process="h1:27017",database="we\"ird\\db"} 0.5
+`
+	assert.Equal(t, want, sb.String())
+}
+
+func TestLoadOptions(t *testing.T) {
+	t.Parallel()
+	o := LoadOptions(config.Config{})
+	assert.Equal(t, ":9216", o.ListenAddress)
+	assert.Equal(t, 60, o.RefreshIntervalSeconds)
+	assert.Equal(t, DefaultProcessMeasurements, o.ProcessMeasurements)
+	assert.Equal(t, DefaultDatabaseMeasurements, o.DatabaseMeasurements)
+
+	// Configuring only process measurements turns off disk and database collection
+	o = LoadOptions(config.Config{Exporter: config.ExporterConfig{ProcessMeasurements: []string{"CONNECTIONS"}}})
+	assert.Equal(t, []string{"CONNECTIONS"}, o.ProcessMeasurements)
+	assert.Empty(t, o.DiskMeasurements)
+	assert.Empty(t, o.DatabaseMeasurements)
+}
+
+func TestExporter_RefreshAndServe(t *testing.T) {
+	t.Parallel()
+	const prefix = "/api/atlas/v2/groups/proj1"
+	var failing atomic.Bool
+
+	view := func(name, units string, v float32) admin.ApiMeasurementsGeneralViewAtlas {
+		return admin.ApiMeasurementsGeneralViewAtlas{Measurements: &[]admin.MetricsMeasurementAtlas{{
+			Name:  admin.PtrString(name),
+			Units: admin.PtrString(units),
+			DataPoints: &[]admin.MetricDataPointAtlas{
+				{Timestamp: admin.PtrTime(time.Unix(1700000000, 0)), Value: admin.PtrFloat32(1)},
+				{Timestamp: admin.PtrTime(time.Unix(1700000060, 0)), Value: admin.PtrFloat32(v)},
+			},
+		}}}
+	}
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			http.Error(w, `{"error":503}`, http.StatusServiceUnavailable)
+			return
+		}
+		var body any
+		switch strings.TrimPrefix(r.URL.Path, prefix) {
+		case "/clusters":
+			body = admin.PaginatedClusterDescription20240805{TotalCount: admin.PtrInt(1), Results: &[]admin.ClusterDescription20240805{{
+				Name: admin.PtrString("Cluster0"),
+				ConnectionStrings: &admin.ClusterConnectionStrings{
+					Standard: admin.PtrString("mongodb://c0-00.ab1.mongodb.net:27017/?replicaSet=atlas-abc-shard-0"),
+				},
+			}}}
+		case "/processes":
+			body = admin.PaginatedHostViewAtlas{TotalCount: admin.PtrInt(1), Results: &[]admin.ApiHostViewAtlas{{
+				Id: admin.PtrString("c0-00.ab1.mongodb.net:27017"), Hostname: admin.PtrString("c0-00.ab1.mongodb.net"),
+				Port: admin.PtrInt(27017), TypeName: admin.PtrString("REPLICA_PRIMARY"), ReplicaSetName: admin.PtrString("atlas-abc-shard-0"),
+			}}}
+		case "/processes/c0-00.ab1.mongodb.net:27017/measurements":
+			body = view("CONNECTIONS", "SCALAR", 42)
+		case "/processes/c0-00.ab1.mongodb.net:27017/disks":
+			body = admin.PaginatedDiskPartition{TotalCount: admin.PtrInt(1), Results: &[]admin.MeasurementDiskPartition{{PartitionName: admin.PtrString("data")}}}
+		case "/processes/c0-00.ab1.mongodb.net:27017/disks/data/measurements":
+			body = view("DISK_PARTITION_SPACE_USED", "GIGABYTES", 2)
+		case "/processes/c0-00.ab1.mongodb.net:27017/databases":
+			body = admin.PaginatedDatabase{TotalCount: admin.PtrInt(1), Results: &[]admin.MesurementsDatabase{{DatabaseName: admin.PtrString("app")}}}
+		default: // database measurements fail
+			http.Error(w, `{"error":500}`, http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		assert.NoError(t, json.NewEncoder(w).Encode(body))
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	t.Cleanup(server.Close)
+	client, err := admin.NewClient(admin.UseBaseURL(server.URL))
+	require.NoError(t, err)
+
+	e := New(client, "proj1", LoadOptions(config.Config{Exporter: config.ExporterConfig{
+		ProcessMeasurements:  []string{"CONNECTIONS"},
+		DiskMeasurements:     []string{"DISK_PARTITION_SPACE_USED"},
+		DatabaseMeasurements: []string{"DATABASE_DATA_SIZE"},
+	}}))
+
+	targetErrs, err := e.Refresh(context.Background())
+	require.NoError(t, err)
+	require.Len(t, targetErrs, 1)
+	assert.Equal(t, metrics.TargetDatabase, targetErrs[0].Target.Kind)
+
+	scrape := func() string {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		assert.Equal(t, ContentType, rec.Header().Get("Content-Type"))
+		return rec.Body.String()
+	}
+	body := scrape()
+	assert.Contains(t, body, `atlas_connections{project="proj1",cluster="Cluster0",process="c0-00.ab1.mongodb.net:27017",role="REPLICA_PRIMARY"} 42`+"\n")
+	assert.Contains(t, body, `atlas_disk_partition_space_used_bytes{project="proj1",cluster="Cluster0",process="c0-00.ab1.mongodb.net:27017",role="REPLICA_PRIMARY",partition="data"} 2.147483648e+09`+"\n")
+	assert.Contains(t, body, "atlas_exporter_up 1\n")
+	assert.Contains(t, body, "atlas_exporter_targets 3\n")
+	assert.Contains(t, body, "atlas_exporter_target_errors 1\n")
+
+	// A failed refresh keeps serving the cached measurements and reports itself down
+	failing.Store(true)
+	_, err = e.Refresh(context.Background())
+	require.Error(t, err)
+	body = scrape()
+	assert.Contains(t, body, "atlas_connections{")
+	assert.Contains(t, body, "atlas_exporter_up 0\n")
+	assert.Contains(t, body, "atlas_exporter_refreshes_total 2\n")
+	assert.Contains(t, body, "atlas_exporter_refresh_failures_total 1\n")
+}
@@ -0,0 +1,111 @@
+package exporter
+
+import (
+	"bufio"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"atlas-sdk-go/internal/metrics"
+)
+
+// Prometheus metric types
+const (
+	TypeGauge   = "gauge"
+	TypeCounter = "counter"
+)
+
+// Label is a Prometheus label name and value.
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Sample is one value of a Prometheus metric family.
+type Sample struct {
+	Name   string
+	Help   string
+	Type   string
+	Labels []Label
+	Value  float64
+}
+
+// unitSuffix maps normalised series units to Prometheus base-unit suffixes.
+var unitSuffix = map[string]string{
+	metrics.UnitsSeconds:        "_seconds",
+	metrics.UnitsBytes:          "_bytes",
+	metrics.UnitsBytesPerSecond: "_bytes_per_second",
+	metrics.UnitsPercent:        "_percent",
+}
+
+// MetricName returns the Prometheus name of an Atlas measurement, e.g. DISK_PARTITION_SPACE_USED
+// in BYTES becomes atlas_disk_partition_space_used_bytes. The unit suffix is left off if the
+// measurement name already mentions it.
+func MetricName(measurement, units string) string {
+	name := "atlas_" + sanitize(measurement)
+	if suffix, ok := unitSuffix[units]; ok && !strings.Contains(name, suffix) {
+		name += suffix
+	}
+	return name
+}
+
+// WriteText writes samples in the Prometheus text exposition format. Samples are grouped into
+// families by name, in name order, keeping their order within a family. Each family takes its
+// help text and type from its first sample.
+func WriteText(w io.Writer, samples []Sample) error {
+	sorted := slices.Clone(samples)
+	slices.SortStableFunc(sorted, func(a, b Sample) int { return strings.Compare(a.Name, b.Name) })
+
+	bw := bufio.NewWriter(w)
+	for i, s := range sorted {
+		if i == 0 || sorted[i-1].Name != s.Name {
+			typ := s.Type
+			if typ == "" {
+				typ = TypeGauge
+			}
+			bw.WriteString("# HELP " + s.Name + " " + escapeHelp(s.Help) + "\n")
+			bw.WriteString("# TYPE " + s.Name + " " + typ + "\n")
+		}
+		bw.WriteString(s.Name)
+		if len(s.Labels) > 0 {
+			bw.WriteByte('{')
+			for j, l := range s.Labels {
+				if j > 0 {
+					bw.WriteByte(',')
+				}
+				bw.WriteString(l.Name + `="` + escapeLabelValue(l.Value) + `"`)
+			}
+			bw.WriteByte('}')
+		}
+		bw.WriteString(" " + strconv.FormatFloat(s.Value, 'g', -1, 64) + "\n")
+	}
+	return bw.Flush()
+}
+
+// sanitize lowercases s and replaces anything outside [a-z0-9_] with an underscore.
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + ('a' - 'A')
+		default:
+			return '_'
+		}
+	}, s)
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string {
+	return helpEscaper.Replace(s)
+}
+
+func escapeLabelValue(s string) string {
+	return labelEscaper.Replace(s)
+}
@@ -0,0 +1,261 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"atlas-sdk-go/internal/clusterutils"
+	"atlas-sdk-go/internal/config"
+	"atlas-sdk-go/internal/metrics"
+
+	"go.mongodb.org/atlas-sdk/v20250219001/admin"
+)
+
+// Status is a traffic-light health rating.
+type Status string
+
+// Health ratings, from best to worst
+const (
+	Green  Status = "GREEN"
+	Yellow Status = "YELLOW"
+	Red    Status = "RED"
+)
+
+// Process measurements used to rate a member
+const (
+	MetricReplicationLag = "OPLOG_REPLICATION_LAG_TIME"
+	MetricOplogWindow    = "OPLOG_MASTER_TIME"
+	MetricConnections    = "CONNECTIONS"
+	MetricTicketsReads   = "TICKETS_AVAILABLE_READS"
+	MetricTicketsWrites  = "TICKETS_AVAILABLE_WRITE"
+)
+
+// Measurements lists the process measurements fetched for every member.
+var Measurements = []string{MetricReplicationLag, MetricOplogWindow, MetricConnections, MetricTicketsReads, MetricTicketsWrites}
+
+// Thresholds exposes config within the health package while reusing config.HealthConfig.
+type Thresholds = config.HealthConfig
+
+// LoadThresholds returns the configured health thresholds with defaults for unset values.
+func LoadThresholds(cfg config.Config) Thresholds {
+	th := cfg.Health
+	if th.ReplicationLagWarnSeconds == 0 {
+		th.ReplicationLagWarnSeconds = 60
+	}
+	if th.ReplicationLagCritSeconds == 0 {
+		th.ReplicationLagCritSeconds = 300
+	}
+	if th.OplogWindowWarnHours == 0 {
+		th.OplogWindowWarnHours = 24
+	}
+	if th.OplogWindowCritHours == 0 {
+		th.OplogWindowCritHours = 8
+	}
+	if th.TicketsAvailableWarn == 0 {
+		th.TicketsAvailableWarn = 32
+	}
+	if th.TicketsAvailableCrit == 0 {
+		th.TicketsAvailableCrit = 8
+	}
+	return th
+}
+
+// Check is the rating of a single measurement or condition.
+type Check struct {
+	Name    string  `json:"name"`
+	Value   float64 `json:"value"`
+	Status  Status  `json:"status"`
+	Message string  `json:"message"`
+}
+
+// MemberHealth is the rating of one replica set member.
+type MemberHealth struct {
+	ID     string  `json:"id"` // hostname:port
+	Role   string  `json:"role"`
+	Region string  `json:"region,omitempty"`
+	Status Status  `json:"status"`
+	Checks []Check `json:"checks"`
+}
+
+// ReplicaSetHealth is the rating of a shard or config server replica set.
+type ReplicaSetHealth struct {
+	Name    string         `json:"name"`
+	Status  Status         `json:"status"`
+	Primary string         `json:"primary,omitempty"`
+	Issues  []string       `json:"issues,omitempty"`
+	Members []MemberHealth `json:"members"`
+}
+
+// Report is the health of every replica set in a cluster.
+type Report struct {
+	ProjectID   string             `json:"projectId"`
+	Cluster     string             `json:"cluster"`
+	GeneratedAt time.Time          `json:"generatedAt"`
+	Status      Status             `json:"status"`
+	ReplicaSets []ReplicaSetHealth `json:"replicaSets"`
+}
+
+// EvaluateCluster discovers a cluster's replica set members, fetches their recent process
+// measurements, and rates each member, replica set, and the cluster as a whole. A member whose
+// measurements cannot be fetched is rated yellow rather than failing the report.
+func EvaluateCluster(ctx context.Context, client *admin.APIClient, projectID, clusterName string, th Thresholds) (*Report, error) {
+	topology, err := clusterutils.GetClusterTopology(ctx, client, projectID, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	views := make(map[string]*admin.ApiMeasurementsGeneralViewAtlas)
+	for _, rs := range replicaSets(topology) {
+		for _, m := range rs.Members {
+			view, err := metrics.FetchProcessMetrics(ctx, client.MonitoringAndLogsApi, &admin.GetHostMeasurementsApiParams{
+				GroupId:     projectID,
+				ProcessId:   m.ID,
+				M:           &Measurements,
+				Granularity: admin.PtrString("PT1M"),
+				Period:      admin.PtrString("PT15M"),
+			})
+			if err != nil {
+				continue // rated as missing data by Evaluate
+			}
+			views[m.ID] = view
+		}
+	}
+	r := Evaluate(topology, views, th)
+	r.ProjectID = projectID
+	return r, nil
+}
+
+// Evaluate rates every shard and config server member of a topology from its process measurements,
+// keyed by process ID. Mongos processes are not rated. Atlas reports every config server as
+// SHARD_CONFIG, with no primary or secondary state, so the config server replica set is not
+// checked for a primary.
+func Evaluate(topology *clusterutils.Topology, views map[string]*admin.ApiMeasurementsGeneralViewAtlas, th Thresholds) *Report {
+	r := &Report{Cluster: topology.Cluster, GeneratedAt: time.Now().UTC(), Status: Green}
+	if len(topology.Shards) == 0 {
+		r.Status = Red
+		r.ReplicaSets = append(r.ReplicaSets, ReplicaSetHealth{Status: Red, Issues: []string{"no replica set members found"}})
+		return r
+	}
+	for _, rs := range replicaSets(topology) {
+		h := ReplicaSetHealth{Name: rs.Name, Status: Green}
+		config := false
+		for _, m := range rs.Members {
+			mh := EvaluateMember(m, views[m.ID], th)
+			h.Status = worst(h.Status, mh.Status)
+			h.Members = append(h.Members, mh)
+			if clusterutils.IsPrimary(m.Role) {
+				h.Primary = m.ID
+			}
+			config = config || clusterutils.IsConfig(m.Role)
+		}
+		if h.Primary == "" && !config {
+			h.Status = Red
+			h.Issues = append(h.Issues, "no primary")
+		}
+		r.Status = worst(r.Status, h.Status)
+		r.ReplicaSets = append(r.ReplicaSets, h)
+	}
+	return r
+}
+
+// EvaluateMember rates a member's state and its latest measurements, with times taken in the
+// measurements' declared units (seconds if undeclared). Replication lag is only rated on
+// secondaries. A nil view rates the member yellow for missing data.
+func EvaluateMember(m clusterutils.Member, view *admin.ApiMeasurementsGeneralViewAtlas, th Thresholds) MemberHealth {
+	h := MemberHealth{ID: m.ID, Role: m.Role, Region: m.Region}
+	h.Checks = append(h.Checks, stateCheck(m.Role))
+
+	if view == nil {
+		h.Checks = append(h.Checks, Check{Name: "metrics", Status: Yellow, Message: "no recent measurements"})
+	} else {
+		series := metrics.SeriesFromView(view)
+		for _, name := range Measurements {
+			p, ok := series[name].Latest()
+			if !ok {
+				continue
+			}
+			v := p.Value
+			switch name {
+			case MetricReplicationLag:
+				if clusterutils.IsSecondary(m.Role) {
+					h.Checks = append(h.Checks, above("replication lag", v, th.ReplicationLagWarnSeconds, th.ReplicationLagCritSeconds, "%.0fs"))
+				}
+			case MetricOplogWindow:
+				hours := v / 3600
+				h.Checks = append(h.Checks, below("oplog window", hours, th.OplogWindowWarnHours, th.OplogWindowCritHours, "%.1fh"))
+			case MetricConnections:
+				h.Checks = append(h.Checks, above("connections", v, th.ConnectionsWarn, th.ConnectionsCrit, "%.0f"))
+			case MetricTicketsReads:
+				h.Checks = append(h.Checks, below("read tickets available", v, th.TicketsAvailableWarn, th.TicketsAvailableCrit, "%.0f"))
+			case MetricTicketsWrites:
+				h.Checks = append(h.Checks, below("write tickets available", v, th.TicketsAvailableWarn, th.TicketsAvailableCrit, "%.0f"))
+			}
+		}
+	}
+
+	h.Status = Green
+	for _, c := range h.Checks {
+		h.Status = worst(h.Status, c.Status)
+	}
+	return h
+}
+
+// stateCheck rates a member by its Atlas process type: primaries, secondaries, and config servers
+// are healthy, recovering members are degraded, and anything else (such as NO_DATA) is down.
+func stateCheck(role string) Check {
+	c := Check{Name: "state", Status: Green, Message: role}
+	switch {
+	case clusterutils.IsPrimary(role), clusterutils.IsSecondary(role), clusterutils.IsConfig(role):
+	case role == "RECOVERING":
+		c.Status = Yellow
+	default:
+		c.Status = Red
+	}
+	return c
+}
+
+// above rates a value that is worse when higher. A threshold of zero or less is not rated.
+func above(name string, v, warn, crit float64, format string) Check {
+	c := Check{Name: name, Value: v, Status: Green, Message: fmt.Sprintf(format, v)}
+	switch {
+	case crit > 0 && v > crit:
+		c.Status = Red
+		c.Message += " > " + fmt.Sprintf(format, crit)
+	case warn > 0 && v > warn:
+		c.Status = Yellow
+		c.Message += " > " + fmt.Sprintf(format, warn)
+	}
+	return c
+}
+
+// below rates a value that is worse when lower. A threshold of zero or less is not rated.
+func below(name string, v, warn, crit float64, format string) Check {
+	c := Check{Name: name, Value: v, Status: Green, Message: fmt.Sprintf(format, v)}
+	switch {
+	case crit > 0 && v < crit:
+		c.Status = Red
+		c.Message += " < " + fmt.Sprintf(format, crit)
+	case warn > 0 && v < warn:
+		c.Status = Yellow
+		c.Message += " < " + fmt.Sprintf(format, warn)
+	}
+	return c
+}
+
+// replicaSets returns the shards and, for sharded clusters, the config server replica set.
+func replicaSets(t *clusterutils.Topology) []clusterutils.ReplicaSet {
+	out := append([]clusterutils.ReplicaSet{}, t.Shards...)
+	if t.ConfigServers != nil {
+		out = append(out, *t.ConfigServers)
+	}
+	return out
+}
+
+// worst returns the more severe of two ratings.
+func worst(a, b Status) Status {
+	rank := map[Status]int{Green: 0, Yellow: 1, Red: 2}
+	if rank[b] > rank[a] {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,156 @@
+package health
+
+import (
+	"testing"
+	"time"
+
+	"atlas-sdk-go/internal/clusterutils"
+	"atlas-sdk-go/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/atlas-sdk/v20250219001/admin"
+)
+
+func view(units map[string]string, values map[string]float32) *admin.ApiMeasurementsGeneralViewAtlas {
+	var ms []admin.MetricsMeasurementAtlas
+	for name, v := range values {
+		m := admin.MetricsMeasurementAtlas{
+			Name: admin.PtrString(name),
+			DataPoints: &[]admin.MetricDataPointAtlas{
+				{Timestamp: admin.PtrTime(time.Now().Add(-2 * time.Minute)), Value: admin.PtrFloat32(0)},
+				{Timestamp: admin.PtrTime(time.Now().Add(-time.Minute)), Value: admin.PtrFloat32(v)},
+				{Timestamp: admin.PtrTime(time.Now())}, // not yet reported
+			},
+		}
+		if u, ok := units[name]; ok {
+			m.Units = admin.PtrString(u)
+		}
+		ms = append(ms, m)
+	}
+	return &admin.ApiMeasurementsGeneralViewAtlas{Measurements: &ms}
+}
+
+func TestLoadThresholds(t *testing.T) {
+	t.Parallel()
+	th := LoadThresholds(config.Config{Health: config.HealthConfig{ReplicationLagWarnSeconds: 10, ConnectionsCrit: 500}})
+
+	assert.Equal(t, 10.0, th.ReplicationLagWarnSeconds)
+	assert.Equal(t, 300.0, th.ReplicationLagCritSeconds)
+	assert.Equal(t, 24.0, th.OplogWindowWarnHours)
+	assert.Equal(t, 500.0, th.ConnectionsCrit)
+	assert.Zero(t, th.ConnectionsWarn)
+}
+
+func TestEvaluateMember(t *testing.T) {
+	t.Parallel()
+	th := LoadThresholds(config.Config{Health: config.HealthConfig{ConnectionsWarn: 1000}})
+	cases := []struct {
+		name     string
+		role     string
+		view     *admin.ApiMeasurementsGeneralViewAtlas
+		expect   Status
+		problems []string
+	}{
+		{
+			name:   "healthy_secondary",
+			role:   "REPLICA_SECONDARY",
+			view:   view(map[string]string{MetricOplogWindow: "HOURS"}, map[string]float32{MetricReplicationLag: 2, MetricOplogWindow: 40, MetricTicketsReads: 120}),
+			expect: Green,
+		},
+		{
+			name:     "lagging_secondary_in_milliseconds",
+			role:     "REPLICA_SECONDARY",
+			view:     view(map[string]string{MetricReplicationLag: "MILLISECONDS"}, map[string]float32{MetricReplicationLag: 90_000}),
+			expect:   Yellow,
+			problems: []string{"replication lag: 90s > 60s"},
+		},
+		{
+			name:     "primary_lag_ignored_short_oplog",
+			role:     "REPLICA_PRIMARY",
+			view:     view(map[string]string{MetricOplogWindow: "SECONDS"}, map[string]float32{MetricReplicationLag: 900, MetricOplogWindow: 3600, MetricConnections: 1200}),
+			expect:   Red,
+			problems: []string{"oplog window: 1.0h < 8.0h"},
+		},
+		{
+			name:     "lagging_shard_secondary",
+			role:     "SHARD_SECONDARY",
+			view:     view(nil, map[string]float32{MetricReplicationLag: 400}),
+			expect:   Red,
+			problems: []string{"replication lag: 400s > 300s"},
+		},
+		{
+			name:   "config_server",
+			role:   "SHARD_CONFIG",
+			view:   view(map[string]string{MetricOplogWindow: "HOURS"}, map[string]float32{MetricOplogWindow: 40}),
+			expect: Green,
+		},
+		{
+			name:     "recovering_without_metrics",
+			role:     "RECOVERING",
+			expect:   Yellow,
+			problems: []string{"state: RECOVERING", "metrics: no recent measurements"},
+		},
+		{
+			name:   "no_data",
+			role:   "NO_DATA",
+			view:   view(nil, map[string]float32{MetricTicketsWrites: 4}),
+			expect: Red,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			h := EvaluateMember(clusterutils.Member{ID: "host:27017", Role: c.role}, c.view, th)
+			assert.Equal(t, c.expect, h.Status)
+			if c.problems != nil {
+				for _, p := range c.problems {
+					assert.Contains(t, h.Problems(), p)
+				}
+			}
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	t.Parallel()
+	th := LoadThresholds(config.Config{})
+	topology := &clusterutils.Topology{
+		Cluster: "Cluster0",
+		Shards: []clusterutils.ReplicaSet{
+			{Name: "atlas-abc-shard-0", Members: []clusterutils.Member{
+				{ID: "a0:27017", Role: "SHARD_PRIMARY"},
+				{ID: "a1:27017", Role: "SHARD_SECONDARY"},
+			}},
+			{Name: "atlas-abc-shard-1", Members: []clusterutils.Member{
+				{ID: "b0:27017", Role: "SHARD_SECONDARY"},
+				{ID: "b1:27017", Role: "SHARD_SECONDARY"},
+			}},
+		},
+		// Atlas reports every config server as SHARD_CONFIG
+		ConfigServers: &clusterutils.ReplicaSet{Name: "atlas-abc-config-0", Members: []clusterutils.Member{
+			{ID: "c0:27017", Role: "SHARD_CONFIG"},
+			{ID: "c1:27017", Role: "SHARD_CONFIG"},
+		}},
+		Mongos: []clusterutils.Member{{ID: "m0:27016", Role: "SHARD_MONGOS"}},
+	}
+	healthy := view(nil, map[string]float32{MetricOplogWindow: 48 * 3600})
+	views := map[string]*admin.ApiMeasurementsGeneralViewAtlas{
+		"a0:27017": healthy, "a1:27017": healthy, "b0:27017": healthy, "b1:27017": healthy, "c0:27017": healthy, "c1:27017": healthy,
+	}
+
+	r := Evaluate(topology, views, th)
+
+	require.Len(t, r.ReplicaSets, 3, "mongos are not rated")
+	assert.Equal(t, Green, r.ReplicaSets[0].Status)
+	assert.Equal(t, "a0:27017", r.ReplicaSets[0].Primary)
+	assert.Equal(t, Red, r.ReplicaSets[1].Status)
+	assert.Equal(t, []string{"no primary"}, r.ReplicaSets[1].Issues)
+	assert.Equal(t, Green, r.ReplicaSets[2].Status, "config servers have no primary state")
+	assert.Empty(t, r.ReplicaSets[2].Issues)
+	assert.Equal(t, Red, r.Status)
+
+	rows := r.Table()
+	assert.Len(t, rows, 1+1+6)
+	assert.Equal(t, []string{"Cluster0", "atlas-abc-shard-1", "", "", "RED", "no primary"}, rows[3])
+}
@@ -0,0 +1,43 @@
+package health
+
+import (
+	"strings"
+
+	"atlas-sdk-go/internal/data/export"
+)
+
+// Table returns one row per member, with the checks that are not green as details, and a header row.
+// Replica set issues such as a missing primary are reported on a row without a member.
+func (r *Report) Table() [][]string {
+	rows := [][]string{{"Cluster", "Replica Set", "Member", "Role", "Status", "Details"}}
+	for _, rs := range r.ReplicaSets {
+		if len(rs.Issues) > 0 {
+			rows = append(rows, []string{r.Cluster, rs.Name, "", "", string(rs.Status), strings.Join(rs.Issues, "; ")})
+		}
+		for _, m := range rs.Members {
+			rows = append(rows, []string{r.Cluster, rs.Name, m.ID, m.Role, string(m.Status), strings.Join(m.Problems(), "; ")})
+		}
+	}
+	return rows
+}
+
+// WriteCSV writes the report table to a CSV file at filePath.
+func (r *Report) WriteCSV(filePath string) error {
+	return export.ToCSV(r.Table(), filePath)
+}
+
+// WriteJSON writes the report to a JSON file at filePath.
+func (r *Report) WriteJSON(filePath string) error {
+	return export.ToJSON(r, filePath)
+}
+
+// Problems describes the member's checks that are not green, e.g. "replication lag: 412s > 300s".
+func (m MemberHealth) Problems() []string {
+	var out []string
+	for _, c := range m.Checks {
+		if c.Status != Green {
+			out = append(out, c.Name+": "+c.Message)
+		}
+	}
+	return out
+}
@@ -0,0 +1,172 @@
+package inventory
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"atlas-sdk-go/internal/data/export"
+)
+
+// Change kinds reported by Diff
+const (
+	ClusterAdded   = "cluster added"
+	ClusterRemoved = "cluster removed"
+	Modified       = "modified"
+)
+
+// Change is a single difference between two snapshots.
+type Change struct {
+	ProjectID   string `json:"projectId"`
+	ProjectName string `json:"projectName,omitempty"`
+	Cluster     string `json:"cluster"`
+	Kind        string `json:"kind"`
+	Field       string `json:"field,omitempty"` // e.g. "shard[0].AWS/US_EAST_1.electable.instanceSize"
+	Before      string `json:"before,omitempty"`
+	After       string `json:"after,omitempty"`
+}
+
+// DriftReport lists the changes between a baseline snapshot and a later one.
+type DriftReport struct {
+	BaselineTakenAt time.Time `json:"baselineTakenAt"`
+	CurrentTakenAt  time.Time `json:"currentTakenAt"`
+	Changes         []Change  `json:"changes"`
+}
+
+// HasDrift reports whether anything changed between the snapshots.
+func (r *DriftReport) HasDrift() bool {
+	return len(r.Changes) > 0
+}
+
+// Diff compares two snapshots cluster by cluster. Clusters are matched by project ID and name,
+// and region configs by shard, provider, and region, so reordering alone is not drift.
+// Changes are sorted by project, cluster, and field.
+func Diff(baseline, current *Snapshot) *DriftReport {
+	r := &DriftReport{BaselineTakenAt: baseline.TakenAt, CurrentTakenAt: current.TakenAt}
+	before := indexClusters(baseline)
+	after := indexClusters(current)
+
+	for _, k := range slices.Sorted(maps.Keys(before)) {
+		b := before[k]
+		a, ok := after[k]
+		if !ok {
+			r.Changes = append(r.Changes, Change{ProjectID: b.ProjectID, ProjectName: b.ProjectName, Cluster: b.Name, Kind: ClusterRemoved})
+			continue
+		}
+		bf, af := b.fields(), a.fields()
+		for _, f := range slices.Sorted(maps.Keys(union(bf, af))) {
+			if bf[f] != af[f] {
+				r.Changes = append(r.Changes, Change{
+					ProjectID: a.ProjectID, ProjectName: a.ProjectName, Cluster: a.Name,
+					Kind: Modified, Field: f, Before: bf[f], After: af[f],
+				})
+			}
+		}
+	}
+	for _, k := range slices.Sorted(maps.Keys(after)) {
+		if _, ok := before[k]; !ok {
+			a := after[k]
+			r.Changes = append(r.Changes, Change{ProjectID: a.ProjectID, ProjectName: a.ProjectName, Cluster: a.Name, Kind: ClusterAdded})
+		}
+	}
+	slices.SortStableFunc(r.Changes, func(x, y Change) int {
+		if x.ProjectID != y.ProjectID {
+			return strings.Compare(x.ProjectID, y.ProjectID)
+		}
+		if x.Cluster != y.Cluster {
+			return strings.Compare(x.Cluster, y.Cluster)
+		}
+		return strings.Compare(x.Field, y.Field)
+	})
+	return r
+}
+
+// Table returns the changes as rows with a header row.
+func (r *DriftReport) Table() [][]string {
+	rows := [][]string{{"Project", "Cluster", "Change", "Field", "Before", "After"}}
+	for _, c := range r.Changes {
+		project := c.ProjectName
+		if project == "" {
+			project = c.ProjectID
+		}
+		rows = append(rows, []string{project, c.Cluster, c.Kind, c.Field, c.Before, c.After})
+	}
+	return rows
+}
+
+// WriteCSV writes the changes to a CSV file at filePath.
+func (r *DriftReport) WriteCSV(filePath string) error {
+	return export.ToCSV(r.Table(), filePath)
+}
+
+// WriteJSON writes the report to a JSON file at filePath.
+func (r *DriftReport) WriteJSON(filePath string) error {
+	return export.ToJSON(r, filePath)
+}
+
+// fields flattens the cluster's configuration into comparable values keyed by field path.
+// The operational state and the full MongoDB version are left out: Atlas changes them on its
+// own (maintenance, patch upgrades), so only the configured major version counts as drift.
+func (c Cluster) fields() map[string]string {
+	f := map[string]string{
+		"clusterType":           c.ClusterType,
+		"paused":                strconv.FormatBool(c.Paused),
+		"mongoDBMajorVersion":   c.MongoDBMajorVersion,
+		"backupEnabled":         strconv.FormatBool(c.BackupEnabled),
+		"pitEnabled":            strconv.FormatBool(c.PitEnabled),
+		"terminationProtection": strconv.FormatBool(c.TerminationProtection),
+	}
+	for k, v := range c.Tags {
+		f["tags."+k] = v
+	}
+	for _, rc := range c.RegionConfigs {
+		p := fmt.Sprintf("shard[%d].%s/%s.", rc.Shard, rc.Provider, rc.Region)
+		f[p+"priority"] = strconv.Itoa(rc.Priority)
+		nodeFields(f, p+"electable.", rc.Electable)
+		nodeFields(f, p+"readOnly.", rc.ReadOnly)
+		nodeFields(f, p+"analytics.", rc.Analytics)
+		autoScalingFields(f, p+"autoScaling.", rc.AutoScaling)
+		autoScalingFields(f, p+"analyticsAutoScaling.", rc.AnalyticsAutoScaling)
+	}
+	return f
+}
+
+func nodeFields(f map[string]string, prefix string, n NodeSpec) {
+	f[prefix+"instanceSize"] = n.InstanceSize
+	f[prefix+"nodeCount"] = strconv.Itoa(n.NodeCount)
+	f[prefix+"diskSizeGB"] = strconv.FormatFloat(n.DiskSizeGB, 'f', -1, 64)
+	f[prefix+"diskIOPS"] = strconv.Itoa(n.DiskIOPS)
+	f[prefix+"ebsVolumeType"] = n.EbsVolumeType
+}
+
+func autoScalingFields(f map[string]string, prefix string, a AutoScaling) {
+	f[prefix+"computeEnabled"] = strconv.FormatBool(a.ComputeEnabled)
+	f[prefix+"scaleDownEnabled"] = strconv.FormatBool(a.ScaleDownEnabled)
+	f[prefix+"minInstanceSize"] = a.MinInstanceSize
+	f[prefix+"maxInstanceSize"] = a.MaxInstanceSize
+	f[prefix+"diskEnabled"] = strconv.FormatBool(a.DiskEnabled)
+}
+
+// indexClusters maps each cluster in s by project ID and name.
+func indexClusters(s *Snapshot) map[string]Cluster {
+	out := make(map[string]Cluster, len(s.Clusters))
+	for _, c := range s.Clusters {
+		out[c.key()] = c
+	}
+	return out
+}
+
+// union returns the set of keys present in either map.
+func union(a, b map[string]string) map[string]bool {
+	out := make(map[string]bool, len(a))
+	for k := range a {
+		out[k] = true
+	}
+	for k := range b {
+		out[k] = true
+	}
+	return out
+}
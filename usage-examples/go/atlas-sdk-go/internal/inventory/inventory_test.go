@@ -0,0 +1,155 @@
+package inventory
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	internalerrors "atlas-sdk-go/internal/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/atlas-sdk/v20250219001/admin"
+)
+
+func clusterDescription(project, name, tier string) admin.ClusterDescription20240805 {
+	return admin.ClusterDescription20240805{
+		GroupId:        admin.PtrString(project),
+		Name:           admin.PtrString(name),
+		ClusterType:    admin.PtrString("REPLICASET"),
+		StateName:      admin.PtrString("IDLE"),
+		MongoDBVersion: admin.PtrString("8.0.4"),
+		BackupEnabled:  admin.PtrBool(true),
+		Tags:           &[]admin.ResourceTag{{Key: "environment", Value: "production"}},
+		ReplicationSpecs: &[]admin.ReplicationSpec20240805{{RegionConfigs: &[]admin.CloudRegionConfig20240805{{
+			ProviderName: admin.PtrString("AWS"),
+			RegionName:   admin.PtrString("US_EAST_1"),
+			Priority:     admin.PtrInt(7),
+			ElectableSpecs: &admin.HardwareSpec20240805{
+				InstanceSize: admin.PtrString(tier),
+				NodeCount:    admin.PtrInt(3),
+				DiskSizeGB:   admin.PtrFloat64(40),
+				DiskIOPS:     admin.PtrInt(3000),
+			},
+			AutoScaling: &admin.AdvancedAutoScalingSettings{
+				Compute: &admin.AdvancedComputeAutoScaling{Enabled: admin.PtrBool(true), MaxInstanceSize: admin.PtrString("M50")},
+				DiskGB:  &admin.DiskGBAutoScaling{Enabled: admin.PtrBool(true)},
+			},
+		}}}},
+	}
+}
+
+func TestFromCluster(t *testing.T) {
+	t.Parallel()
+
+	c := FromCluster("Payments", clusterDescription("p1", "Cluster0", "M30"))
+
+	assert.Equal(t, "p1", c.ProjectID)
+	assert.Equal(t, "8.0.4", c.MongoDBVersion)
+	assert.True(t, c.BackupEnabled)
+	assert.Equal(t, map[string]string{"environment": "production"}, c.Tags)
+	require.Len(t, c.RegionConfigs, 1)
+	rc := c.RegionConfigs[0]
+	assert.Equal(t, NodeSpec{InstanceSize: "M30", NodeCount: 3, DiskSizeGB: 40, DiskIOPS: 3000}, rc.Electable)
+	assert.Equal(t, 0, rc.ReadOnly.NodeCount)
+	assert.Equal(t, AutoScaling{ComputeEnabled: true, MaxInstanceSize: "M50", DiskEnabled: true}, rc.AutoScaling)
+}
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+	t0 := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	baseline := &Snapshot{SchemaVersion: SchemaVersion, TakenAt: t0, Clusters: []Cluster{
+		FromCluster("Payments", clusterDescription("p1", "Cluster0", "M30")),
+		FromCluster("Payments", clusterDescription("p1", "Legacy", "M10")),
+	}}
+	scaled := clusterDescription("p1", "Cluster0", "M40")
+	scaled.PitEnabled = admin.PtrBool(true)
+	// State and patch upgrades are not configuration drift
+	scaled.StateName = admin.PtrString("UPDATING")
+	scaled.MongoDBVersion = admin.PtrString("8.0.5")
+	current := &Snapshot{SchemaVersion: SchemaVersion, TakenAt: t0.Add(24 * time.Hour), Clusters: []Cluster{
+		FromCluster("Payments", clusterDescription("p1", "Analytics", "M10")),
+		FromCluster("Payments", scaled),
+	}}
+
+	r := Diff(baseline, current)
+
+	require.True(t, r.HasDrift())
+	require.Len(t, r.Changes, 4)
+	assert.Equal(t, Change{ProjectID: "p1", ProjectName: "Payments", Cluster: "Analytics", Kind: ClusterAdded}, r.Changes[0])
+	assert.Equal(t, Change{
+		ProjectID: "p1", ProjectName: "Payments", Cluster: "Cluster0", Kind: Modified,
+		Field: "pitEnabled", Before: "false", After: "true",
+	}, r.Changes[1])
+	assert.Equal(t, "shard[0].AWS/US_EAST_1.electable.instanceSize", r.Changes[2].Field)
+	assert.Equal(t, "M30", r.Changes[2].Before)
+	assert.Equal(t, "M40", r.Changes[2].After)
+	assert.Equal(t, ClusterRemoved, r.Changes[3].Kind)
+
+	assert.False(t, Diff(baseline, baseline).HasDrift())
+	assert.Equal(t, []string{"Payments", "Legacy", ClusterRemoved, "", "", ""}, r.Table()[4])
+}
+
+func TestSnapshot_SaveAndLoad(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "inventory.json")
+	s := &Snapshot{SchemaVersion: SchemaVersion, TakenAt: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), OrgID: "org1",
+		Clusters: []Cluster{FromCluster("Payments", clusterDescription("p1", "Cluster0", "M30"))}}
+
+	require.NoError(t, s.Save(path))
+	loaded, err := Load(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, s, loaded)
+
+	_, err = Load(filepath.Join(dir, "missing.json"))
+	var nfErr *internalerrors.NotFoundError
+	assert.ErrorAs(t, err, &nfErr)
+}
+
+func TestCollect_AllOrgProjects(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var body any
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/orgs/org1/groups"):
+			body = admin.PaginatedAtlasGroup{
+				Results:    &[]admin.Group{{Id: admin.PtrString("p2"), Name: "Search"}, {Id: admin.PtrString("p1"), Name: "Payments"}},
+				TotalCount: admin.PtrInt(2),
+			}
+		case strings.HasSuffix(r.URL.Path, "/groups/p1/clusters"):
+			body = admin.PaginatedClusterDescription20240805{
+				Results:    &[]admin.ClusterDescription20240805{clusterDescription("p1", "Cluster0", "M30")},
+				TotalCount: admin.PtrInt(1),
+			}
+		case strings.HasSuffix(r.URL.Path, "/groups/p2/clusters"):
+			body = admin.PaginatedClusterDescription20240805{
+				Results:    &[]admin.ClusterDescription20240805{clusterDescription("p2", "Index", "M20")},
+				TotalCount: admin.PtrInt(1),
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(body))
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	t.Cleanup(server.Close)
+	client, err := admin.NewClient(admin.UseBaseURL(server.URL))
+	require.NoError(t, err)
+
+	s, err := Collect(context.Background(), client, "org1", nil)
+
+	require.NoError(t, err)
+	require.Len(t, s.Clusters, 2)
+	assert.Equal(t, "Payments", s.Clusters[0].ProjectName, "clusters should be sorted by project")
+	assert.Equal(t, "Index", s.Clusters[1].Name)
+	assert.Equal(t, SchemaVersion, s.SchemaVersion)
+}
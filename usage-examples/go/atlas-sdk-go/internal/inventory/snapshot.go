@@ -0,0 +1,236 @@
+package inventory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"atlas-sdk-go/internal/clusterutils"
+	"atlas-sdk-go/internal/data/export"
+	"atlas-sdk-go/internal/errors"
+	"atlas-sdk-go/internal/pagination"
+
+	"go.mongodb.org/atlas-sdk/v20250219001/admin"
+)
+
+// SchemaVersion is the version of the snapshot file format.
+const SchemaVersion = 1
+
+// Snapshot is the state of every cluster in a set of projects at a point in time.
+type Snapshot struct {
+	SchemaVersion int       `json:"schemaVersion"`
+	TakenAt       time.Time `json:"takenAt"`
+	OrgID         string    `json:"orgId,omitempty"`
+	Clusters      []Cluster `json:"clusters"`
+}
+
+// Cluster is the recorded state of a single cluster.
+type Cluster struct {
+	ProjectID             string            `json:"projectId"`
+	ProjectName           string            `json:"projectName,omitempty"`
+	Name                  string            `json:"name"`
+	ClusterType           string            `json:"clusterType"`
+	State                 string            `json:"state"`
+	Paused                bool              `json:"paused"`
+	MongoDBVersion        string            `json:"mongoDBVersion"`
+	MongoDBMajorVersion   string            `json:"mongoDBMajorVersion"`
+	BackupEnabled         bool              `json:"backupEnabled"`
+	PitEnabled            bool              `json:"pitEnabled"`
+	TerminationProtection bool              `json:"terminationProtection"`
+	Tags                  map[string]string `json:"tags,omitempty"`
+	RegionConfigs         []RegionConfig    `json:"regionConfigs"`
+}
+
+// RegionConfig is the hardware and auto-scaling configuration of one region of one shard (replication spec).
+type RegionConfig struct {
+	Shard                int         `json:"shard"` // Index of the replication spec
+	ZoneName             string      `json:"zoneName,omitempty"`
+	Provider             string      `json:"provider"`
+	Region               string      `json:"region"`
+	Priority             int         `json:"priority"`
+	Electable            NodeSpec    `json:"electable"`
+	ReadOnly             NodeSpec    `json:"readOnly"`
+	Analytics            NodeSpec    `json:"analytics"`
+	AutoScaling          AutoScaling `json:"autoScaling"`
+	AnalyticsAutoScaling AutoScaling `json:"analyticsAutoScaling"`
+}
+
+// NodeSpec is the hardware of one node type.
+type NodeSpec struct {
+	InstanceSize  string  `json:"instanceSize,omitempty"`
+	NodeCount     int     `json:"nodeCount"`
+	DiskSizeGB    float64 `json:"diskSizeGB,omitempty"`
+	DiskIOPS      int     `json:"diskIOPS,omitempty"`
+	EbsVolumeType string  `json:"ebsVolumeType,omitempty"`
+}
+
+// AutoScaling holds compute and disk auto-scaling settings.
+type AutoScaling struct {
+	ComputeEnabled   bool   `json:"computeEnabled"`
+	ScaleDownEnabled bool   `json:"scaleDownEnabled"`
+	MinInstanceSize  string `json:"minInstanceSize,omitempty"`
+	MaxInstanceSize  string `json:"maxInstanceSize,omitempty"`
+	DiskEnabled      bool   `json:"diskEnabled"`
+}
+
+// Collect takes a snapshot of the clusters in projectIDs, or of every project in the organization
+// if projectIDs is empty. Clusters are sorted by project and name so snapshots compare cleanly.
+func Collect(ctx context.Context, client *admin.APIClient, orgID string, projectIDs []string) (*Snapshot, error) {
+	if client == nil {
+		return nil, fmt.Errorf("nil atlas api client")
+	}
+	projectNames := make(map[string]string)
+	if len(projectIDs) == 0 {
+		if orgID == "" {
+			return nil, &errors.ValidationError{Message: "an organization ID or project IDs are required"}
+		}
+		projects, err := listOrgProjects(ctx, client.OrganizationsApi, orgID)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range projects {
+			projectIDs = append(projectIDs, p.GetId())
+			projectNames[p.GetId()] = p.GetName()
+		}
+	}
+
+	s := &Snapshot{SchemaVersion: SchemaVersion, TakenAt: time.Now().UTC(), OrgID: orgID}
+	for _, projectID := range projectIDs {
+		clusters, err := clusterutils.ListAllClusters(ctx, client.ClustersApi, &admin.ListClustersApiParams{GroupId: projectID})
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range clusters {
+			s.Clusters = append(s.Clusters, FromCluster(projectNames[projectID], c))
+		}
+	}
+	s.sort()
+	return s, nil
+}
+
+// FromCluster records the state of a cluster description.
+func FromCluster(projectName string, c admin.ClusterDescription20240805) Cluster {
+	out := Cluster{
+		ProjectID:             c.GetGroupId(),
+		ProjectName:           projectName,
+		Name:                  c.GetName(),
+		ClusterType:           c.GetClusterType(),
+		State:                 c.GetStateName(),
+		Paused:                c.GetPaused(),
+		MongoDBVersion:        c.GetMongoDBVersion(),
+		MongoDBMajorVersion:   c.GetMongoDBMajorVersion(),
+		BackupEnabled:         c.GetBackupEnabled(),
+		PitEnabled:            c.GetPitEnabled(),
+		TerminationProtection: c.GetTerminationProtectionEnabled(),
+	}
+	for _, t := range c.GetTags() {
+		if out.Tags == nil {
+			out.Tags = make(map[string]string)
+		}
+		out.Tags[t.GetKey()] = t.GetValue()
+	}
+	for i, spec := range c.GetReplicationSpecs() {
+		for _, rc := range spec.GetRegionConfigs() {
+			es := rc.ElectableSpecs
+			out.RegionConfigs = append(out.RegionConfigs, RegionConfig{
+				Shard:    i,
+				ZoneName: spec.GetZoneName(),
+				Provider: rc.GetProviderName(),
+				Region:   rc.GetRegionName(),
+				Priority: rc.GetPriority(),
+				Electable: NodeSpec{
+					InstanceSize:  es.GetInstanceSize(),
+					NodeCount:     es.GetNodeCount(),
+					DiskSizeGB:    es.GetDiskSizeGB(),
+					DiskIOPS:      es.GetDiskIOPS(),
+					EbsVolumeType: es.GetEbsVolumeType(),
+				},
+				ReadOnly:             dedicatedSpec(rc.ReadOnlySpecs),
+				Analytics:            dedicatedSpec(rc.AnalyticsSpecs),
+				AutoScaling:          autoScaling(rc.AutoScaling),
+				AnalyticsAutoScaling: autoScaling(rc.AnalyticsAutoScaling),
+			})
+		}
+	}
+	return out
+}
+
+// Save writes the snapshot to a JSON file at filePath.
+func (s *Snapshot) Save(filePath string) error {
+	return export.ToJSON(s, filePath)
+}
+
+// Load reads a snapshot written by Save.
+func Load(filePath string) (*Snapshot, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, &errors.NotFoundError{Resource: "inventory snapshot", ID: filePath}
+		}
+		return nil, errors.WithContext(err, "reading inventory snapshot")
+	}
+	var s Snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, errors.WithContext(err, "parsing inventory snapshot")
+	}
+	if s.SchemaVersion != SchemaVersion {
+		return nil, &errors.ValidationError{Message: fmt.Sprintf("unsupported inventory snapshot version %d", s.SchemaVersion)}
+	}
+	return &s, nil
+}
+
+// sort orders clusters by project and name.
+func (s *Snapshot) sort() {
+	slices.SortFunc(s.Clusters, func(a, b Cluster) int {
+		return strings.Compare(a.key(), b.key())
+	})
+}
+
+// key identifies a cluster across snapshots.
+func (c Cluster) key() string {
+	return c.ProjectID + "/" + c.Name
+}
+
+// dedicatedSpec converts read-only or analytics hardware.
+func dedicatedSpec(s *admin.DedicatedHardwareSpec20240805) NodeSpec {
+	return NodeSpec{
+		InstanceSize:  s.GetInstanceSize(),
+		NodeCount:     s.GetNodeCount(),
+		DiskSizeGB:    s.GetDiskSizeGB(),
+		DiskIOPS:      s.GetDiskIOPS(),
+		EbsVolumeType: s.GetEbsVolumeType(),
+	}
+}
+
+// autoScaling converts auto-scaling settings.
+func autoScaling(a *admin.AdvancedAutoScalingSettings) AutoScaling {
+	compute := a.GetCompute()
+	disk := a.GetDiskGB()
+	return AutoScaling{
+		ComputeEnabled:   compute.GetEnabled(),
+		ScaleDownEnabled: compute.GetScaleDownEnabled(),
+		MinInstanceSize:  compute.GetMinInstanceSize(),
+		MaxInstanceSize:  compute.GetMaxInstanceSize(),
+		DiskEnabled:      disk.GetEnabled(),
+	}
+}
+
+// listOrgProjects collects every project in an organization.
+func listOrgProjects(ctx context.Context, sdk admin.OrganizationsApi, orgID string) ([]admin.Group, error) {
+	fetch := func(ctx context.Context, pageNum, itemsPerPage int) ([]admin.Group, int, error) {
+		r, _, err := sdk.ListOrganizationProjects(ctx, orgID).
+			PageNum(pageNum).
+			ItemsPerPage(itemsPerPage).
+			IncludeCount(true).
+			Execute()
+		if err != nil {
+			return nil, 0, errors.FormatError("list organization projects", orgID, err)
+		}
+		return r.GetResults(), r.GetTotalCount(), nil
+	}
+	return pagination.Collect(pagination.Iterate(ctx, fetch, 1, 0))
+}
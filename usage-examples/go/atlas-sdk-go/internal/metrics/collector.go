@@ -0,0 +1,290 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"atlas-sdk-go/internal/clusterutils"
+	"atlas-sdk-go/internal/errors"
+	"atlas-sdk-go/internal/pagination"
+
+	"go.mongodb.org/atlas-sdk/v20250219001/admin"
+)
+
+// Defaults for CollectOptions
+const (
+	DefaultCollectWorkers = 8
+	DefaultRequestTimeout = 30 * time.Second
+)
+
+// Kinds of measurement targets
+const (
+	TargetProcess  = "process"
+	TargetDisk     = "disk"
+	TargetDatabase = "database"
+	// TargetNamespace is reported in errors from RankDatabases; Collect does not fetch namespaces.
+	TargetNamespace = "namespace"
+)
+
+// Target identifies the process, disk partition, or database that one measurement request reads.
+type Target struct {
+	Kind       string `json:"kind"`
+	ProcessID  string `json:"processId"` // hostname:port
+	Partition  string `json:"partition,omitempty"`
+	Database   string `json:"database,omitempty"`
+	Collection string `json:"collection,omitempty"`
+}
+
+// String returns the target as "host:port", "host:port/disk/<partition>", "host:port/database/<name>",
+// or "host:port/namespace/<database>.<collection>".
+func (t Target) String() string {
+	switch t.Kind {
+	case TargetDisk:
+		return t.ProcessID + "/disk/" + t.Partition
+	case TargetDatabase:
+		return t.ProcessID + "/database/" + t.Database
+	case TargetNamespace:
+		return t.ProcessID + "/namespace/" + t.Database + "." + t.Collection
+	default:
+		return t.ProcessID
+	}
+}
+
+// TargetError is a failure to discover or fetch one target. It does not stop the rest of the collection.
+type TargetError struct {
+	Target Target
+	Err    error
+}
+
+func (e *TargetError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Target, e.Err)
+}
+
+func (e *TargetError) Unwrap() error {
+	return e.Err
+}
+
+// CollectOptions controls which measurements are collected and how many requests run at once.
+// Targets of a kind with no measurements listed are neither discovered nor fetched.
+type CollectOptions struct {
+	ProcessMeasurements  []string
+	DiskMeasurements     []string
+	DatabaseMeasurements []string
+	Granularity          string // e.g. "PT1M"
+	Period               string // e.g. "PT1H"
+	// Workers is the number of requests in flight at once (DefaultCollectWorkers if <= 0).
+	Workers int
+	// RequestTimeout bounds each request (DefaultRequestTimeout if <= 0).
+	RequestTimeout time.Duration
+}
+
+// Result holds the measurements fetched for one target, keyed by measurement name.
+type Result struct {
+	Target Target            `json:"target"`
+	Series map[string]Series `json:"series"`
+}
+
+// Collection holds the results and per-target errors of a collection, each in target order.
+type Collection struct {
+	Results []Result
+	Errors  []TargetError
+}
+
+// CollectProject discovers every process in a project, with its disk partitions and databases,
+// and fetches the requested measurements for each. Failures to list a process's partitions or
+// databases are reported in the collection's errors; only failing to list the processes is an error.
+func CollectProject(ctx context.Context, sdk admin.MonitoringAndLogsApi, projectID string, opts CollectOptions) (*Collection, error) {
+	targets, discoveryErrs, err := DiscoverTargets(ctx, sdk, projectID, opts)
+	if err != nil {
+		return nil, err
+	}
+	c := Collect(ctx, sdk, projectID, targets, opts)
+	c.Errors = append(discoveryErrs, c.Errors...)
+	return c, nil
+}
+
+// DiscoverTargets lists the processes in a project and, for each mongod, its disk partitions and
+// databases, as needed by the measurements in opts. Partitions and databases are listed concurrently.
+func DiscoverTargets(ctx context.Context, sdk admin.MonitoringAndLogsApi, projectID string, opts CollectOptions) ([]Target, []TargetError, error) {
+	if projectID == "" {
+		return nil, nil, &errors.ValidationError{Message: "project ID is required"}
+	}
+	processes, err := clusterutils.ListAllProcesses(ctx, sdk, &admin.ListAtlasProcessesApiParams{GroupId: projectID})
+	if err != nil {
+		return nil, nil, err
+	}
+	targets, targetErrs := DiscoverProcessTargets(ctx, sdk, projectID, processes, opts)
+	return targets, targetErrs, nil
+}
+
+// DiscoverProcessTargets is DiscoverTargets for processes that have already been listed.
+func DiscoverProcessTargets(ctx context.Context, sdk admin.MonitoringAndLogsApi, projectID string, processes []admin.ApiHostViewAtlas, opts CollectOptions) ([]Target, []TargetError) {
+	var targets []Target
+	var mongods []string
+	for _, p := range processes {
+		id := p.GetId()
+		if id == "" {
+			continue
+		}
+		if len(opts.ProcessMeasurements) > 0 {
+			targets = append(targets, Target{Kind: TargetProcess, ProcessID: id})
+		}
+		if p.GetTypeName() != "SHARD_MONGOS" {
+			mongods = append(mongods, id)
+		}
+	}
+
+	// One listing job per mongod and kind; each job fills its own slot so output order is stable
+	type job struct {
+		kind, processID string
+	}
+	var jobs []job
+	for _, id := range mongods {
+		if len(opts.DiskMeasurements) > 0 {
+			jobs = append(jobs, job{TargetDisk, id})
+		}
+		if len(opts.DatabaseMeasurements) > 0 {
+			jobs = append(jobs, job{TargetDatabase, id})
+		}
+	}
+	found := make([][]Target, len(jobs))
+	errs := make([]error, len(jobs))
+	forEach(ctx, len(jobs), opts.Workers, func(ctx context.Context, i int) {
+		ctx, cancel := context.WithTimeout(ctx, requestTimeout(opts))
+		defer cancel()
+		found[i], errs[i] = listTargets(ctx, sdk, projectID, jobs[i].kind, jobs[i].processID)
+	})
+
+	var targetErrs []TargetError
+	for i, j := range jobs {
+		if errs[i] != nil {
+			targetErrs = append(targetErrs, TargetError{Target: Target{Kind: j.kind, ProcessID: j.processID}, Err: errs[i]})
+			continue
+		}
+		targets = append(targets, found[i]...)
+	}
+	return targets, targetErrs
+}
+
+// Collect fetches the measurements in opts for every target, with at most opts.Workers requests
+// in flight and each request bounded by opts.RequestTimeout.
+func Collect(ctx context.Context, sdk admin.MonitoringAndLogsApi, projectID string, targets []Target, opts CollectOptions) *Collection {
+	views := make([]*admin.ApiMeasurementsGeneralViewAtlas, len(targets))
+	errs := make([]error, len(targets))
+	forEach(ctx, len(targets), opts.Workers, func(ctx context.Context, i int) {
+		ctx, cancel := context.WithTimeout(ctx, requestTimeout(opts))
+		defer cancel()
+		views[i], errs[i] = fetchTarget(ctx, sdk, projectID, targets[i], opts)
+	})
+
+	c := &Collection{}
+	for i, t := range targets {
+		if errs[i] != nil {
+			c.Errors = append(c.Errors, TargetError{Target: t, Err: errs[i]})
+			continue
+		}
+		c.Results = append(c.Results, Result{Target: t, Series: SeriesFromView(views[i])})
+	}
+	return c
+}
+
+// fetchTarget requests the measurements for one target.
+func fetchTarget(ctx context.Context, sdk admin.MonitoringAndLogsApi, projectID string, t Target, opts CollectOptions) (*admin.ApiMeasurementsGeneralViewAtlas, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	switch t.Kind {
+	case TargetProcess:
+		return FetchProcessMetrics(ctx, sdk, &admin.GetHostMeasurementsApiParams{
+			GroupId: projectID, ProcessId: t.ProcessID, M: &opts.ProcessMeasurements,
+			Granularity: &opts.Granularity, Period: &opts.Period,
+		})
+	case TargetDisk:
+		return FetchDiskMetrics(ctx, sdk, &admin.GetDiskMeasurementsApiParams{
+			GroupId: projectID, ProcessId: t.ProcessID, PartitionName: t.Partition, M: &opts.DiskMeasurements,
+			Granularity: &opts.Granularity, Period: &opts.Period,
+		})
+	case TargetDatabase:
+		return FetchDatabaseMetrics(ctx, sdk, &admin.GetDatabaseMeasurementsApiParams{
+			GroupId: projectID, ProcessId: t.ProcessID, DatabaseName: t.Database, M: &opts.DatabaseMeasurements,
+			Granularity: &opts.Granularity, Period: &opts.Period,
+		})
+	default:
+		return nil, &errors.ValidationError{Message: fmt.Sprintf("unknown target kind %q", t.Kind)}
+	}
+}
+
+// ListDiskPartitions returns a disk target for every partition of a process.
+func ListDiskPartitions(ctx context.Context, sdk admin.MonitoringAndLogsApi, projectID, processID string) ([]Target, error) {
+	return listTargets(ctx, sdk, projectID, TargetDisk, processID)
+}
+
+// listTargets lists every disk partition or database of a process as targets.
+func listTargets(ctx context.Context, sdk admin.MonitoringAndLogsApi, projectID, kind, processID string) ([]Target, error) {
+	var fetch func(ctx context.Context, pageNum, itemsPerPage int) ([]Target, int, error)
+	switch kind {
+	case TargetDisk:
+		fetch = func(ctx context.Context, pageNum, itemsPerPage int) ([]Target, int, error) {
+			r, _, err := sdk.ListDiskPartitions(ctx, projectID, processID).
+				PageNum(pageNum).ItemsPerPage(itemsPerPage).IncludeCount(true).Execute()
+			if err != nil {
+				return nil, 0, errors.FormatError("list disk partitions", processID, err)
+			}
+			var out []Target
+			for _, d := range r.GetResults() {
+				out = append(out, Target{Kind: TargetDisk, ProcessID: processID, Partition: d.GetPartitionName()})
+			}
+			return out, r.GetTotalCount(), nil
+		}
+	case TargetDatabase:
+		fetch = func(ctx context.Context, pageNum, itemsPerPage int) ([]Target, int, error) {
+			r, _, err := sdk.ListDatabases(ctx, projectID, processID).
+				PageNum(pageNum).ItemsPerPage(itemsPerPage).IncludeCount(true).Execute()
+			if err != nil {
+				return nil, 0, errors.FormatError("list databases", processID, err)
+			}
+			var out []Target
+			for _, d := range r.GetResults() {
+				out = append(out, Target{Kind: TargetDatabase, ProcessID: processID, Database: d.GetDatabaseName()})
+			}
+			return out, r.GetTotalCount(), nil
+		}
+	}
+	return pagination.Collect(pagination.Iterate(ctx, fetch, 0, 0))
+}
+
+// forEach calls fn for indexes 0..n-1 with at most workers calls running at once
+// (DefaultCollectWorkers if workers <= 0). Every index is passed to fn, so fn must return
+// promptly once ctx is done and record the context error for its index.
+func forEach(ctx context.Context, n, workers int, fn func(ctx context.Context, i int)) {
+	if workers <= 0 {
+		workers = DefaultCollectWorkers
+	}
+	workers = min(workers, n)
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fn(ctx, i)
+			}
+		}()
+	}
+	for i := range n {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+func requestTimeout(opts CollectOptions) time.Duration {
+	if opts.RequestTimeout <= 0 {
+		return DefaultRequestTimeout
+	}
+	return opts.RequestTimeout
+}
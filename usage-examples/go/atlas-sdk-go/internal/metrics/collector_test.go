@@ -0,0 +1,190 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/atlas-sdk/v20250219001/admin"
+)
+
+func newTestAtlasClient(t *testing.T, handler http.HandlerFunc) *admin.APIClient {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	client, err := admin.NewClient(admin.UseBaseURL(server.URL))
+	require.NoError(t, err)
+	return client
+}
+
+// measurementsView returns a single-point view for each named measurement.
+func measurementsView(t *testing.T, names ...string) *admin.ApiMeasurementsGeneralViewAtlas {
+	var ms []admin.MetricsMeasurementAtlas
+	for _, n := range names {
+		ms = append(ms, admin.MetricsMeasurementAtlas{
+			Name: admin.PtrString(n),
+			DataPoints: &[]admin.MetricDataPointAtlas{{
+				Timestamp: admin.PtrTime(parseTS(t, fixedTS)),
+				Value:     admin.PtrFloat32(1)}}})
+	}
+	return &admin.ApiMeasurementsGeneralViewAtlas{Measurements: &ms}
+}
+
+func TestCollectProject(t *testing.T) {
+	t.Parallel()
+	const prefix = "/api/atlas/v2/groups/proj1/processes"
+
+	var mu sync.Mutex
+	var requested []string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested = append(requested, r.URL.Path)
+		mu.Unlock()
+
+		var body any
+		switch p := strings.TrimPrefix(r.URL.Path, prefix); p {
+		case "":
+			body = admin.PaginatedHostViewAtlas{TotalCount: admin.PtrInt(3), Results: &[]admin.ApiHostViewAtlas{
+				{Id: admin.PtrString("m1:27017"), TypeName: admin.PtrString("REPLICA_PRIMARY")},
+				{Id: admin.PtrString("m2:27017"), TypeName: admin.PtrString("REPLICA_SECONDARY")},
+				{Id: admin.PtrString("mongos:27016"), TypeName: admin.PtrString("SHARD_MONGOS")},
+			}}
+		case "/m1:27017/disks", "/m2:27017/disks":
+			body = admin.PaginatedDiskPartition{TotalCount: admin.PtrInt(1), Results: &[]admin.MeasurementDiskPartition{
+				{PartitionName: admin.PtrString("data")},
+			}}
+		case "/m1:27017/databases":
+			body = admin.PaginatedDatabase{TotalCount: admin.PtrInt(1), Results: &[]admin.MesurementsDatabase{
+				{DatabaseName: admin.PtrString("app")},
+			}}
+		case "/m1:27017/measurements", "/m2:27017/measurements":
+			body = measurementsView(t, "CONNECTIONS")
+		case "/m1:27017/disks/data/measurements", "/m2:27017/disks/data/measurements":
+			body = measurementsView(t, "DISK_PARTITION_IOPS_READ")
+		case "/m1:27017/databases/app/measurements":
+			body = measurementsView(t, "DATABASE_DATA_SIZE")
+		default: // m2 databases and mongos measurements fail
+			http.Error(w, `{"error":500}`, http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		assert.NoError(t, json.NewEncoder(w).Encode(body))
+	}
+	client := newTestAtlasClient(t, handler)
+
+	c, err := CollectProject(context.Background(), client.MonitoringAndLogsApi, "proj1", CollectOptions{
+		ProcessMeasurements:  []string{"CONNECTIONS"},
+		DiskMeasurements:     []string{"DISK_PARTITION_IOPS_READ"},
+		DatabaseMeasurements: []string{"DATABASE_DATA_SIZE"},
+		Granularity:          "PT1M",
+		Period:               "PT1H",
+		Workers:              3,
+	})
+	require.NoError(t, err)
+
+	var got []string
+	for _, r := range c.Results {
+		got = append(got, r.Target.String())
+		assert.Len(t, r.Series, 1, r.Target.String())
+	}
+	assert.Equal(t, []string{
+		"m1:27017", "m2:27017",
+		"m1:27017/disk/data", "m1:27017/database/app", "m2:27017/disk/data",
+	}, got)
+
+	require.Len(t, c.Errors, 2)
+	assert.Equal(t, Target{Kind: TargetDatabase, ProcessID: "m2:27017"}, c.Errors[0].Target)
+	assert.Equal(t, Target{Kind: TargetProcess, ProcessID: "mongos:27016"}, c.Errors[1].Target)
+
+	for _, p := range requested {
+		assert.NotContains(t, p, "mongos:27016/disks")
+		assert.NotContains(t, p, "mongos:27016/databases")
+	}
+}
+
+func TestCollectProject_RequiresProjectID(t *testing.T) {
+	t.Parallel()
+	_, err := CollectProject(context.Background(), nil, "", CollectOptions{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "project ID is required")
+}
+
+func TestCollect_WorkerLimit(t *testing.T) {
+	t.Parallel()
+	var inFlight, maxInFlight atomic.Int32
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		assert.NoError(t, json.NewEncoder(w).Encode(measurementsView(t, "CONNECTIONS")))
+	}
+	client := newTestAtlasClient(t, handler)
+
+	var targets []Target
+	for _, id := range []string{"a:1", "b:1", "c:1", "d:1", "e:1", "f:1"} {
+		targets = append(targets, Target{Kind: TargetProcess, ProcessID: id})
+	}
+	c := Collect(context.Background(), client.MonitoringAndLogsApi, "proj1", targets, CollectOptions{
+		ProcessMeasurements: []string{"CONNECTIONS"}, Granularity: "PT1M", Period: "PT1H", Workers: 2,
+	})
+
+	assert.Empty(t, c.Errors)
+	require.Len(t, c.Results, len(targets))
+	for i, r := range c.Results {
+		assert.Equal(t, targets[i], r.Target)
+	}
+	assert.LessOrEqual(t, maxInFlight.Load(), int32(2))
+}
+
+func TestCollect_RequestTimeout(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(2 * time.Second):
+		}
+	}
+	client := newTestAtlasClient(t, handler)
+
+	targets := []Target{{Kind: TargetProcess, ProcessID: "a:1"}, {Kind: TargetProcess, ProcessID: "b:1"}}
+	c := Collect(context.Background(), client.MonitoringAndLogsApi, "proj1", targets, CollectOptions{
+		ProcessMeasurements: []string{"CONNECTIONS"}, Granularity: "PT1M", Period: "PT1H",
+		RequestTimeout: 20 * time.Millisecond,
+	})
+
+	assert.Empty(t, c.Results)
+	require.Len(t, c.Errors, 2)
+	for _, e := range c.Errors {
+		assert.ErrorIs(t, e.Err, context.DeadlineExceeded)
+	}
+}
+
+func TestCollect_CancelledContext(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	targets := []Target{{Kind: TargetProcess, ProcessID: "a:1"}, {Kind: TargetDisk, ProcessID: "a:1", Partition: "data"}}
+	c := Collect(ctx, nil, "proj1", targets, CollectOptions{})
+
+	assert.Empty(t, c.Results)
+	require.Len(t, c.Errors, 2)
+	for _, e := range c.Errors {
+		assert.ErrorIs(t, e.Err, context.Canceled)
+	}
+}
@@ -0,0 +1,42 @@
+package metrics
+
+import (
+	"context"
+
+	"go.mongodb.org/atlas-sdk/v20250219001/admin"
+
+	"atlas-sdk-go/internal/errors"
+)
+
+// FetchDatabaseMetrics returns measurements for a specified database on a host process in a MongoDB Atlas project.
+// Requires the group ID, process ID, database name, granularity, and either a period or a start and end.
+// Measurement types are optional; all are returned if M is nil.
+func FetchDatabaseMetrics(ctx context.Context, sdk admin.MonitoringAndLogsApi, p *admin.GetDatabaseMeasurementsApiParams) (*admin.ApiMeasurementsGeneralViewAtlas, error) {
+	const op = "fetch database metrics"
+	if err := requireParams(op, param{"group ID", p.GroupId}, param{"process ID", p.ProcessId},
+		param{"database name", p.DatabaseName}, param{"granularity", deref(p.Granularity)}); err != nil {
+		return nil, err
+	}
+	if err := requireWindow(op, p.Period, p.Start, p.End); err != nil {
+		return nil, err
+	}
+
+	req := sdk.GetDatabaseMeasurements(ctx, p.GroupId, p.DatabaseName, p.ProcessId).Granularity(*p.Granularity)
+	if p.M != nil {
+		req = req.M(*p.M)
+	}
+	if p.Period != nil && *p.Period != "" {
+		req = req.Period(*p.Period)
+	} else {
+		req = req.Start(*p.Start).End(*p.End)
+	}
+
+	r, _, err := req.Execute()
+	if err != nil {
+		return nil, errors.FormatError(op, p.ProcessId+"/"+p.DatabaseName, err)
+	}
+	if r == nil || !r.HasMeasurements() || len(r.GetMeasurements()) == 0 {
+		return nil, &errors.NotFoundError{Resource: "database metrics", ID: p.ProcessId + "/" + p.DatabaseName}
+	}
+	return r, nil
+}
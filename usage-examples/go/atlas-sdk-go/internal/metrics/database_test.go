@@ -0,0 +1,127 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/atlas-sdk/v20250219001/admin"
+	"go.mongodb.org/atlas-sdk/v20250219001/mockadmin"
+
+	internalerrors "atlas-sdk-go/internal/errors"
+)
+
+func TestFetchDatabaseMetrics_Unit(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	cases := []struct {
+		name      string
+		view      *admin.ApiMeasurementsGeneralViewAtlas
+		wantErr   bool
+		wantCount int
+	}{
+		{
+			name:      "Happy path returns data",
+			view:      measurementsView(t, "DATABASE_DATA_SIZE"),
+			wantCount: 1,
+		},
+		{
+			name:    "No available data returns error",
+			view:    &admin.ApiMeasurementsGeneralViewAtlas{},
+			wantErr: true,
+		},
+	}
+
+	var baseDatabase = admin.GetDatabaseMeasurementsApiParams{
+		GroupId:      "gID",
+		ProcessId:    "pID",
+		DatabaseName: "app",
+		Granularity:  admin.PtrString("PT1H"),
+		Period:       admin.PtrString("P1D"),
+		M:            &[]string{"DATABASE_DATA_SIZE"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			mockSvc := mockadmin.NewMonitoringAndLogsApi(t)
+			mockSvc.EXPECT().
+				GetDatabaseMeasurements(mock.Anything, baseDatabase.GroupId, baseDatabase.DatabaseName, baseDatabase.ProcessId).
+				Return(admin.GetDatabaseMeasurementsApiRequest{ApiService: mockSvc}).Once()
+			mockSvc.EXPECT().
+				GetDatabaseMeasurementsExecute(mock.Anything).
+				Return(tc.view, nil, nil).Once()
+
+			result, err := FetchDatabaseMetrics(ctx, mockSvc, &baseDatabase)
+
+			if tc.wantErr {
+				var nf *internalerrors.NotFoundError
+				require.ErrorAs(t, err, &nf)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, result.GetMeasurements(), tc.wantCount)
+		})
+	}
+}
+
+func TestFetchMetrics_Validation(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	// The mocks have no expectations, so any request fails the test
+	monitoring := mockadmin.NewMonitoringAndLogsApi(t)
+	collections := mockadmin.NewCollectionLevelMetricsApi(t)
+
+	cases := map[string]func() error{
+		"database without name": func() error {
+			_, err := FetchDatabaseMetrics(ctx, monitoring, &admin.GetDatabaseMeasurementsApiParams{
+				GroupId: "gID", ProcessId: "pID", Granularity: admin.PtrString("PT1H"), Period: admin.PtrString("P1D"),
+			})
+			return err
+		},
+		"database without granularity": func() error {
+			_, err := FetchDatabaseMetrics(ctx, monitoring, &admin.GetDatabaseMeasurementsApiParams{
+				GroupId: "gID", ProcessId: "pID", DatabaseName: "app", Period: admin.PtrString("P1D"),
+			})
+			return err
+		},
+		"process without window": func() error {
+			_, err := FetchProcessMetrics(ctx, monitoring, &admin.GetHostMeasurementsApiParams{
+				GroupId: "gID", ProcessId: "pID", Granularity: admin.PtrString("PT1H"),
+			})
+			return err
+		},
+		"disk without partition": func() error {
+			_, err := FetchDiskMetrics(ctx, monitoring, &admin.GetDiskMeasurementsApiParams{
+				GroupId: "gID", ProcessId: "pID", Granularity: admin.PtrString("PT1H"), Period: admin.PtrString("P1D"),
+			})
+			return err
+		},
+		"namespace without collection": func() error {
+			_, err := FetchNamespaceMetrics(ctx, collections, &admin.GetCollStatsLatencyNamespaceHostMeasurementsApiParams{
+				GroupId: "gID", ProcessId: "pID", DatabaseName: "app", Period: admin.PtrString("P1D"),
+			})
+			return err
+		},
+		"cluster namespace without view": func() error {
+			_, err := FetchClusterNamespaceMetrics(ctx, collections, &admin.GetCollStatsLatencyNamespaceClusterMeasurementsApiParams{
+				GroupId: "gID", ClusterName: "c", DatabaseName: "app", CollectionName: "orders", Period: admin.PtrString("P1D"),
+			})
+			return err
+		},
+		"ranked namespaces without end": func() error {
+			_, err := ListRankedNamespaces(ctx, collections, &admin.GetCollStatsLatencyNamespacesForHostApiParams{
+				GroupId: "gID", ProcessId: "pID", Start: admin.PtrTime(parseTS(t, fixedTS)),
+			})
+			return err
+		},
+	}
+	for name, call := range cases {
+		err := call()
+		var ve *internalerrors.ValidationError
+		assert.ErrorAs(t, err, &ve, name)
+	}
+}
@@ -9,14 +9,31 @@ import (
 )
 
 // FetchDiskMetrics returns measurements for a specified disk partition in a MongoDB Atlas project.
-// Requires the group ID, process ID, partition name, and parameters for measurement type, granularity, and period.
+// Requires the group ID, process ID, partition name, granularity, and either a period or a start and end.
+// Measurement types are optional; all are returned if M is nil.
 func FetchDiskMetrics(ctx context.Context, sdk admin.MonitoringAndLogsApi, p *admin.GetDiskMeasurementsApiParams) (*admin.ApiMeasurementsGeneralViewAtlas, error) {
-	req := sdk.GetDiskMeasurements(ctx, p.GroupId, p.PartitionName, p.ProcessId)
-	req = req.Granularity(*p.Granularity).Period(*p.Period).M(*p.M)
+	const op = "fetch disk metrics"
+	if err := requireParams(op, param{"group ID", p.GroupId}, param{"process ID", p.ProcessId},
+		param{"partition name", p.PartitionName}, param{"granularity", deref(p.Granularity)}); err != nil {
+		return nil, err
+	}
+	if err := requireWindow(op, p.Period, p.Start, p.End); err != nil {
+		return nil, err
+	}
+
+	req := sdk.GetDiskMeasurements(ctx, p.GroupId, p.PartitionName, p.ProcessId).Granularity(*p.Granularity)
+	if p.M != nil {
+		req = req.M(*p.M)
+	}
+	if p.Period != nil && *p.Period != "" {
+		req = req.Period(*p.Period)
+	} else {
+		req = req.Start(*p.Start).End(*p.End)
+	}
 
 	r, _, err := req.Execute()
 	if err != nil {
-		return nil, errors.FormatError("fetch disk metrics", p.ProcessId+"/"+p.PartitionName, err)
+		return nil, errors.FormatError(op, p.ProcessId+"/"+p.PartitionName, err)
 	}
 
 	if r == nil || !r.HasMeasurements() || len(r.GetMeasurements()) == 0 {
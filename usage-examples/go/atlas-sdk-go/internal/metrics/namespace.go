@@ -0,0 +1,266 @@
+package metrics
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"sync"
+
+	"go.mongodb.org/atlas-sdk/v20250219001/admin"
+
+	"atlas-sdk-go/internal/errors"
+)
+
+// Namespace (collection-level) measurement names. GetCollStatsLatencyNamespaceMetrics lists the
+// names available in a project.
+const (
+	NamespaceTotalOps     = "TOTAL_OPS"
+	NamespaceReadOps      = "READS_OPS"
+	NamespaceWriteOps     = "WRITES_OPS"
+	NamespaceReadLatency  = "READS_LATENCY"
+	NamespaceWriteLatency = "WRITES_LATENCY"
+)
+
+// DatabaseDataSize is the database measurement RankDatabases reads for each database's size.
+const DatabaseDataSize = "DATABASE_DATA_SIZE"
+
+// defaultRankGranularity is the data size granularity RankDatabases uses when opts.Granularity is empty.
+const defaultRankGranularity = "PT1H"
+
+// FetchNamespaceMetrics returns latency and operation measurements for one collection on a host process.
+// Requires the group ID, process ID, database and collection names, and either a period or a start and end.
+// Metrics are optional; all collection-level metrics are returned if Metrics is nil.
+func FetchNamespaceMetrics(ctx context.Context, sdk admin.CollectionLevelMetricsApi, p *admin.GetCollStatsLatencyNamespaceHostMeasurementsApiParams) (*admin.MeasurementsCollStatsLatencyHost, error) {
+	const op = "fetch namespace metrics"
+	if err := requireParams(op, param{"group ID", p.GroupId}, param{"process ID", p.ProcessId},
+		param{"database name", p.DatabaseName}, param{"collection name", p.CollectionName}); err != nil {
+		return nil, err
+	}
+	if err := requireWindow(op, p.Period, p.Start, p.End); err != nil {
+		return nil, err
+	}
+
+	req := sdk.GetCollStatsLatencyNamespaceHostMeasurements(ctx, p.GroupId, p.ProcessId, p.DatabaseName, p.CollectionName)
+	if p.Metrics != nil {
+		req = req.Metrics(*p.Metrics)
+	}
+	if p.Period != nil && *p.Period != "" {
+		req = req.Period(*p.Period)
+	} else {
+		req = req.Start(*p.Start).End(*p.End)
+	}
+
+	id := p.ProcessId + "/" + p.DatabaseName + "." + p.CollectionName
+	r, _, err := req.Execute()
+	if err != nil {
+		return nil, errors.FormatError(op, id, err)
+	}
+	if r == nil || len(r.GetMeasurements()) == 0 {
+		return nil, &errors.NotFoundError{Resource: "namespace metrics", ID: id}
+	}
+	return r, nil
+}
+
+// FetchClusterNamespaceMetrics returns latency and operation measurements for one collection across a
+// cluster view (PRIMARY, SECONDARY, or INDIVIDUAL_PROCESS). Requires the group ID, cluster name and view,
+// database and collection names, and either a period or a start and end.
+func FetchClusterNamespaceMetrics(ctx context.Context, sdk admin.CollectionLevelMetricsApi, p *admin.GetCollStatsLatencyNamespaceClusterMeasurementsApiParams) (*admin.MeasurementsCollStatsLatencyCluster, error) {
+	const op = "fetch cluster namespace metrics"
+	if err := requireParams(op, param{"group ID", p.GroupId}, param{"cluster name", p.ClusterName}, param{"cluster view", p.ClusterView},
+		param{"database name", p.DatabaseName}, param{"collection name", p.CollectionName}); err != nil {
+		return nil, err
+	}
+	if err := requireWindow(op, p.Period, p.Start, p.End); err != nil {
+		return nil, err
+	}
+
+	req := sdk.GetCollStatsLatencyNamespaceClusterMeasurements(ctx, p.GroupId, p.ClusterName, p.ClusterView, p.DatabaseName, p.CollectionName)
+	if p.Metrics != nil {
+		req = req.Metrics(*p.Metrics)
+	}
+	if p.Period != nil && *p.Period != "" {
+		req = req.Period(*p.Period)
+	} else {
+		req = req.Start(*p.Start).End(*p.End)
+	}
+
+	id := p.ClusterName + "/" + p.DatabaseName + "." + p.CollectionName
+	r, _, err := req.Execute()
+	if err != nil {
+		return nil, errors.FormatError(op, id, err)
+	}
+	if r == nil || len(r.GetMeasurements()) == 0 {
+		return nil, &errors.NotFoundError{Resource: "namespace metrics", ID: id}
+	}
+	return r, nil
+}
+
+// ListRankedNamespaces returns the busiest namespaces ("database.collection") on a host process,
+// busiest first. Requires the group ID, process ID, and either a period or a start and end.
+func ListRankedNamespaces(ctx context.Context, sdk admin.CollectionLevelMetricsApi, p *admin.GetCollStatsLatencyNamespacesForHostApiParams) ([]string, error) {
+	const op = "list ranked namespaces"
+	if err := requireParams(op, param{"group ID", p.GroupId}, param{"process ID", p.ProcessId}); err != nil {
+		return nil, err
+	}
+	if err := requireWindow(op, p.Period, p.Start, p.End); err != nil {
+		return nil, err
+	}
+
+	req := sdk.GetCollStatsLatencyNamespacesForHost(ctx, p.GroupId, p.ProcessId)
+	if p.Period != nil && *p.Period != "" {
+		req = req.Period(*p.Period)
+	} else {
+		req = req.Start(*p.Start).End(*p.End)
+	}
+
+	r, _, err := req.Execute()
+	if err != nil {
+		return nil, errors.FormatError(op, p.ProcessId, err)
+	}
+	return r.GetRankedNamespaces(), nil
+}
+
+// SeriesFromNamespace converts collection-level measurements into series, keyed by measurement name.
+func SeriesFromNamespace(measurements []admin.MetricsMeasurement) map[string]Series {
+	out := make(map[string]Series, len(measurements))
+	for _, m := range measurements {
+		am := admin.MetricsMeasurementAtlas{Name: m.Name, Units: m.Units, DataPoints: &[]admin.MetricDataPointAtlas{}}
+		for _, dp := range m.GetDataPoints() {
+			*am.DataPoints = append(*am.DataPoints, admin.MetricDataPointAtlas{Timestamp: dp.Timestamp, Value: dp.Value})
+		}
+		out[m.GetName()] = NewSeries(am)
+	}
+	return out
+}
+
+// DatabaseLoad summarises the collection-level load on one database.
+type DatabaseLoad struct {
+	Database   string `json:"database"`
+	Namespaces int    `json:"namespaces"` // ranked collections seen on any process
+	// Mean operations per second over the period, summed over collections and processes.
+	Ops      float64 `json:"ops"`
+	ReadOps  float64 `json:"readOps"`
+	WriteOps float64 `json:"writeOps"`
+	// MaxLatency is the highest mean read or write latency of any collection, in seconds.
+	MaxLatency float64 `json:"maxLatencySeconds"`
+	// DataSizeBytes is the latest DATABASE_DATA_SIZE, or 0 if it could not be fetched.
+	DataSizeBytes float64 `json:"dataSizeBytes"`
+}
+
+// RankDatabases ranks the databases on a set of host processes by collection-level operations, busiest
+// first. It lists each process's ranked namespaces, fetches their measurements, and adds each database's
+// data size. Only opts.Period, Granularity (for data size, default PT1H), Workers, and RequestTimeout
+// are used. Failures for one process, namespace, or database are returned as TargetErrors.
+func RankDatabases(ctx context.Context, client *admin.APIClient, projectID string, processIDs []string, opts CollectOptions) ([]DatabaseLoad, []TargetError) {
+	if opts.Granularity == "" {
+		opts.Granularity = defaultRankGranularity
+	}
+	var (
+		mu   sync.Mutex
+		errs []TargetError
+	)
+	fail := func(t Target, err error) {
+		mu.Lock()
+		errs = append(errs, TargetError{Target: t, Err: err})
+		mu.Unlock()
+	}
+
+	// List the ranked namespaces on each process
+	var targets []Target
+	order := make(map[string]int, len(processIDs))
+	for i, id := range processIDs {
+		order[id] = i
+	}
+	forEach(ctx, len(processIDs), opts.Workers, func(ctx context.Context, i int) {
+		ctx, cancel := context.WithTimeout(ctx, requestTimeout(opts))
+		defer cancel()
+		t := Target{Kind: TargetProcess, ProcessID: processIDs[i]}
+		names, err := ListRankedNamespaces(ctx, client.CollectionLevelMetricsApi, &admin.GetCollStatsLatencyNamespacesForHostApiParams{
+			GroupId: projectID, ProcessId: t.ProcessID, Period: &opts.Period,
+		})
+		if err != nil {
+			fail(t, err)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		for _, ns := range names {
+			db, coll, _ := strings.Cut(ns, ".")
+			targets = append(targets, Target{Kind: TargetNamespace, ProcessID: t.ProcessID, Database: db, Collection: coll})
+		}
+	})
+
+	// Fetch each namespace and add it to its database
+	loads := make(map[string]*DatabaseLoad)
+	hosts := make(map[string]int) // database -> index of the first listed process that serves it
+	seen := make(map[string]bool) // database.collection
+	metricNames := []string{NamespaceTotalOps, NamespaceReadOps, NamespaceWriteOps, NamespaceReadLatency, NamespaceWriteLatency}
+	forEach(ctx, len(targets), opts.Workers, func(ctx context.Context, i int) {
+		ctx, cancel := context.WithTimeout(ctx, requestTimeout(opts))
+		defer cancel()
+		t := targets[i]
+		r, err := FetchNamespaceMetrics(ctx, client.CollectionLevelMetricsApi, &admin.GetCollStatsLatencyNamespaceHostMeasurementsApiParams{
+			GroupId: projectID, ProcessId: t.ProcessID, DatabaseName: t.Database, CollectionName: t.Collection,
+			Metrics: &metricNames, Period: &opts.Period,
+		})
+		if err != nil {
+			fail(t, err)
+			return
+		}
+		series := SeriesFromNamespace(r.GetMeasurements())
+		mean := func(name string) float64 {
+			v, _ := series[name].Mean()
+			return v
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		l, ok := loads[t.Database]
+		if !ok {
+			l = &DatabaseLoad{Database: t.Database}
+			loads[t.Database] = l
+			hosts[t.Database] = order[t.ProcessID]
+		} else {
+			hosts[t.Database] = min(hosts[t.Database], order[t.ProcessID])
+		}
+		if ns := t.Database + "." + t.Collection; !seen[ns] {
+			seen[ns] = true
+			l.Namespaces++
+		}
+		l.Ops += mean(NamespaceTotalOps)
+		l.ReadOps += mean(NamespaceReadOps)
+		l.WriteOps += mean(NamespaceWriteOps)
+		l.MaxLatency = max(l.MaxLatency, mean(NamespaceReadLatency), mean(NamespaceWriteLatency))
+	})
+
+	out := make([]DatabaseLoad, 0, len(loads))
+	for _, l := range loads {
+		out = append(out, *l)
+	}
+	slices.SortFunc(out, func(a, b DatabaseLoad) int {
+		return cmp.Or(cmp.Compare(b.Ops, a.Ops), cmp.Compare(a.Database, b.Database))
+	})
+
+	// Add data sizes from one process serving each database
+	sizeNames := []string{DatabaseDataSize}
+	forEach(ctx, len(out), opts.Workers, func(ctx context.Context, i int) {
+		ctx, cancel := context.WithTimeout(ctx, requestTimeout(opts))
+		defer cancel()
+		t := Target{Kind: TargetDatabase, ProcessID: processIDs[hosts[out[i].Database]], Database: out[i].Database}
+		r, err := FetchDatabaseMetrics(ctx, client.MonitoringAndLogsApi, &admin.GetDatabaseMeasurementsApiParams{
+			GroupId: projectID, ProcessId: t.ProcessID, DatabaseName: t.Database, M: &sizeNames,
+			Granularity: &opts.Granularity, Period: &opts.Period,
+		})
+		if err != nil {
+			fail(t, err)
+			return
+		}
+		if p, ok := SeriesFromView(r)[DatabaseDataSize].Latest(); ok {
+			out[i].DataSizeBytes = p.Value
+		}
+	})
+
+	slices.SortFunc(errs, func(a, b TargetError) int { return cmp.Compare(a.Target.String(), b.Target.String()) })
+	return out, errs
+}
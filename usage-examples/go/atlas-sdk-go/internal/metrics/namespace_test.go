@@ -0,0 +1,93 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/atlas-sdk/v20250219001/admin"
+)
+
+// namespaceView returns a single-point collection-level view with the given values.
+func namespaceView(t *testing.T, values map[string]float32) admin.MeasurementsCollStatsLatencyHost {
+	var ms []admin.MetricsMeasurement
+	for name, v := range values {
+		units := "SCALAR_PER_SECOND"
+		if strings.HasSuffix(name, "_LATENCY") {
+			units = "MICROSECONDS"
+		}
+		ms = append(ms, admin.MetricsMeasurement{
+			Name:  admin.PtrString(name),
+			Units: admin.PtrString(units),
+			DataPoints: &[]admin.MetricDataPoint{{
+				Timestamp: admin.PtrTime(parseTS(t, fixedTS)),
+				Value:     admin.PtrFloat32(v)}}})
+	}
+	return admin.MeasurementsCollStatsLatencyHost{Measurements: &ms}
+}
+
+func TestSeriesFromNamespace(t *testing.T) {
+	t.Parallel()
+	view := namespaceView(t, map[string]float32{NamespaceReadLatency: 2500})
+	s := SeriesFromNamespace(view.GetMeasurements())[NamespaceReadLatency]
+	assert.Equal(t, UnitsSeconds, s.Units)
+	v, ok := s.Mean()
+	require.True(t, ok)
+	assert.InDelta(t, 0.0025, v, 1e-9)
+}
+
+func TestRankDatabases(t *testing.T) {
+	t.Parallel()
+	const prefix = "/api/atlas/v2/groups/proj1/processes"
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		var body any
+		switch p := strings.TrimPrefix(r.URL.Path, prefix); p {
+		case "/m1:27017/collStats/namespaces":
+			assert.Equal(t, "PT1H", r.URL.Query().Get("period"))
+			body = admin.CollStatsRankedNamespaces{RankedNamespaces: &[]string{"shop.orders", "crm.contacts", "shop.carts"}}
+		case "/m2:27017/collStats/namespaces":
+			body = admin.CollStatsRankedNamespaces{RankedNamespaces: &[]string{"shop.orders", "logs.events"}}
+		case "/m1:27017/shop/orders/collStats/measurements":
+			body = namespaceView(t, map[string]float32{NamespaceTotalOps: 100, NamespaceReadOps: 80, NamespaceWriteOps: 20, NamespaceReadLatency: 1000})
+		case "/m2:27017/shop/orders/collStats/measurements":
+			body = namespaceView(t, map[string]float32{NamespaceTotalOps: 40, NamespaceReadOps: 40})
+		case "/m1:27017/shop/carts/collStats/measurements":
+			body = namespaceView(t, map[string]float32{NamespaceTotalOps: 10, NamespaceWriteOps: 10, NamespaceWriteLatency: 5000})
+		case "/m1:27017/crm/contacts/collStats/measurements":
+			body = namespaceView(t, map[string]float32{NamespaceTotalOps: 60, NamespaceReadOps: 60})
+		case "/m1:27017/databases/shop/measurements":
+			assert.Equal(t, DatabaseDataSize, r.URL.Query().Get("m"))
+			body = measurementsView(t, DatabaseDataSize)
+		case "/m1:27017/databases/crm/measurements":
+			body = measurementsView(t, DatabaseDataSize)
+		default: // logs.events measurements fail
+			http.Error(w, `{"error":500}`, http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		assert.NoError(t, json.NewEncoder(w).Encode(body))
+	}
+	client := newTestAtlasClient(t, handler)
+
+	loads, errs := RankDatabases(context.Background(), client, "proj1", []string{"m1:27017", "m2:27017"}, CollectOptions{Period: "PT1H", Workers: 2})
+
+	require.Len(t, loads, 2)
+	shop, crm := loads[0], loads[1]
+	assert.Equal(t, "shop", shop.Database)
+	assert.Equal(t, 2, shop.Namespaces)
+	assert.InDelta(t, 150, shop.Ops, 1e-9)
+	assert.InDelta(t, 120, shop.ReadOps, 1e-9)
+	assert.InDelta(t, 30, shop.WriteOps, 1e-9)
+	assert.InDelta(t, 0.005, shop.MaxLatency, 1e-9)
+	assert.InDelta(t, 1, shop.DataSizeBytes, 1e-9)
+	assert.Equal(t, "crm", crm.Database)
+	assert.InDelta(t, 60, crm.Ops, 1e-9)
+
+	require.Len(t, errs, 1)
+	assert.Equal(t, Target{Kind: TargetNamespace, ProcessID: "m2:27017", Database: "logs", Collection: "events"}, errs[0].Target)
+	assert.Equal(t, "m2:27017/namespace/logs.events", errs[0].Target.String())
+}
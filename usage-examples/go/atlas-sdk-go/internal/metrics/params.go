@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	"fmt"
+	"time"
+
+	"atlas-sdk-go/internal/errors"
+)
+
+// param is a named request parameter for validation.
+type param struct {
+	name  string
+	value string
+}
+
+// requireParams returns a ValidationError naming the first parameter with an empty value.
+func requireParams(op string, params ...param) error {
+	for _, p := range params {
+		if p.value == "" {
+			return &errors.ValidationError{Message: fmt.Sprintf("%s: %s is required", op, p.name)}
+		}
+	}
+	return nil
+}
+
+// requireWindow returns a ValidationError unless a period or both a start and an end are set.
+func requireWindow(op string, period *string, start, end *time.Time) error {
+	if (period == nil || *period == "") && (start == nil || end == nil) {
+		return &errors.ValidationError{Message: fmt.Sprintf("%s: period or start and end are required", op)}
+	}
+	return nil
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -9,14 +9,30 @@ import (
 )
 
 // FetchProcessMetrics returns measurements for a specified host process in a MongoDB Atlas project.
-// Requires the group ID, process ID, and parameters for measurement type, granularity, and period.
+// Requires the group ID, process ID, granularity, and either a period or a start and end.
+// Measurement types are optional; all are returned if M is nil.
 func FetchProcessMetrics(ctx context.Context, sdk admin.MonitoringAndLogsApi, p *admin.GetHostMeasurementsApiParams) (*admin.ApiMeasurementsGeneralViewAtlas, error) {
-	req := sdk.GetHostMeasurements(ctx, p.GroupId, p.ProcessId)
-	req = req.Granularity(*p.Granularity).Period(*p.Period).M(*p.M)
+	const op = "fetch process metrics"
+	if err := requireParams(op, param{"group ID", p.GroupId}, param{"process ID", p.ProcessId}, param{"granularity", deref(p.Granularity)}); err != nil {
+		return nil, err
+	}
+	if err := requireWindow(op, p.Period, p.Start, p.End); err != nil {
+		return nil, err
+	}
+
+	req := sdk.GetHostMeasurements(ctx, p.GroupId, p.ProcessId).Granularity(*p.Granularity)
+	if p.M != nil {
+		req = req.M(*p.M)
+	}
+	if p.Period != nil && *p.Period != "" {
+		req = req.Period(*p.Period)
+	} else {
+		req = req.Start(*p.Start).End(*p.End)
+	}
 
 	r, _, err := req.Execute()
 	if err != nil {
-		return nil, errors.FormatError("fetch process metrics", p.ProcessId, err)
+		return nil, errors.FormatError(op, p.ProcessId, err)
 	}
 	if r == nil || !r.HasMeasurements() || len(r.GetMeasurements()) == 0 {
 		return nil, &errors.NotFoundError{Resource: "process metrics", ID: p.ProcessId}
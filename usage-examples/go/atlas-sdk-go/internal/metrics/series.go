@@ -0,0 +1,184 @@
+package metrics
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	"go.mongodb.org/atlas-sdk/v20250219001/admin"
+)
+
+// Units of a normalised series. Time is converted to seconds, sizes to bytes, and throughput
+// to bytes per second; other units, such as PERCENT and SCALAR_PER_SECOND, are kept as declared.
+const (
+	UnitsSeconds        = "SECONDS"
+	UnitsBytes          = "BYTES"
+	UnitsBytesPerSecond = "BYTES_PER_SECOND"
+	UnitsPercent        = "PERCENT"
+)
+
+// unitScale maps declared Atlas units to normalised units and the factor that converts to them.
+var unitScale = map[string]struct {
+	units  string
+	factor float64
+}{
+	"MICROSECONDS":         {UnitsSeconds, 1.0 / 1e6},
+	"MILLISECONDS":         {UnitsSeconds, 1.0 / 1000},
+	"SECONDS":              {UnitsSeconds, 1},
+	"MINUTES":              {UnitsSeconds, 60},
+	"HOURS":                {UnitsSeconds, 3600},
+	"BYTES":                {UnitsBytes, 1},
+	"KILOBYTES":            {UnitsBytes, 1 << 10},
+	"MEGABYTES":            {UnitsBytes, 1 << 20},
+	"GIGABYTES":            {UnitsBytes, 1 << 30},
+	"BYTES_PER_SECOND":     {UnitsBytesPerSecond, 1},
+	"KILOBYTES_PER_SECOND": {UnitsBytesPerSecond, 1 << 10},
+	"MEGABYTES_PER_SECOND": {UnitsBytesPerSecond, 1 << 20},
+	"GIGABYTES_PER_HOUR":   {UnitsBytesPerSecond, (1 << 30) / 3600.0},
+}
+
+// Point is one sample of a series. A sample Atlas reported without a value is kept as a gap with Valid false.
+type Point struct {
+	Timestamp time.Time `json:"timestamp"`
+	Value     float64   `json:"value"`
+	Valid     bool      `json:"valid"`
+}
+
+// Series is a single measurement over time, in normalised units.
+// Units is empty if the measurement did not declare any.
+type Series struct {
+	Name   string  `json:"name"`
+	Units  string  `json:"units,omitempty"`
+	Points []Point `json:"points"`
+}
+
+// NewSeries converts an Atlas measurement into a series, normalising values using the
+// measurement's declared units. Points are sorted by timestamp.
+func NewSeries(m admin.MetricsMeasurementAtlas) Series {
+	s := Series{Name: m.GetName(), Units: m.GetUnits()}
+	factor := 1.0
+	if u, ok := unitScale[s.Units]; ok {
+		s.Units, factor = u.units, u.factor
+	}
+	for _, dp := range m.GetDataPoints() {
+		p := Point{Timestamp: dp.GetTimestamp()}
+		if dp.Value != nil {
+			p.Value, p.Valid = float64(*dp.Value)*factor, true
+		}
+		s.Points = append(s.Points, p)
+	}
+	slices.SortStableFunc(s.Points, func(a, b Point) int { return a.Timestamp.Compare(b.Timestamp) })
+	return s
+}
+
+// SeriesFromView converts every measurement in a measurements response, keyed by measurement name.
+func SeriesFromView(view *admin.ApiMeasurementsGeneralViewAtlas) map[string]Series {
+	out := make(map[string]Series)
+	for _, m := range view.GetMeasurements() {
+		out[m.GetName()] = NewSeries(m)
+	}
+	return out
+}
+
+// Values returns the values of the valid points, in time order.
+func (s Series) Values() []float64 {
+	var out []float64
+	for _, p := range s.Points {
+		if p.Valid {
+			out = append(out, p.Value)
+		}
+	}
+	return out
+}
+
+// Latest returns the most recent valid point.
+func (s Series) Latest() (Point, bool) {
+	for i := len(s.Points) - 1; i >= 0; i-- {
+		if s.Points[i].Valid {
+			return s.Points[i], true
+		}
+	}
+	return Point{}, false
+}
+
+// Mean returns the average of the valid points. It reports false if there are none.
+func (s Series) Mean() (float64, bool) {
+	vals := s.Values()
+	if len(vals) == 0 {
+		return 0, false
+	}
+	total := 0.0
+	for _, v := range vals {
+		total += v
+	}
+	return total / float64(len(vals)), true
+}
+
+// Min returns the smallest valid value. It reports false if there are none.
+func (s Series) Min() (float64, bool) {
+	vals := s.Values()
+	if len(vals) == 0 {
+		return 0, false
+	}
+	return slices.Min(vals), true
+}
+
+// Max returns the largest valid value. It reports false if there are none.
+func (s Series) Max() (float64, bool) {
+	vals := s.Values()
+	if len(vals) == 0 {
+		return 0, false
+	}
+	return slices.Max(vals), true
+}
+
+// Percentile returns the p-th percentile (0-100) of the valid values, interpolating linearly
+// between the nearest ranks. It reports false if there are no values or p is out of range.
+func (s Series) Percentile(p float64) (float64, bool) {
+	vals := s.Values()
+	if len(vals) == 0 || p < 0 || p > 100 {
+		return 0, false
+	}
+	slices.Sort(vals)
+	rank := p / 100 * float64(len(vals)-1)
+	lo, hi := int(math.Floor(rank)), int(math.Ceil(rank))
+	return vals[lo] + (vals[hi]-vals[lo])*(rank-float64(lo)), true
+}
+
+// RateOfChange returns the change per second between the first and last valid points.
+// It reports false if there are fewer than two valid points or they share a timestamp.
+func (s Series) RateOfChange() (float64, bool) {
+	var first, last *Point
+	for i := range s.Points {
+		if !s.Points[i].Valid {
+			continue
+		}
+		if first == nil {
+			first = &s.Points[i]
+		}
+		last = &s.Points[i]
+	}
+	if first == nil || first == last {
+		return 0, false
+	}
+	secs := last.Timestamp.Sub(first.Timestamp).Seconds()
+	if secs <= 0 {
+		return 0, false
+	}
+	return (last.Value - first.Value) / secs, true
+}
+
+// TimeAbove returns how long the series was above threshold. Each valid point counts until the
+// next point's timestamp; the last point counts for the same step as the one before it. Gaps count as not above.
+func (s Series) TimeAbove(threshold float64) time.Duration {
+	var total, step time.Duration
+	for i, p := range s.Points {
+		if i+1 < len(s.Points) {
+			step = s.Points[i+1].Timestamp.Sub(p.Timestamp)
+		}
+		if p.Valid && p.Value > threshold {
+			total += step
+		}
+	}
+	return total
+}
@@ -0,0 +1,105 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/atlas-sdk/v20250219001/admin"
+)
+
+// measurement builds a one-minute series starting at fixedTS; nil values are gaps.
+func measurement(t *testing.T, name, units string, values ...*float32) admin.MetricsMeasurementAtlas {
+	start := parseTS(t, fixedTS)
+	m := admin.MetricsMeasurementAtlas{Name: admin.PtrString(name), DataPoints: &[]admin.MetricDataPointAtlas{}}
+	if units != "" {
+		m.Units = admin.PtrString(units)
+	}
+	for i, v := range values {
+		*m.DataPoints = append(*m.DataPoints, admin.MetricDataPointAtlas{
+			Timestamp: admin.PtrTime(start.Add(time.Duration(i) * time.Minute)),
+			Value:     v,
+		})
+	}
+	return m
+}
+
+func TestNewSeries_NormalisesUnits(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		units       string
+		value       float32
+		expectUnits string
+		expectValue float64
+	}{
+		{"MILLISECONDS", 1500, UnitsSeconds, 1.5},
+		{"HOURS", 2, UnitsSeconds, 7200},
+		{"MEGABYTES", 3, UnitsBytes, 3 << 20},
+		{"GIGABYTES_PER_HOUR", 36, UnitsBytesPerSecond, 36.0 * (1 << 30) / 3600},
+		{"PERCENT", 42, UnitsPercent, 42},
+		{"SCALAR_PER_SECOND", 7, "SCALAR_PER_SECOND", 7},
+		{"", 0.5, "", 0.5},
+	}
+	for _, c := range cases {
+		s := NewSeries(measurement(t, "M", c.units, admin.PtrFloat32(c.value)))
+		assert.Equal(t, c.expectUnits, s.Units, c.units)
+		assert.InDelta(t, c.expectValue, s.Points[0].Value, 0.01, c.units)
+	}
+}
+
+func TestSeries_Statistics(t *testing.T) {
+	t.Parallel()
+	s := NewSeries(measurement(t, "PROCESS_CPU_USER", "PERCENT",
+		admin.PtrFloat32(10), admin.PtrFloat32(40), nil, admin.PtrFloat32(80), admin.PtrFloat32(30)))
+
+	require.Len(t, s.Points, 5)
+	assert.False(t, s.Points[2].Valid, "null values are kept as gaps")
+	assert.Equal(t, []float64{10, 40, 80, 30}, s.Values())
+
+	mean, ok := s.Mean()
+	require.True(t, ok)
+	assert.InDelta(t, 40, mean, 0.001)
+	lo, _ := s.Min()
+	hi, _ := s.Max()
+	assert.Equal(t, 10.0, lo)
+	assert.Equal(t, 80.0, hi)
+
+	p50, ok := s.Percentile(50)
+	require.True(t, ok)
+	assert.InDelta(t, 35, p50, 0.001)
+	p90, _ := s.Percentile(90)
+	assert.InDelta(t, 68, p90, 0.001)
+	_, ok = s.Percentile(101)
+	assert.False(t, ok)
+
+	rate, ok := s.RateOfChange()
+	require.True(t, ok)
+	assert.InDelta(t, 20.0/240, rate, 0.0001, "first to last valid point over four minutes")
+
+	assert.Equal(t, 2*time.Minute, s.TimeAbove(35), "40 and 80 each last one minute; the gap is not counted")
+	assert.Equal(t, 3*time.Minute, s.TimeAbove(25), "the last point counts for one step")
+
+	last, ok := s.Latest()
+	require.True(t, ok)
+	assert.Equal(t, 30.0, last.Value)
+}
+
+func TestSeries_Empty(t *testing.T) {
+	t.Parallel()
+	s := NewSeries(measurement(t, "CONNECTIONS", "SCALAR", nil, nil))
+
+	_, ok := s.Mean()
+	assert.False(t, ok)
+	_, ok = s.Latest()
+	assert.False(t, ok)
+	_, ok = s.RateOfChange()
+	assert.False(t, ok)
+	assert.Zero(t, s.TimeAbove(0))
+
+	view := &admin.ApiMeasurementsGeneralViewAtlas{Measurements: &[]admin.MetricsMeasurementAtlas{measurement(t, "CONNECTIONS", "SCALAR", admin.PtrFloat32(5))}}
+	series := SeriesFromView(view)
+	assert.Contains(t, series, "CONNECTIONS")
+	_, ok = series["MISSING"].Latest()
+	assert.False(t, ok)
+}
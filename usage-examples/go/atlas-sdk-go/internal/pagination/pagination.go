@@ -0,0 +1,77 @@
+package pagination
+
+import (
+	"context"
+	"iter"
+)
+
+// DefaultItemsPerPage is the page size used when the caller does not request one.
+// Atlas accepts up to 500 items per page for most list endpoints.
+const DefaultItemsPerPage = 100
+
+// PageFetcher retrieves a single page of results.
+// It returns the items on the requested page and the total number of items
+// reported by Atlas (zero if the total count was not included in the response).
+type PageFetcher[T any] func(ctx context.Context, pageNum, itemsPerPage int) ([]T, int, error)
+
+// Iterate returns an iterator that walks every page returned by fetch, starting at startPage.
+// Paging stops when the number of items seen reaches the reported total count, or when a page
+// comes back short or empty if Atlas did not report a total.
+// The context is checked before each request; if it is cancelled, the iterator yields the
+// context error and stops.
+func Iterate[T any](ctx context.Context, fetch PageFetcher[T], startPage, itemsPerPage int) iter.Seq2[T, error] {
+	if startPage < 1 {
+		startPage = 1
+	}
+	if itemsPerPage <= 0 {
+		itemsPerPage = DefaultItemsPerPage
+	}
+
+	return func(yield func(T, error) bool) {
+		var zero T
+		seen := (startPage - 1) * itemsPerPage
+
+		for pageNum := startPage; ; pageNum++ {
+			if err := ctx.Err(); err != nil {
+				yield(zero, err)
+				return
+			}
+
+			items, total, err := fetch(ctx, pageNum, itemsPerPage)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			seen += len(items)
+
+			if len(items) == 0 {
+				return
+			}
+			if total > 0 && seen >= total {
+				return
+			}
+			if total == 0 && len(items) < itemsPerPage {
+				return
+			}
+		}
+	}
+}
+
+// Collect drains seq into a slice. It returns the items gathered so far along with
+// the first error encountered.
+func Collect[T any](seq iter.Seq2[T, error]) ([]T, error) {
+	var out []T
+	for item, err := range seq {
+		if err != nil {
+			return out, err
+		}
+		out = append(out, item)
+	}
+	return out, nil
+}
@@ -0,0 +1,120 @@
+package pagination
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pagedSource serves items in fixed-size pages and records the pages requested
+type pagedSource struct {
+	items        []int
+	reportTotal  bool
+	requested    []int
+	failOnPageNo int
+}
+
+func (s *pagedSource) fetch(_ context.Context, pageNum, itemsPerPage int) ([]int, int, error) {
+	s.requested = append(s.requested, pageNum)
+	if s.failOnPageNo == pageNum {
+		return nil, 0, errors.New("page failed")
+	}
+	start := (pageNum - 1) * itemsPerPage
+	if start >= len(s.items) {
+		return nil, s.total(), nil
+	}
+	end := min(start+itemsPerPage, len(s.items))
+	return s.items[start:end], s.total(), nil
+}
+
+func (s *pagedSource) total() int {
+	if s.reportTotal {
+		return len(s.items)
+	}
+	return 0
+}
+
+func TestIterate_WalksAllPagesUsingTotalCount(t *testing.T) {
+	t.Parallel()
+	src := &pagedSource{items: []int{1, 2, 3, 4, 5}, reportTotal: true}
+
+	got, err := Collect(Iterate(context.Background(), src.fetch, 1, 2))
+
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, got)
+	assert.Equal(t, []int{1, 2, 3}, src.requested, "should stop once totalCount is reached")
+}
+
+func TestIterate_ExactMultipleStopsWithoutExtraRequest(t *testing.T) {
+	t.Parallel()
+	src := &pagedSource{items: []int{1, 2, 3, 4}, reportTotal: true}
+
+	got, err := Collect(Iterate(context.Background(), src.fetch, 1, 2))
+
+	require.NoError(t, err)
+	assert.Len(t, got, 4)
+	assert.Equal(t, []int{1, 2}, src.requested)
+}
+
+func TestIterate_NoTotalCountStopsOnShortPage(t *testing.T) {
+	t.Parallel()
+	src := &pagedSource{items: []int{1, 2, 3}}
+
+	got, err := Collect(Iterate(context.Background(), src.fetch, 1, 2))
+
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, got)
+	assert.Equal(t, []int{1, 2}, src.requested)
+}
+
+func TestIterate_StartPage(t *testing.T) {
+	t.Parallel()
+	src := &pagedSource{items: []int{1, 2, 3, 4, 5}, reportTotal: true}
+
+	got, err := Collect(Iterate(context.Background(), src.fetch, 2, 2))
+
+	require.NoError(t, err)
+	assert.Equal(t, []int{3, 4, 5}, got)
+}
+
+func TestIterate_PropagatesFetchError(t *testing.T) {
+	t.Parallel()
+	src := &pagedSource{items: []int{1, 2, 3, 4, 5}, reportTotal: true, failOnPageNo: 2}
+
+	got, err := Collect(Iterate(context.Background(), src.fetch, 1, 2))
+
+	require.Error(t, err)
+	assert.Equal(t, []int{1, 2}, got, "items from earlier pages should be returned")
+}
+
+func TestIterate_ContextCancelled(t *testing.T) {
+	t.Parallel()
+	src := &pagedSource{items: []int{1, 2, 3}, reportTotal: true}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := Collect(Iterate(ctx, src.fetch, 1, 2))
+
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, src.requested, "no requests should be made after cancellation")
+}
+
+func TestIterate_EarlyBreak(t *testing.T) {
+	t.Parallel()
+	src := &pagedSource{items: []int{1, 2, 3, 4, 5}, reportTotal: true}
+
+	var got []int
+	for v, err := range Iterate(context.Background(), src.fetch, 1, 2) {
+		require.NoError(t, err)
+		got = append(got, v)
+		if v == 2 {
+			break
+		}
+	}
+
+	assert.Equal(t, []int{1, 2}, got)
+	assert.Equal(t, []int{1}, src.requested)
+}
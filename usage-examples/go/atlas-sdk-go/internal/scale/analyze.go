@@ -28,54 +28,14 @@ func GetAverageProcessCPU(ctx context.Context, client *admin.APIClient, projectI
 		return 0, fmt.Errorf("invalid period minutes: %d", periodMinutes)
 	}
 
-	procID, err := clusterutils.GetProcessIdForCluster(ctx, client.MonitoringAndLogsApi, &admin.ListAtlasProcessesApiParams{GroupId: projectID}, clusterName)
+	procID, err := clusterutils.GetProcessIdForCluster(ctx, client, projectID, clusterName)
 	if err != nil {
 		return 0, err
 	}
 	if procID == "" {
-		return 0, fmt.Errorf("no process found for cluster %s", clusterName)
+		return 0, fmt.Errorf("no primary process found for cluster %s", clusterName)
 	}
-
-	granularity := "PT1M"
-	period := fmt.Sprintf("PT%vM", periodMinutes)
-	metricsList := []string{"PROCESS_CPU_USER"}
-	m, err := metrics.FetchProcessMetrics(ctx, client.MonitoringAndLogsApi, &admin.GetHostMeasurementsApiParams{
-		GroupId:     projectID,
-		ProcessId:   procID,
-		Granularity: &granularity,
-		Period:      &period,
-		M:           &metricsList,
-	})
-	if err != nil {
-		return 0, err
-	}
-
-	if m == nil || !m.HasMeasurements() {
-		return 0, fmt.Errorf("no measurements returned")
-	}
-	meas := m.GetMeasurements()
-	if len(meas) == 0 || !meas[0].HasDataPoints() {
-		return 0, fmt.Errorf("no datapoints returned")
-	}
-
-	total := 0.0
-	count := 0.0
-	for _, dp := range meas[0].GetDataPoints() {
-		if dp.HasValue() {
-			v := float64(dp.GetValue())
-			total += v
-			count++
-		}
-	}
-	if count == 0 {
-		return 0, fmt.Errorf("no usable datapoint values")
-	}
-	avg := total / count
-	// Convert fractional to % if needed
-	if avg <= 1.0 {
-		avg *= 100.0
-	}
-	return avg, nil
+	return GetAverageCPUForProcess(ctx, client, projectID, procID, periodMinutes)
 }
 
 // GetAverageCPUForProcess fetches host CPU metrics for a specific process ID and returns an average percentage.
@@ -94,7 +54,7 @@ func GetAverageCPUForProcess(ctx context.Context, client *admin.APIClient, proje
 	}
 	granularity := "PT1M"
 	period := fmt.Sprintf("PT%vM", periodMinutes)
-	metricsList := []string{"PROCESS_CPU_USER"}
+	metricsList := []string{cpuMetric}
 	m, err := metrics.FetchProcessMetrics(ctx, client.MonitoringAndLogsApi, &admin.GetHostMeasurementsApiParams{
 		GroupId:     projectID,
 		ProcessId:   processID,
@@ -105,43 +65,58 @@ func GetAverageCPUForProcess(ctx context.Context, client *admin.APIClient, proje
 	if err != nil {
 		return 0, err
 	}
-	if m == nil || !m.HasMeasurements() {
-		return 0, fmt.Errorf("no measurements returned")
+	series, ok := metrics.SeriesFromView(m)[cpuMetric]
+	if !ok {
+		return 0, fmt.Errorf("no %s measurements returned", cpuMetric)
 	}
-	meas := m.GetMeasurements()
-	if len(meas) == 0 || !meas[0].HasDataPoints() {
-		return 0, fmt.Errorf("no datapoints returned")
-	}
-	total := 0.0
-	count := 0.0
-	for _, dp := range meas[0].GetDataPoints() {
-		if dp.HasValue() {
-			v := float64(dp.GetValue())
-			total += v
-			count++
-		}
-	}
-	if count == 0 {
+	return averageCPUPercent(series)
+}
+
+// cpuMetric is the process measurement used for CPU-based scaling decisions.
+const cpuMetric = "PROCESS_CPU_USER"
+
+// averageCPUPercent returns the mean of a CPU series, which Atlas declares in PERCENT. A series that
+// declares no units, or other units, is rejected rather than guessed at.
+func averageCPUPercent(s metrics.Series) (float64, error) {
+	switch s.Units {
+	case metrics.UnitsPercent:
+	case "":
+		return 0, fmt.Errorf("%s measurements declare no units", cpuMetric)
+	default:
+		return 0, fmt.Errorf("%s measurements in unsupported units %q", cpuMetric, s.Units)
+	}
+	avg, ok := s.Mean()
+	if !ok {
 		return 0, fmt.Errorf("no usable datapoint values")
 	}
-	avg := total / count
-	if avg <= 1.0 { // convert fractional to percent
-		avg *= 100.0
-	}
 	return avg, nil
 }
 
-// GetAverageCPUForProcesses returns a map of processID -> average CPU (percent) ignoring processes with errors.
-func GetAverageCPUForProcesses(ctx context.Context, client *admin.APIClient, projectID string, processIDs []string, periodMinutes int) map[string]float64 {
-	out := make(map[string]float64, len(processIDs))
+// GetAverageCPUForProcesses returns a map of processID -> average CPU (percent), fetching the processes
+// concurrently. Processes whose metrics cannot be fetched or averaged are left out of the map and
+// reported in the returned errors.
+func GetAverageCPUForProcesses(ctx context.Context, client *admin.APIClient, projectID string, processIDs []string, periodMinutes int) (map[string]float64, []metrics.TargetError) {
+	targets := make([]metrics.Target, 0, len(processIDs))
 	for _, pid := range processIDs {
-		avg, err := GetAverageCPUForProcess(ctx, client, projectID, pid, periodMinutes)
+		targets = append(targets, metrics.Target{Kind: metrics.TargetProcess, ProcessID: pid})
+	}
+	c := metrics.Collect(ctx, client.MonitoringAndLogsApi, projectID, targets, metrics.CollectOptions{
+		ProcessMeasurements: []string{cpuMetric},
+		Granularity:         "PT1M",
+		Period:              fmt.Sprintf("PT%vM", periodMinutes),
+	})
+
+	out := make(map[string]float64, len(processIDs))
+	errs := c.Errors
+	for _, r := range c.Results {
+		avg, err := averageCPUPercent(r.Series[cpuMetric])
 		if err != nil {
+			errs = append(errs, metrics.TargetError{Target: r.Target, Err: err})
 			continue
 		}
-		out[pid] = avg
+		out[r.Target.ProcessID] = avg
 	}
-	return out
+	return out, errs
 }
 
 // ExtractInstanceSize retrieves the electable instance size from the first region config.
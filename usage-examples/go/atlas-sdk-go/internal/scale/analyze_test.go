@@ -34,7 +34,7 @@ func TestGetAverageProcessCPU(t *testing.T) {
 		msg           string
 	}{
 		{
-			name:          "fractional_datapoints",
+			name:          "undeclared_units",
 			projectID:     "proj1",
 			clusterName:   "clusterA",
 			periodMinutes: 60,
@@ -42,19 +42,17 @@ func TestGetAverageProcessCPU(t *testing.T) {
 			processList: &admin.PaginatedHostViewAtlas{Results: &[]admin.ApiHostViewAtlas{{
 				Id:        admin.PtrString("procA"),
 				UserAlias: admin.PtrString("clusterA"),
+				TypeName:  admin.PtrString("REPLICA_PRIMARY"),
 			}}},
 			measurements: &admin.ApiMeasurementsGeneralViewAtlas{Measurements: &[]admin.MetricsMeasurementAtlas{{
 				Name: admin.PtrString("PROCESS_CPU_USER"),
 				DataPoints: &[]admin.MetricDataPointAtlas{{
 					Timestamp: admin.PtrTime(parseTS(t, fixedTS)),
-					Value:     admin.PtrFloat32(0.75),
-				}, {
-					Timestamp: admin.PtrTime(parseTS(t, "2023-04-01T12:01:00Z")),
-					Value:     admin.PtrFloat32(0.80),
+					Value:     admin.PtrFloat32(0.8),
 				}},
 			}}},
-			expectedCPU: 77.5,
-			msg:         "average of fractional datapoints converted to percent",
+			expectError: true,
+			msg:         "error when CPU measurements declare no units rather than guessing a scale",
 		},
 		{
 			name:          "percentage_datapoints",
@@ -65,9 +63,11 @@ func TestGetAverageProcessCPU(t *testing.T) {
 			processList: &admin.PaginatedHostViewAtlas{Results: &[]admin.ApiHostViewAtlas{{
 				Id:        admin.PtrString("procA"),
 				UserAlias: admin.PtrString("clusterA"),
+				TypeName:  admin.PtrString("REPLICA_PRIMARY"),
 			}}},
 			measurements: &admin.ApiMeasurementsGeneralViewAtlas{Measurements: &[]admin.MetricsMeasurementAtlas{{
-				Name: admin.PtrString("PROCESS_CPU_USER"),
+				Name:  admin.PtrString("PROCESS_CPU_USER"),
+				Units: admin.PtrString("PERCENT"),
 				DataPoints: &[]admin.MetricDataPointAtlas{{
 					Timestamp: admin.PtrTime(parseTS(t, fixedTS)),
 					Value:     admin.PtrFloat32(75.0),
@@ -79,6 +79,31 @@ func TestGetAverageProcessCPU(t *testing.T) {
 			expectedCPU: 77.5,
 			msg:         "average of already-percentage datapoints",
 		},
+		{
+			name:          "declared_percent_below_one",
+			projectID:     "proj1",
+			clusterName:   "clusterA",
+			periodMinutes: 60,
+			processID:     "procA",
+			processList: &admin.PaginatedHostViewAtlas{Results: &[]admin.ApiHostViewAtlas{{
+				Id:        admin.PtrString("procA"),
+				UserAlias: admin.PtrString("clusterA"),
+				TypeName:  admin.PtrString("REPLICA_PRIMARY"),
+			}}},
+			measurements: &admin.ApiMeasurementsGeneralViewAtlas{Measurements: &[]admin.MetricsMeasurementAtlas{{
+				Name:  admin.PtrString("PROCESS_CPU_USER"),
+				Units: admin.PtrString("PERCENT"),
+				DataPoints: &[]admin.MetricDataPointAtlas{{
+					Timestamp: admin.PtrTime(parseTS(t, fixedTS)),
+					Value:     admin.PtrFloat32(0.4),
+				}, {
+					Timestamp: admin.PtrTime(parseTS(t, "2023-04-01T12:01:00Z")),
+					Value:     admin.PtrFloat32(0.6),
+				}},
+			}}},
+			expectedCPU: 0.5,
+			msg:         "declared percent units are not rescaled",
+		},
 		{
 			name:          "no_process_found",
 			projectID:     "proj1",
@@ -101,6 +126,7 @@ func TestGetAverageProcessCPU(t *testing.T) {
 			processList: &admin.PaginatedHostViewAtlas{Results: &[]admin.ApiHostViewAtlas{{
 				Id:        admin.PtrString("procA"),
 				UserAlias: admin.PtrString("clusterA"),
+				TypeName:  admin.PtrString("REPLICA_PRIMARY"),
 			}}},
 			measurements: &admin.ApiMeasurementsGeneralViewAtlas{},
 			expectError:  true,
@@ -115,6 +141,7 @@ func TestGetAverageProcessCPU(t *testing.T) {
 			processList: &admin.PaginatedHostViewAtlas{Results: &[]admin.ApiHostViewAtlas{{
 				Id:        admin.PtrString("procA"),
 				UserAlias: admin.PtrString("clusterA"),
+				TypeName:  admin.PtrString("REPLICA_PRIMARY"),
 			}}},
 			measurements: &admin.ApiMeasurementsGeneralViewAtlas{Measurements: &[]admin.MetricsMeasurementAtlas{{
 				Name:       admin.PtrString("PROCESS_CPU_USER"),
@@ -130,6 +157,18 @@ func TestGetAverageProcessCPU(t *testing.T) {
 		t.Run(c.name, func(t *testing.T) {
 			mockSvc := mockadmin.NewMonitoringAndLogsApi(t)
 
+			// The primary is found through the cluster's topology
+			clusterSvc := mockadmin.NewClustersApi(t)
+			clusterSvc.EXPECT().
+				GetCluster(mock.Anything, c.projectID, c.clusterName).
+				Return(admin.GetClusterApiRequest{ApiService: clusterSvc}).Once()
+			clusterSvc.EXPECT().
+				GetClusterExecute(mock.Anything).
+				Return(&admin.ClusterDescription20240805{
+					Name:              admin.PtrString(c.clusterName),
+					ConnectionStrings: &admin.ClusterConnectionStrings{Standard: admin.PtrString("mongodb://" + c.clusterName + ":27017")},
+				}, nil, nil).Once()
+
 			// Expect list processes
 			mockSvc.EXPECT().
 				ListAtlasProcesses(mock.Anything, c.projectID).
@@ -157,7 +196,7 @@ func TestGetAverageProcessCPU(t *testing.T) {
 					Return(c.measurements, nil, nil).Once()
 			}
 
-			client := &admin.APIClient{MonitoringAndLogsApi: mockSvc}
+			client := &admin.APIClient{ClustersApi: clusterSvc, MonitoringAndLogsApi: mockSvc}
 			val, err := GetAverageProcessCPU(ctx, client, c.projectID, c.clusterName, c.periodMinutes)
 			if c.expectError {
 				require.Error(t, err, c.msg)
@@ -202,10 +241,11 @@ func BenchmarkGetAverageProcessCPU(b *testing.B) {
 		UserAlias: admin.PtrString("benchCluster"),
 	}}}
 	meas := &admin.ApiMeasurementsGeneralViewAtlas{Measurements: &[]admin.MetricsMeasurementAtlas{{
-		Name: admin.PtrString("PROCESS_CPU_USER"),
+		Name:  admin.PtrString("PROCESS_CPU_USER"),
+		Units: admin.PtrString("PERCENT"),
 		DataPoints: &[]admin.MetricDataPointAtlas{{
 			Timestamp: admin.PtrTime(time.Now()),
-			Value:     admin.PtrFloat32(0.50),
+			Value:     admin.PtrFloat32(50),
 		}},
 	}}}
 	mockSvc.EXPECT().ListAtlasProcesses(mock.Anything, "benchProj").Return(admin.ListAtlasProcessesApiRequest{ApiService: mockSvc}).Maybe()
@@ -0,0 +1,106 @@
+package scale
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"atlas-sdk-go/internal/clusterutils"
+	"atlas-sdk-go/internal/errors"
+
+	"go.mongodb.org/atlas-sdk/v20250219001/admin"
+)
+
+// ApplyOptions controls how ApplyPlan rolls out a plan.
+type ApplyOptions struct {
+	// Confirm, if set, is asked before each cluster is updated, and asked again with the new plan
+	// (Replanned set) if the cluster changed while waiting. Clusters it declines are skipped.
+	Confirm func(ClusterPlan) bool
+	// Wait controls the wait for each cluster to be IDLE before and after its update.
+	// The target state is always IDLE. AwaitTransition only applies after the update and
+	// defaults to clusterutils.DefaultAwaitTransition.
+	Wait clusterutils.WaitOptions
+}
+
+// ApplyResult is the outcome of applying the plan for one cluster.
+type ApplyResult struct {
+	ProjectID string
+	Cluster   string
+	Applied   bool
+	Skipped   bool
+	Err       error
+}
+
+// ApplyPlan updates the planned clusters one at a time with a single UpdateCluster call each.
+// It waits for each cluster to be IDLE before updating it and again afterwards, so changes never
+// overlap. It stops at the first failure and returns the results for the clusters handled so far.
+// Only plans built by BuildPlan or PlanCluster can be applied; a plan read back from JSON has no payloads.
+//
+// Once a cluster is IDLE it is re-read and re-planned, so changes made since the plan was built
+// (such as auto-scaling or another edit) are not reverted. A cluster that now matches its desired
+// state is skipped. If the changes differ from the confirmed plan, the new plan is confirmed again,
+// or, without Confirm, ApplyPlan stops with a ValidationError.
+func ApplyPlan(ctx context.Context, sdk admin.ClustersApi, plan *Plan, opts ApplyOptions) ([]ApplyResult, error) {
+	before := opts.Wait
+	before.TargetState, before.AwaitTransition = clusterutils.StateIdle, 0
+	after := before
+	after.AwaitTransition = cmp.Or(opts.Wait.AwaitTransition, clusterutils.DefaultAwaitTransition)
+	var results []ApplyResult
+	for _, cp := range plan.Clusters {
+		r := ApplyResult{ProjectID: cp.ProjectID, Cluster: cp.Cluster}
+		if cp.payload == nil {
+			r.Err = &errors.ValidationError{Message: fmt.Sprintf("plan for cluster %s has no update payload", cp.Cluster)}
+			return append(results, r), r.Err
+		}
+		if opts.Confirm != nil && !opts.Confirm(cp) {
+			r.Skipped = true
+			results = append(results, r)
+			continue
+		}
+
+		if _, err := clusterutils.WaitForState(ctx, sdk, cp.ProjectID, cp.Cluster, before); err != nil {
+			r.Err = errors.WithContext(err, "waiting before update")
+			return append(results, r), r.Err
+		}
+		cur, _, err := sdk.GetCluster(ctx, cp.ProjectID, cp.Cluster).Execute()
+		if err != nil {
+			r.Err = errors.FormatError("get cluster", cp.Cluster, err)
+			return append(results, r), r.Err
+		}
+		fresh, err := PlanCluster(cp.desired, cur)
+		if err != nil {
+			r.Err = errors.WithContext(err, "re-planning before update")
+			return append(results, r), r.Err
+		}
+		if len(fresh.Changes) == 0 {
+			r.Skipped = true
+			results = append(results, r)
+			continue
+		}
+		if !slices.Equal(fresh.Changes, cp.Changes) {
+			fresh.Replanned = true
+			if opts.Confirm == nil {
+				r.Err = &errors.ValidationError{Message: fmt.Sprintf("cluster %s changed since the plan was built; plan again", cp.Cluster)}
+				return append(results, r), r.Err
+			}
+			if !opts.Confirm(*fresh) {
+				r.Skipped = true
+				results = append(results, r)
+				continue
+			}
+		}
+
+		if _, _, err := sdk.UpdateCluster(ctx, cp.ProjectID, cp.Cluster, fresh.payload).Execute(); err != nil {
+			r.Err = errors.FormatError("update cluster", cp.Cluster, err)
+			return append(results, r), r.Err
+		}
+		r.Applied = true
+		if _, err := clusterutils.WaitForState(ctx, sdk, cp.ProjectID, cp.Cluster, after); err != nil {
+			r.Err = errors.WithContext(err, "waiting after update")
+			return append(results, r), r.Err
+		}
+		results = append(results, r)
+	}
+	return results, nil
+}
@@ -0,0 +1,94 @@
+package scale
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"atlas-sdk-go/internal/errors"
+)
+
+// DesiredState declares the hardware and auto-scaling settings that clusters should have.
+// Settings left out of the file are not managed and keep their current values.
+type DesiredState struct {
+	Clusters []DesiredCluster `json:"clusters"`
+}
+
+// DesiredCluster declares the settings of one cluster's regions.
+type DesiredCluster struct {
+	ProjectID string          `json:"projectId"`
+	Name      string          `json:"name"`
+	Regions   []DesiredRegion `json:"regions"`
+}
+
+// DesiredRegion declares the settings of one region. The region must already exist in the
+// cluster; adding and removing regions is left to the Atlas UI or Terraform.
+type DesiredRegion struct {
+	Provider string `json:"provider"`
+	Region   string `json:"region"`
+	// Shard limits the settings to one replication spec by index. Omit it to apply them to every shard in the region.
+	Shard       *int                `json:"shard,omitempty"`
+	Electable   *DesiredNodes       `json:"electable,omitempty"`
+	ReadOnly    *DesiredNodes       `json:"readOnly,omitempty"`
+	Analytics   *DesiredNodes       `json:"analytics,omitempty"`
+	AutoScaling *DesiredAutoScaling `json:"autoScaling,omitempty"`
+}
+
+// DesiredNodes declares the hardware of one node type. Empty or nil fields are not managed.
+type DesiredNodes struct {
+	InstanceSize string   `json:"instanceSize,omitempty"`
+	NodeCount    *int     `json:"nodeCount,omitempty"`
+	DiskSizeGB   *float64 `json:"diskSizeGB,omitempty"`
+}
+
+// DesiredAutoScaling declares compute and disk auto-scaling bounds. Empty or nil fields are not managed.
+type DesiredAutoScaling struct {
+	ComputeEnabled   *bool  `json:"computeEnabled,omitempty"`
+	ScaleDownEnabled *bool  `json:"scaleDownEnabled,omitempty"`
+	MinInstanceSize  string `json:"minInstanceSize,omitempty"`
+	MaxInstanceSize  string `json:"maxInstanceSize,omitempty"`
+	DiskEnabled      *bool  `json:"diskEnabled,omitempty"`
+}
+
+// LoadDesiredState reads and validates a desired state file.
+func LoadDesiredState(filePath string) (*DesiredState, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, &errors.NotFoundError{Resource: "desired state file", ID: filePath}
+		}
+		return nil, errors.WithContext(err, "reading desired state file")
+	}
+	var d DesiredState
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, errors.WithContext(err, "parsing desired state file")
+	}
+	if err := d.Validate(); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// Validate checks that every cluster and region is identified and that no cluster is declared twice.
+func (d *DesiredState) Validate() error {
+	seen := make(map[string]bool, len(d.Clusters))
+	for i, c := range d.Clusters {
+		if c.ProjectID == "" || c.Name == "" {
+			return &errors.ValidationError{Message: fmt.Sprintf("desired cluster %d: projectId and name are required", i)}
+		}
+		key := c.ProjectID + "/" + c.Name
+		if seen[key] {
+			return &errors.ValidationError{Message: fmt.Sprintf("cluster %s is declared more than once", key)}
+		}
+		seen[key] = true
+		for j, r := range c.Regions {
+			if r.Provider == "" || r.Region == "" {
+				return &errors.ValidationError{Message: fmt.Sprintf("cluster %s region %d: provider and region are required", key, j)}
+			}
+			if r.Shard != nil && *r.Shard < 0 {
+				return &errors.ValidationError{Message: fmt.Sprintf("cluster %s region %s/%s: shard must not be negative", key, r.Provider, r.Region)}
+			}
+		}
+	}
+	return nil
+}
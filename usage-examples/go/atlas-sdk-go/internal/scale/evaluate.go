@@ -77,7 +77,10 @@ func EvaluateDecisionAggregated(ctx context.Context, client *admin.APIClient, pr
 	if len(processIDs) == 0 {
 		return EvaluateDecision(ctx, client, projectID, clusterName, sc)
 	}
-	cpus := GetAverageCPUForProcesses(ctx, client, projectID, processIDs, sc.PeriodMinutes)
+	cpus, errs := GetAverageCPUForProcesses(ctx, client, projectID, processIDs, sc.PeriodMinutes)
+	for _, e := range errs {
+		fmt.Printf("  Warning: CPU metrics unavailable for %s: %v\n", e.Target, e.Err)
+	}
 	if len(cpus) == 0 {
 		fmt.Printf("  Warning: no usable metrics across %d processes for cluster %s\n", len(processIDs), clusterName)
 		return false, "metrics unavailable for reactive scaling decision"
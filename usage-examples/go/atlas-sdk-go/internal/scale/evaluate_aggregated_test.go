@@ -2,17 +2,20 @@ package scale
 
 import (
 	"context"
-	"fmt"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path"
 	"testing"
 	"time"
 
-	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.mongodb.org/atlas-sdk/v20250219001/admin"
 	"go.mongodb.org/atlas-sdk/v20250219001/mockadmin"
 )
 
-// helper to build measurements with provided float32 values (interpreting <=1 as fractional)
+// helper to build CPU measurements in PERCENT with provided float32 values
 func buildMeasurements(vals ...float32) *admin.ApiMeasurementsGeneralViewAtlas {
 	pts := make([]admin.MetricDataPointAtlas, 0, len(vals))
 	for i, v := range vals {
@@ -23,10 +26,31 @@ func buildMeasurements(vals ...float32) *admin.ApiMeasurementsGeneralViewAtlas {
 	}
 	return &admin.ApiMeasurementsGeneralViewAtlas{Measurements: &[]admin.MetricsMeasurementAtlas{{
 		Name:       admin.PtrString("PROCESS_CPU_USER"),
+		Units:      admin.PtrString("PERCENT"),
 		DataPoints: &pts,
 	}}}
 }
 
+// newMeasurementsClient serves host measurements keyed by process ID. Processes without
+// an entry return 404. Processes are fetched concurrently, so responses can't depend on call order.
+func newMeasurementsClient(t *testing.T, views map[string]*admin.ApiMeasurementsGeneralViewAtlas) *admin.APIClient {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		processID := path.Base(path.Dir(r.URL.Path)) // .../processes/{processId}/measurements
+		view, ok := views[processID]
+		if !ok {
+			http.Error(w, `{"error":404,"errorCode":"RESOURCE_NOT_FOUND"}`, http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		assert.NoError(t, json.NewEncoder(w).Encode(view))
+	}))
+	t.Cleanup(server.Close)
+	client, err := admin.NewClient(admin.UseBaseURL(server.URL))
+	require.NoError(t, err)
+	return client
+}
+
 func TestEvaluateDecisionAggregated_PrimaryTriggers(t *testing.T) {
 	ctx := context.Background()
 	projectID := "proj1"
@@ -34,16 +58,12 @@ func TestEvaluateDecisionAggregated_PrimaryTriggers(t *testing.T) {
 	primaryID := "primary:27017"
 	secondaryID := "secondary:27017"
 
-	mockSvc := mockadmin.NewMonitoringAndLogsApi(t)
-
-	// Primary metrics average 80% (0.80 fractional) > threshold 75
-	mockSvc.EXPECT().GetHostMeasurements(mock.Anything, projectID, primaryID).Return(admin.GetHostMeasurementsApiRequest{ApiService: mockSvc}).Once()
-	mockSvc.EXPECT().GetHostMeasurementsExecute(mock.Anything).Return(buildMeasurements(0.80, 0.80), nil, nil).Once()
-	// Secondary metrics average 50%
-	mockSvc.EXPECT().GetHostMeasurements(mock.Anything, projectID, secondaryID).Return(admin.GetHostMeasurementsApiRequest{ApiService: mockSvc}).Once()
-	mockSvc.EXPECT().GetHostMeasurementsExecute(mock.Anything).Return(buildMeasurements(0.50, 0.50), nil, nil).Once()
-
-	client := &admin.APIClient{MonitoringAndLogsApi: mockSvc}
+	client := newMeasurementsClient(t, map[string]*admin.ApiMeasurementsGeneralViewAtlas{
+		// Primary metrics average 80% (0.80 fractional) > threshold 75
+		primaryID: buildMeasurements(80, 80),
+		// Secondary metrics average 50%
+		secondaryID: buildMeasurements(50, 50),
+	})
 	sc := ScalingConfig{TargetTier: "M50", CPUThreshold: 75, PeriodMinutes: 60}
 
 	shouldScale, reason := EvaluateDecisionAggregated(ctx, client, projectID, clusterName, []string{primaryID, secondaryID}, primaryID, sc)
@@ -59,17 +79,13 @@ func TestEvaluateDecisionAggregated_AggregatedTriggers(t *testing.T) {
 	sec1 := "sec1:27017"
 	sec2 := "sec2:27017"
 
-	mockSvc := mockadmin.NewMonitoringAndLogsApi(t)
-	// Primary 70% (below threshold)
-	mockSvc.EXPECT().GetHostMeasurements(mock.Anything, projectID, primaryID).Return(admin.GetHostMeasurementsApiRequest{ApiService: mockSvc}).Once()
-	mockSvc.EXPECT().GetHostMeasurementsExecute(mock.Anything).Return(buildMeasurements(0.70, 0.70), nil, nil).Once()
-	// Secondaries high (85%) raising aggregate above threshold 75
-	mockSvc.EXPECT().GetHostMeasurements(mock.Anything, projectID, sec1).Return(admin.GetHostMeasurementsApiRequest{ApiService: mockSvc}).Once()
-	mockSvc.EXPECT().GetHostMeasurementsExecute(mock.Anything).Return(buildMeasurements(0.85, 0.85), nil, nil).Once()
-	mockSvc.EXPECT().GetHostMeasurements(mock.Anything, projectID, sec2).Return(admin.GetHostMeasurementsApiRequest{ApiService: mockSvc}).Once()
-	mockSvc.EXPECT().GetHostMeasurementsExecute(mock.Anything).Return(buildMeasurements(0.85, 0.85), nil, nil).Once()
-
-	client := &admin.APIClient{MonitoringAndLogsApi: mockSvc}
+	client := newMeasurementsClient(t, map[string]*admin.ApiMeasurementsGeneralViewAtlas{
+		// Primary 70% (below threshold)
+		primaryID: buildMeasurements(70, 70),
+		// Secondaries high (85%) raising aggregate above threshold 75
+		sec1: buildMeasurements(85, 85),
+		sec2: buildMeasurements(85, 85),
+	})
 	sc := ScalingConfig{TargetTier: "M50", CPUThreshold: 75, PeriodMinutes: 60}
 
 	shouldScale, reason := EvaluateDecisionAggregated(ctx, client, projectID, clusterName, []string{primaryID, sec1, sec2}, primaryID, sc)
@@ -84,14 +100,8 @@ func TestEvaluateDecisionAggregated_NoMetrics(t *testing.T) {
 	primaryID := "primary:27017"
 	sec := "sec:27017"
 
-	mockSvc := mockadmin.NewMonitoringAndLogsApi(t)
-	// Return errors (simulate metrics not available) by returning nil measurements with not found error detail
-	mockSvc.EXPECT().GetHostMeasurements(mock.Anything, projectID, primaryID).Return(admin.GetHostMeasurementsApiRequest{ApiService: mockSvc}).Once()
-	mockSvc.EXPECT().GetHostMeasurementsExecute(mock.Anything).Return(nil, nil, fmt.Errorf("not found"))
-	mockSvc.EXPECT().GetHostMeasurements(mock.Anything, projectID, sec).Return(admin.GetHostMeasurementsApiRequest{ApiService: mockSvc}).Once()
-	mockSvc.EXPECT().GetHostMeasurementsExecute(mock.Anything).Return(nil, nil, fmt.Errorf("not found"))
-
-	client := &admin.APIClient{MonitoringAndLogsApi: mockSvc}
+	// No entries, so every process returns not found (simulate metrics not available)
+	client := newMeasurementsClient(t, nil)
 	sc := ScalingConfig{TargetTier: "M50", CPUThreshold: 75, PeriodMinutes: 60}
 
 	shouldScale, reason := EvaluateDecisionAggregated(ctx, client, projectID, clusterName, []string{primaryID, sec}, primaryID, sc)
@@ -36,33 +36,68 @@ func ExecuteClusterScaling(ctx context.Context, client *admin.APIClient, project
 
 // buildScalePayload copies current replication specs and updates instance sizes to targetTier.
 func buildScalePayload(cur *admin.ClusterDescription20240805, targetTier string) *admin.ClusterDescription20240805 {
-	payload := admin.NewClusterDescription20240805()
+	return buildPayload(cur, func(_ int, rc *admin.CloudRegionConfig20240805) {
+		if rc.HasElectableSpecs() {
+			rc.ElectableSpecs.SetInstanceSize(targetTier)
+		}
+		if rc.HasReadOnlySpecs() {
+			rc.ReadOnlySpecs.SetInstanceSize(targetTier)
+		}
+		if rc.HasAnalyticsSpecs() {
+			rc.AnalyticsSpecs.SetInstanceSize(targetTier)
+		}
+	})
+}
+
+// buildPayload returns an update payload holding a copy of the current replication specs, after
+// edit has changed each region config in place. shard is the index of the region's replication spec.
+// The current cluster is not modified. Returns nil if the cluster has no replication specs.
+func buildPayload(cur *admin.ClusterDescription20240805, edit func(shard int, rc *admin.CloudRegionConfig20240805)) *admin.ClusterDescription20240805 {
 	if cur == nil || !cur.HasReplicationSpecs() {
 		return nil
 	}
 
-	repl := cur.GetReplicationSpecs()
-	for i := range repl {
-		rcs := repl[i].GetRegionConfigs()
-		for j := range rcs {
-			if rcs[j].HasElectableSpecs() {
-				es := rcs[j].GetElectableSpecs()
-				es.SetInstanceSize(targetTier)
-				rcs[j].SetElectableSpecs(es)
-			}
-			if rcs[j].HasReadOnlySpecs() {
-				ros := rcs[j].GetReadOnlySpecs()
-				ros.SetInstanceSize(targetTier)
-				rcs[j].SetReadOnlySpecs(ros)
-			}
-			if rcs[j].HasAnalyticsSpecs() {
-				as := rcs[j].GetAnalyticsSpecs()
-				as.SetInstanceSize(targetTier)
-				rcs[j].SetAnalyticsSpecs(as)
-			}
+	cs := cur.GetReplicationSpecs()
+	repl := make([]admin.ReplicationSpec20240805, len(cs))
+	for i, spec := range cs {
+		rcs := make([]admin.CloudRegionConfig20240805, len(spec.GetRegionConfigs()))
+		for j, rc := range spec.GetRegionConfigs() {
+			rcs[j] = cloneRegionConfig(rc)
+			edit(i, &rcs[j])
 		}
-		repl[i].SetRegionConfigs(rcs)
+		spec.SetRegionConfigs(rcs)
+		repl[i] = spec
 	}
+	payload := admin.NewClusterDescription20240805()
 	payload.SetReplicationSpecs(repl)
 	return payload
 }
+
+// cloneRegionConfig copies the hardware and auto-scaling settings of a region config so they
+// can be changed without affecting the original. The SDK setters replace scalar pointers, so
+// copying each struct one level deep is enough.
+func cloneRegionConfig(rc admin.CloudRegionConfig20240805) admin.CloudRegionConfig20240805 {
+	rc.ElectableSpecs = clonePtr(rc.ElectableSpecs)
+	rc.ReadOnlySpecs = clonePtr(rc.ReadOnlySpecs)
+	rc.AnalyticsSpecs = clonePtr(rc.AnalyticsSpecs)
+	rc.AutoScaling = cloneAutoScaling(rc.AutoScaling)
+	rc.AnalyticsAutoScaling = cloneAutoScaling(rc.AnalyticsAutoScaling)
+	return rc
+}
+
+func cloneAutoScaling(a *admin.AdvancedAutoScalingSettings) *admin.AdvancedAutoScalingSettings {
+	a = clonePtr(a)
+	if a != nil {
+		a.Compute = clonePtr(a.Compute)
+		a.DiskGB = clonePtr(a.DiskGB)
+	}
+	return a
+}
+
+func clonePtr[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
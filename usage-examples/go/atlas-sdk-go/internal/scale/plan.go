@@ -0,0 +1,215 @@
+package scale
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"atlas-sdk-go/internal/data/export"
+	"atlas-sdk-go/internal/errors"
+
+	"go.mongodb.org/atlas-sdk/v20250219001/admin"
+)
+
+// FieldChange is a single setting that a plan changes.
+type FieldChange struct {
+	Field  string `json:"field"` // e.g. "shard[0].AWS/US_EAST_1.electable.instanceSize"
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// ClusterPlan lists the changes that a single UpdateCluster call makes to a cluster.
+type ClusterPlan struct {
+	ProjectID string        `json:"projectId"`
+	Cluster   string        `json:"cluster"`
+	Changes   []FieldChange `json:"changes"`
+	// Replanned is set on the plan ApplyPlan asks to confirm again when the cluster changed after
+	// the plan was built.
+	Replanned bool `json:"replanned,omitempty"`
+	payload   *admin.ClusterDescription20240805
+	desired   DesiredCluster
+}
+
+// Plan lists the clusters that differ from their desired state, in the order they are declared.
+type Plan struct {
+	Clusters []ClusterPlan `json:"clusters"`
+}
+
+// HasChanges reports whether any cluster needs to be updated.
+func (p *Plan) HasChanges() bool {
+	return len(p.Clusters) > 0
+}
+
+// Table returns the changes as rows with a header row.
+func (p *Plan) Table() [][]string {
+	rows := [][]string{{"Project", "Cluster", "Field", "Before", "After"}}
+	for _, c := range p.Clusters {
+		for _, f := range c.Changes {
+			rows = append(rows, []string{c.ProjectID, c.Cluster, f.Field, f.Before, f.After})
+		}
+	}
+	return rows
+}
+
+// WriteCSV writes the changes to a CSV file at filePath.
+func (p *Plan) WriteCSV(filePath string) error {
+	return export.ToCSV(p.Table(), filePath)
+}
+
+// WriteJSON writes the plan to a JSON file at filePath.
+func (p *Plan) WriteJSON(filePath string) error {
+	return export.ToJSON(p, filePath)
+}
+
+// BuildPlan reads the live description of each declared cluster and plans the changes that bring
+// it to its desired state. Clusters that already match are left out of the plan.
+func BuildPlan(ctx context.Context, sdk admin.ClustersApi, desired *DesiredState) (*Plan, error) {
+	if err := desired.Validate(); err != nil {
+		return nil, err
+	}
+	p := &Plan{}
+	for _, dc := range desired.Clusters {
+		cur, _, err := sdk.GetCluster(ctx, dc.ProjectID, dc.Name).Execute()
+		if err != nil {
+			return nil, errors.FormatError("get cluster", dc.Name, err)
+		}
+		cp, err := PlanCluster(dc, cur)
+		if err != nil {
+			return nil, err
+		}
+		if len(cp.Changes) > 0 {
+			p.Clusters = append(p.Clusters, *cp)
+		}
+	}
+	return p, nil
+}
+
+// PlanCluster compares a live cluster with its desired state. Each desired region applies to every
+// matching region config, in every shard unless the region names one. It returns a NotFoundError
+// if a desired region matches nothing in the cluster.
+func PlanCluster(desired DesiredCluster, cur *admin.ClusterDescription20240805) (*ClusterPlan, error) {
+	cp := &ClusterPlan{ProjectID: desired.ProjectID, Cluster: desired.Name, desired: desired}
+	matched := make([]bool, len(desired.Regions))
+	payload := buildPayload(cur, func(shard int, rc *admin.CloudRegionConfig20240805) {
+		prefix := fmt.Sprintf("shard[%d].%s/%s.", shard, rc.GetProviderName(), rc.GetRegionName())
+		for i, dr := range desired.Regions {
+			if dr.matches(shard, rc) {
+				matched[i] = true
+				cp.Changes = append(cp.Changes, dr.apply(prefix, rc)...)
+			}
+		}
+	})
+	if payload == nil {
+		return nil, &errors.ValidationError{Message: fmt.Sprintf("cluster %s has no replication specs", desired.Name)}
+	}
+	for i, ok := range matched {
+		if !ok {
+			r := desired.Regions[i]
+			return nil, &errors.NotFoundError{Resource: "cluster region", ID: fmt.Sprintf("%s %s/%s", desired.Name, r.Provider, r.Region)}
+		}
+	}
+	if len(cp.Changes) > 0 {
+		cp.payload = payload
+	}
+	return cp, nil
+}
+
+// matches reports whether the desired region applies to a region config in the given shard.
+func (d DesiredRegion) matches(shard int, rc *admin.CloudRegionConfig20240805) bool {
+	if d.Shard != nil && *d.Shard != shard {
+		return false
+	}
+	return strings.EqualFold(d.Provider, rc.GetProviderName()) && strings.EqualFold(d.Region, rc.GetRegionName())
+}
+
+// apply sets the desired values on rc and returns the fields that changed.
+func (d DesiredRegion) apply(prefix string, rc *admin.CloudRegionConfig20240805) []FieldChange {
+	var changes []FieldChange
+	if d.Electable != nil {
+		if rc.ElectableSpecs == nil {
+			rc.ElectableSpecs = &admin.HardwareSpec20240805{}
+		}
+		changes = append(changes, d.Electable.apply(prefix+"electable.", rc.ElectableSpecs)...)
+	}
+	if d.ReadOnly != nil {
+		if rc.ReadOnlySpecs == nil {
+			rc.ReadOnlySpecs = &admin.DedicatedHardwareSpec20240805{}
+		}
+		changes = append(changes, d.ReadOnly.apply(prefix+"readOnly.", rc.ReadOnlySpecs)...)
+	}
+	if d.Analytics != nil {
+		if rc.AnalyticsSpecs == nil {
+			rc.AnalyticsSpecs = &admin.DedicatedHardwareSpec20240805{}
+		}
+		changes = append(changes, d.Analytics.apply(prefix+"analytics.", rc.AnalyticsSpecs)...)
+	}
+	if d.AutoScaling != nil {
+		if rc.AutoScaling == nil {
+			rc.AutoScaling = &admin.AdvancedAutoScalingSettings{}
+		}
+		changes = append(changes, d.AutoScaling.apply(prefix+"autoScaling.", rc.AutoScaling)...)
+	}
+	return changes
+}
+
+// hardwareSpec is satisfied by both electable and read-only/analytics hardware specs.
+type hardwareSpec interface {
+	GetInstanceSize() string
+	SetInstanceSize(string)
+	GetNodeCount() int
+	SetNodeCount(int)
+	GetDiskSizeGB() float64
+	SetDiskSizeGB(float64)
+}
+
+func (n DesiredNodes) apply(prefix string, spec hardwareSpec) []FieldChange {
+	var changes []FieldChange
+	if n.InstanceSize != "" {
+		setField(&changes, prefix+"instanceSize", spec.GetInstanceSize(), n.InstanceSize, spec.SetInstanceSize)
+	}
+	if n.NodeCount != nil {
+		setField(&changes, prefix+"nodeCount", spec.GetNodeCount(), *n.NodeCount, spec.SetNodeCount)
+	}
+	if n.DiskSizeGB != nil {
+		setField(&changes, prefix+"diskSizeGB", spec.GetDiskSizeGB(), *n.DiskSizeGB, spec.SetDiskSizeGB)
+	}
+	return changes
+}
+
+func (a DesiredAutoScaling) apply(prefix string, s *admin.AdvancedAutoScalingSettings) []FieldChange {
+	var changes []FieldChange
+	if a.ComputeEnabled != nil || a.ScaleDownEnabled != nil || a.MinInstanceSize != "" || a.MaxInstanceSize != "" {
+		if s.Compute == nil {
+			s.Compute = &admin.AdvancedComputeAutoScaling{}
+		}
+		c := s.Compute
+		if a.ComputeEnabled != nil {
+			setField(&changes, prefix+"computeEnabled", c.GetEnabled(), *a.ComputeEnabled, c.SetEnabled)
+		}
+		if a.ScaleDownEnabled != nil {
+			setField(&changes, prefix+"scaleDownEnabled", c.GetScaleDownEnabled(), *a.ScaleDownEnabled, c.SetScaleDownEnabled)
+		}
+		if a.MinInstanceSize != "" {
+			setField(&changes, prefix+"minInstanceSize", c.GetMinInstanceSize(), a.MinInstanceSize, c.SetMinInstanceSize)
+		}
+		if a.MaxInstanceSize != "" {
+			setField(&changes, prefix+"maxInstanceSize", c.GetMaxInstanceSize(), a.MaxInstanceSize, c.SetMaxInstanceSize)
+		}
+	}
+	if a.DiskEnabled != nil {
+		if s.DiskGB == nil {
+			s.DiskGB = &admin.DiskGBAutoScaling{}
+		}
+		setField(&changes, prefix+"diskEnabled", s.DiskGB.GetEnabled(), *a.DiskEnabled, s.DiskGB.SetEnabled)
+	}
+	return changes
+}
+
+// setField records a change and applies it with set if the desired value differs from the current one.
+func setField[T comparable](changes *[]FieldChange, field string, before, after T, set func(T)) {
+	if before == after {
+		return
+	}
+	*changes = append(*changes, FieldChange{Field: field, Before: fmt.Sprint(before), After: fmt.Sprint(after)})
+	set(after)
+}
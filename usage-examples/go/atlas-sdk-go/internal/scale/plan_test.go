@@ -0,0 +1,253 @@
+package scale
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"atlas-sdk-go/internal/clusterutils"
+	internalerrors "atlas-sdk-go/internal/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/atlas-sdk/v20250219001/admin"
+)
+
+// shardedCluster returns a two-shard AWS cluster with compute auto-scaling between M30 and M60.
+func shardedCluster() *admin.ClusterDescription20240805 {
+	spec := func() admin.ReplicationSpec20240805 {
+		return admin.ReplicationSpec20240805{RegionConfigs: &[]admin.CloudRegionConfig20240805{{
+			ProviderName: admin.PtrString("AWS"),
+			RegionName:   admin.PtrString("US_EAST_1"),
+			ElectableSpecs: &admin.HardwareSpec20240805{
+				InstanceSize: admin.PtrString("M30"),
+				NodeCount:    admin.PtrInt(3),
+				DiskSizeGB:   admin.PtrFloat64(40),
+			},
+			AutoScaling: &admin.AdvancedAutoScalingSettings{Compute: &admin.AdvancedComputeAutoScaling{
+				Enabled:         admin.PtrBool(true),
+				MinInstanceSize: admin.PtrString("M30"),
+				MaxInstanceSize: admin.PtrString("M60"),
+			}},
+		}}}
+	}
+	return &admin.ClusterDescription20240805{
+		Name:             admin.PtrString("Cluster0"),
+		ReplicationSpecs: &[]admin.ReplicationSpec20240805{spec(), spec()},
+	}
+}
+
+func TestPlanCluster(t *testing.T) {
+	t.Parallel()
+	cur := shardedCluster()
+	desired := DesiredCluster{ProjectID: "proj1", Name: "Cluster0", Regions: []DesiredRegion{{
+		Provider:    "AWS",
+		Region:      "US_EAST_1",
+		Electable:   &DesiredNodes{InstanceSize: "M40", DiskSizeGB: admin.PtrFloat64(40)},
+		AutoScaling: &DesiredAutoScaling{MaxInstanceSize: "M80", DiskEnabled: admin.PtrBool(true)},
+	}}}
+
+	cp, err := PlanCluster(desired, cur)
+
+	require.NoError(t, err)
+	assert.Equal(t, []FieldChange{
+		{Field: "shard[0].AWS/US_EAST_1.electable.instanceSize", Before: "M30", After: "M40"},
+		{Field: "shard[0].AWS/US_EAST_1.autoScaling.maxInstanceSize", Before: "M60", After: "M80"},
+		{Field: "shard[0].AWS/US_EAST_1.autoScaling.diskEnabled", Before: "false", After: "true"},
+		{Field: "shard[1].AWS/US_EAST_1.electable.instanceSize", Before: "M30", After: "M40"},
+		{Field: "shard[1].AWS/US_EAST_1.autoScaling.maxInstanceSize", Before: "M60", After: "M80"},
+		{Field: "shard[1].AWS/US_EAST_1.autoScaling.diskEnabled", Before: "false", After: "true"},
+	}, cp.Changes)
+
+	rc := cp.payload.GetReplicationSpecs()[1].GetRegionConfigs()[0]
+	assert.Equal(t, "M40", rc.ElectableSpecs.GetInstanceSize())
+	assert.Equal(t, 3, rc.ElectableSpecs.GetNodeCount(), "unmanaged settings are kept")
+	assert.Equal(t, "M30", rc.AutoScaling.Compute.GetMinInstanceSize())
+	orig := cur.GetReplicationSpecs()[1].GetRegionConfigs()[0]
+	assert.Equal(t, "M30", orig.ElectableSpecs.GetInstanceSize(), "live cluster is not modified")
+	assert.Equal(t, "M60", orig.AutoScaling.Compute.GetMaxInstanceSize())
+}
+
+func TestPlanCluster_ShardAndErrors(t *testing.T) {
+	t.Parallel()
+	one := DesiredCluster{ProjectID: "proj1", Name: "Cluster0", Regions: []DesiredRegion{{
+		Provider: "aws", Region: "us_east_1", Shard: admin.PtrInt(1),
+		ReadOnly: &DesiredNodes{InstanceSize: "M30", NodeCount: admin.PtrInt(1)},
+	}}}
+	cp, err := PlanCluster(one, shardedCluster())
+	require.NoError(t, err)
+	require.Len(t, cp.Changes, 2)
+	assert.Equal(t, "shard[1].AWS/US_EAST_1.readOnly.nodeCount", cp.Changes[1].Field)
+	assert.Equal(t, "0", cp.Changes[1].Before)
+
+	same := DesiredCluster{ProjectID: "proj1", Name: "Cluster0", Regions: []DesiredRegion{{
+		Provider: "AWS", Region: "US_EAST_1", Electable: &DesiredNodes{InstanceSize: "M30", NodeCount: admin.PtrInt(3)},
+	}}}
+	cp, err = PlanCluster(same, shardedCluster())
+	require.NoError(t, err)
+	assert.Empty(t, cp.Changes)
+	assert.Nil(t, cp.payload)
+
+	missing := DesiredCluster{ProjectID: "proj1", Name: "Cluster0", Regions: []DesiredRegion{{
+		Provider: "AWS", Region: "EU_WEST_1", Electable: &DesiredNodes{InstanceSize: "M40"},
+	}}}
+	_, err = PlanCluster(missing, shardedCluster())
+	var nfErr *internalerrors.NotFoundError
+	assert.ErrorAs(t, err, &nfErr)
+}
+
+func TestLoadDesiredState_Validation(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	write := func(name, body string) string {
+		p := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(p, []byte(body), 0o600))
+		return p
+	}
+
+	d, err := LoadDesiredState(write("ok.json", `{"clusters":[{"projectId":"p","name":"c","regions":[{"provider":"AWS","region":"US_EAST_1"}]}]}`))
+	require.NoError(t, err)
+	assert.Len(t, d.Clusters, 1)
+
+	var vErr *internalerrors.ValidationError
+	_, err = LoadDesiredState(write("dup.json", `{"clusters":[{"projectId":"p","name":"c"},{"projectId":"p","name":"c"}]}`))
+	assert.ErrorAs(t, err, &vErr)
+	_, err = LoadDesiredState(write("region.json", `{"clusters":[{"projectId":"p","name":"c","regions":[{"provider":"AWS"}]}]}`))
+	assert.ErrorAs(t, err, &vErr)
+
+	var nfErr *internalerrors.NotFoundError
+	_, err = LoadDesiredState(filepath.Join(dir, "missing.json"))
+	assert.ErrorAs(t, err, &nfErr)
+}
+
+// newClusterServer serves GetCluster from live, keyed by cluster name, and records the clusters
+// updated. Clusters are IDLE except for one UPDATING read after each update.
+func newClusterServer(t *testing.T, live map[string]*admin.ClusterDescription20240805) (*admin.APIClient, func() []string) {
+	t.Helper()
+	var mu sync.Mutex
+	var updates []string
+	updating := map[string]bool{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Method == http.MethodPatch {
+			var body admin.ClusterDescription20240805
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Len(t, body.GetReplicationSpecs(), 2)
+			updates = append(updates, name)
+			live[name] = &body
+			updating[name] = true
+		}
+		state := clusterutils.StateIdle
+		if r.Method == http.MethodGet && updating[name] {
+			state, updating[name] = clusterutils.StateUpdating, false
+		}
+		cur := *live[name]
+		cur.Name, cur.StateName = admin.PtrString(name), admin.PtrString(state)
+		w.Header().Set("Content-Type", "application/json")
+		assert.NoError(t, json.NewEncoder(w).Encode(cur))
+	}))
+	t.Cleanup(server.Close)
+	client, err := admin.NewClient(admin.UseBaseURL(server.URL))
+	require.NoError(t, err)
+	return client, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return slices.Clone(updates)
+	}
+}
+
+// planM40 plans an electable instance size of M40 for each named cluster against shardedCluster.
+func planM40(t *testing.T, names ...string) *Plan {
+	t.Helper()
+	plan := &Plan{}
+	for _, name := range names {
+		cp, err := PlanCluster(DesiredCluster{ProjectID: "proj1", Name: name, Regions: []DesiredRegion{{
+			Provider: "AWS", Region: "US_EAST_1", Electable: &DesiredNodes{InstanceSize: "M40"},
+		}}}, shardedCluster())
+		require.NoError(t, err)
+		plan.Clusters = append(plan.Clusters, *cp)
+	}
+	return plan
+}
+
+func TestApplyPlan(t *testing.T) {
+	t.Parallel()
+	client, updates := newClusterServer(t, map[string]*admin.ClusterDescription20240805{"a": shardedCluster(), "b": shardedCluster()})
+	plan := planM40(t, "a", "b")
+	opts := ApplyOptions{
+		Confirm: func(cp ClusterPlan) bool { return cp.Cluster == "a" },
+		Wait:    clusterutils.WaitOptions{InitialInterval: time.Millisecond, Timeout: time.Second},
+	}
+
+	results, err := ApplyPlan(context.Background(), client.ClustersApi, plan, opts)
+
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.True(t, results[0].Applied)
+	assert.True(t, results[1].Skipped)
+	assert.Equal(t, []string{"a"}, updates())
+
+	var loaded Plan
+	data, err := json.Marshal(plan)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &loaded))
+	_, err = ApplyPlan(context.Background(), client.ClustersApi, &loaded, opts)
+	var vErr *internalerrors.ValidationError
+	assert.ErrorAs(t, err, &vErr, "a plan read from JSON has no payloads")
+}
+
+func TestApplyPlan_ClusterChangedAfterPlan(t *testing.T) {
+	t.Parallel()
+	wait := clusterutils.WaitOptions{InitialInterval: time.Millisecond, Timeout: time.Second}
+	// Auto-scaling moved "a" to M50 and someone else already set "b" to M40 after the plan was built
+	changed := func() map[string]*admin.ClusterDescription20240805 {
+		a, b := shardedCluster(), shardedCluster()
+		for _, spec := range a.GetReplicationSpecs() {
+			spec.GetRegionConfigs()[0].ElectableSpecs.InstanceSize = admin.PtrString("M50")
+		}
+		for _, spec := range b.GetReplicationSpecs() {
+			spec.GetRegionConfigs()[0].ElectableSpecs.InstanceSize = admin.PtrString("M40")
+		}
+		return map[string]*admin.ClusterDescription20240805{"a": a, "b": b}
+	}
+
+	// Without Confirm, a changed plan stops the rollout
+	client, updates := newClusterServer(t, changed())
+	results, err := ApplyPlan(context.Background(), client.ClustersApi, planM40(t, "a", "b"), ApplyOptions{Wait: wait})
+	var vErr *internalerrors.ValidationError
+	require.ErrorAs(t, err, &vErr)
+	require.Len(t, results, 1)
+	assert.False(t, results[0].Applied)
+	assert.Empty(t, updates())
+
+	// With Confirm, the new plan is confirmed again and a cluster that already matches is skipped
+	live := changed()
+	client, updates = newClusterServer(t, live)
+	var asked []ClusterPlan
+	opts := ApplyOptions{Wait: wait, Confirm: func(cp ClusterPlan) bool {
+		asked = append(asked, cp)
+		return true
+	}}
+	results, err = ApplyPlan(context.Background(), client.ClustersApi, planM40(t, "a", "b"), opts)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.True(t, results[0].Applied)
+	assert.True(t, results[1].Skipped)
+	assert.Equal(t, []string{"a"}, updates())
+
+	require.Len(t, asked, 3, "a, a again with the new plan, then b")
+	assert.False(t, asked[0].Replanned)
+	assert.True(t, asked[1].Replanned)
+	assert.Equal(t, "a", asked[1].Cluster)
+	assert.Equal(t, FieldChange{Field: "shard[0].AWS/US_EAST_1.electable.instanceSize", Before: "M50", After: "M40"}, asked[1].Changes[0])
+}